@@ -0,0 +1,155 @@
+// Package canonicaljson implements a deterministic JSON encoding that matches
+// the output of json-c's json_object_to_json_string(), as used by the CT
+// server when it stores XJSON log entries.
+//
+// Objects are rendered as `{ "k": v, "k2": v2 }` and arrays as `[ v, v2 ]`.
+// Object members keep the order they appear in the input, as json-c keeps
+// them in insertion order, and strings are escaped as json-c does (including
+// `\/`).  Numbers are written as they appear in the input; json-c re-renders
+// non-integral numbers (e.g. 0.1 becomes 0.10000000000000001), so documents
+// containing them may not match the server's encoding.
+package canonicaljson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+const hexChars = "0123456789abcdef"
+
+// Marshal returns the canonical JSON encoding of |v|.  |v| is first encoded
+// with encoding/json, so struct tags, field order and json.Marshaler
+// implementations are honoured, and map keys are sorted.
+func Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Canonicalize(data)
+}
+
+// Canonicalize re-encodes the JSON document in |data| in canonical form.
+func Canonicalize(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var buf bytes.Buffer
+	if err := encodeValue(&buf, dec); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err == nil {
+		return nil, errors.New("trailing data after JSON value")
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeValue reads the next JSON value from |dec| and writes its canonical
+// encoding to |buf|.
+func encodeValue(buf *bytes.Buffer, dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch t := tok.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		if t {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case json.Number:
+		buf.WriteString(string(t))
+	case string:
+		encodeString(buf, t)
+	case json.Delim:
+		switch t {
+		case '[':
+			buf.WriteByte('[')
+			for i := 0; dec.More(); i++ {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				buf.WriteByte(' ')
+				if err := encodeValue(buf, dec); err != nil {
+					return err
+				}
+			}
+			buf.WriteString(" ]")
+		case '{':
+			buf.WriteByte('{')
+			for i := 0; dec.More(); i++ {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				buf.WriteByte(' ')
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				encodeString(buf, key.(string))
+				buf.WriteString(": ")
+				if err := encodeValue(buf, dec); err != nil {
+					return err
+				}
+			}
+			buf.WriteString(" }")
+		default:
+			return fmt.Errorf("unexpected delimiter %v", t)
+		}
+		// Consume the closing delimiter.
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unexpected JSON token %T", t)
+	}
+	return nil
+}
+
+// encodeString writes |s| as a quoted JSON string using json-c's escaping
+// rules: control characters, '"', '\\' and '/' are escaped, and everything
+// else (including non-ASCII UTF-8) is written verbatim.
+func encodeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			buf.WriteRune(r)
+			i += size
+			continue
+		}
+		switch c {
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '/':
+			buf.WriteString(`\/`)
+		default:
+			if c < ' ' {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexChars[c>>4])
+				buf.WriteByte(hexChars[c&0xf])
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+		i++
+	}
+	buf.WriteByte('"')
+}
@@ -0,0 +1,76 @@
+package canonicaljson
+
+import (
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	var tests = []struct {
+		in   interface{}
+		want string
+	}{
+		{nil, `null`},
+		{true, `true`},
+		{false, `false`},
+		{42, `42`},
+		{-1.5, `-1.5`},
+		{"data", `"data"`},
+		{"a:b,c{d}e", `"a:b,c{d}e"`},
+		{"http://example.com/", `"http:\/\/example.com\/"`},
+		{"q\"b\\n\nt\tc\x01", `"q\"b\\n\nt\tc\u0001"`},
+		{"<&>", `"<&>"`},
+		{"ԱԲաբ", `"ԱԲաբ"`},
+		{[]int{}, `[ ]`},
+		{[]int{1, 2, 3}, `[ 1, 2, 3 ]`},
+		{map[string]int{}, `{ }`},
+		{map[string]interface{}{"b": []string{"x, y"}, "a": map[string]int{"z": 1}}, `{ "a": { "z": 1 }, "b": [ "x, y" ] }`},
+		{struct {
+			Data string `json:"data"`
+		}{"d"}, `{ "data": "d" }`},
+		{struct{ B, A int }{1, 2}, `{ "B": 1, "A": 2 }`},
+		{map[string]interface{}{"data": struct{ Z, Y string }{"/", "<&>"}}, `{ "data": { "Z": "\/", "Y": "<&>" } }`},
+	}
+	for _, test := range tests {
+		got, err := Marshal(test.in)
+		if err != nil {
+			t.Errorf("Marshal(%v)=nil,%v; want %q,nil", test.in, err, test.want)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("Marshal(%v)=%q; want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	var tests = []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: `{"b":1,"a":[true,null]}`, want: `{ "b": 1, "a": [ true, null ] }`},
+		{in: `{"a":{},"b":[],"c":[{}]}`, want: `{ "a": { }, "b": [ ], "c": [ { } ] }`},
+		{in: ` { "x" : 1.000 } `, want: `{ "x": 1.000 }`},
+		{in: `12345678901234567890`, want: `12345678901234567890`},
+		{in: `"/"`, want: `"\/"`},
+		{in: `{"a":`, wantErr: true},
+		{in: `{} {}`, wantErr: true},
+		{in: `[1 2]`, wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := Canonicalize([]byte(test.in))
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("Canonicalize(%q)=%q,nil; want error", test.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Canonicalize(%q)=nil,%v; want %q,nil", test.in, err, test.want)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("Canonicalize(%q)=%q; want %q", test.in, got, test.want)
+		}
+	}
+}
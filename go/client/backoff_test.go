@@ -0,0 +1,21 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffJitter(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 8 * time.Second, Multiplier: 2, Jitter: 0.5}
+	for retry := 1; retry <= 6; retry++ {
+		max := time.Second << uint(retry-1)
+		if max > p.MaxBackoff {
+			max = p.MaxBackoff
+		}
+		for i := 0; i < 100; i++ {
+			if d := p.backoff(retry); d < max/2 || d > max {
+				t.Fatalf("backoff(%d)=%v, want in [%v, %v]", retry, d, max/2, max)
+			}
+		}
+	}
+}
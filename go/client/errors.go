@@ -0,0 +1,70 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RspError is returned when a log's HTTP response can't be used, either
+// because the log rejected the request (a non-200 status code) or because the
+// response body was malformed. It holds the details of the response so that
+// callers can diagnose the problem; |Err| describes what went wrong and will
+// be one of JSONError, HashLengthError or DigitallySignedError if the log
+// returned 200 but its response could not be parsed.
+// Errors which occur before a response is received (e.g. network failures or
+// context expiry) are not RspErrors.
+type RspError struct {
+	Err        error  // the underlying error
+	Endpoint   string // the log endpoint which was called, e.g. AddChainPath
+	StatusCode int    // the HTTP status code of the response
+	Body       []byte // the body of the response
+
+	header http.Header // the headers of the response, used when retrying
+}
+
+func (e RspError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Endpoint, e.Err)
+}
+
+// JSONError indicates that a log's response could not be parsed as the
+// expected JSON structure.
+type JSONError struct {
+	Err error
+}
+
+func (e JSONError) Error() string {
+	return fmt.Sprintf("failed to parse JSON response: %v", e.Err)
+}
+
+// HashLengthError indicates that a hash in a log's response had the wrong
+// length.
+type HashLengthError struct {
+	Field string // the name of the JSON field containing the hash
+	Got   int
+	Want  int
+}
+
+func (e HashLengthError) Error() string {
+	return fmt.Sprintf("%s is invalid length, expected %d got %d", e.Field, e.Want, e.Got)
+}
+
+// DigitallySignedError indicates that a signature in a log's response could
+// not be decoded as a TLS DigitallySigned structure.
+type DigitallySignedError struct {
+	Err error
+}
+
+func (e DigitallySignedError) Error() string {
+	return fmt.Sprintf("failed to unmarshal DigitallySigned: %v", e.Err)
+}
+
+// SignatureVerificationError is returned when a log responds with an STH or
+// SCT whose signature doesn't verify under the log's public key.
+type SignatureVerificationError struct {
+	Endpoint string // the log endpoint which returned the bad signature
+	Err      error  // the reason verification failed
+}
+
+func (e SignatureVerificationError) Error() string {
+	return fmt.Sprintf("signature verification failed for %s: %v", e.Endpoint, e.Err)
+}
@@ -0,0 +1,172 @@
+package client_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+	"github.com/google/certificate-transparency/go/ctfake"
+	"github.com/google/certificate-transparency/go/testonly"
+	"golang.org/x/net/context"
+)
+
+func TestRspErrors(t *testing.T) {
+	ctx := context.Background()
+	getSTH := func(c *client.LogClient) error {
+		_, err := c.GetSTHWithContext(ctx)
+		return err
+	}
+	addChain := func(c *client.LogClient) error {
+		_, err := c.AddChainWithContext(ctx, []ct.ASN1Cert{testonly.ReadPEM(t, "google-cert.pem")})
+		return err
+	}
+	getConsistency := func(c *client.LogClient) error {
+		_, err := c.GetSTHConsistency(ctx, 1, 2)
+		return err
+	}
+	getProof := func(c *client.LogClient) error {
+		_, err := c.GetProofByHash(ctx, make([]byte, 32), 2)
+		return err
+	}
+	getEntries := func(c *client.LogClient) error {
+		_, err := c.GetEntriesWithContext(ctx, 0, 1)
+		return err
+	}
+
+	// The log is empty, so it rejects every request for its contents.
+	l := newLog(t)
+	defer l.Close()
+	down := newLog(t)
+	defer down.Close()
+	down.SetMisbehaviour(ctfake.Misbehaviour{Unavailable: 100})
+
+	tests := []struct {
+		desc     string
+		uri      string
+		call     func(*client.LogClient) error
+		status   int
+		endpoint string
+	}{
+		{"get-sth 404", l.URL() + "/missing", getSTH, 404, client.GetSTHPath},
+		{"get-sth 503", down.URL(), getSTH, 503, client.GetSTHPath},
+		{"add-chain rejected", l.URL(), addChain, 400, client.AddChainPath},
+		{"get-sth-consistency beyond tree", l.URL(), getConsistency, 400, client.GetSTHConsistencyPath},
+		{"get-proof-by-hash beyond tree", l.URL(), getProof, 400, client.GetProofByHashPath},
+		{"get-entries beyond tree", l.URL(), getEntries, 400, client.GetEntriesPath},
+	}
+	for _, test := range tests {
+		err := test.call(client.New(test.uri, &http.Client{}))
+		rspErr, ok := err.(client.RspError)
+		if !ok {
+			t.Errorf("%s: got error %v (%T), want RspError", test.desc, err, err)
+			continue
+		}
+		if rspErr.StatusCode != test.status {
+			t.Errorf("%s: StatusCode=%d, want %d", test.desc, rspErr.StatusCode, test.status)
+		}
+		if len(rspErr.Body) == 0 {
+			t.Errorf("%s: Body is empty, want the log's error message", test.desc)
+		}
+		if rspErr.Endpoint != test.endpoint {
+			t.Errorf("%s: Endpoint=%q, want %q", test.desc, rspErr.Endpoint, test.endpoint)
+		}
+	}
+}
+
+// A ctfake log never sends malformed responses, so these come from a canned
+// test server.
+func TestMalformedResponseErrors(t *testing.T) {
+	ctx := context.Background()
+	getSTH := func(c *client.LogClient) error {
+		_, err := c.GetSTHWithContext(ctx)
+		return err
+	}
+	addChain := func(c *client.LogClient) error {
+		_, err := c.AddChainWithContext(ctx, []ct.ASN1Cert{testonly.ReadPEM(t, "test-cert.pem")})
+		return err
+	}
+	getConsistency := func(c *client.LogClient) error {
+		_, err := c.GetSTHConsistency(ctx, 1, 2)
+		return err
+	}
+	getProof := func(c *client.LogClient) error {
+		_, err := c.GetProofByHash(ctx, []byte("hash"), 2)
+		return err
+	}
+	addJSON := func(c *client.LogClient) error {
+		_, err := c.AddJSONWithContext(ctx, "data")
+		return err
+	}
+	sctBody := func(id, sig string) string {
+		return fmt.Sprintf(`{"sct_version":0,"id":"%s","timestamp":1337,"extensions":"","signature":"%s"}`, id, sig)
+	}
+	const validID = "KHYaGJAn++880NYaAY12sFBXKcenQRvMvfYE9F1CYVM="
+	const validSig = "BAMARjBEAiAIc21J5ZbdKZHw5wLxCP+MhBEsV5+nfvGyakOIv6FOvAIgWYMZb6Pw///uiNM7QTg2Of1OqmK1GbeGuEl9VJN8v8c="
+
+	tests := []struct {
+		desc     string
+		call     func(*client.LogClient) error
+		body     string
+		endpoint string
+		wantErr  interface{} // the expected type of RspError.Err
+	}{
+		{"get-sth bad JSON", getSTH, "not json", client.GetSTHPath, client.JSONError{}},
+		{"get-sth short hash", getSTH,
+			fmt.Sprintf(`{"tree_size":1,"timestamp":1,"sha256_root_hash":"AAAA","tree_head_signature":"%s"}`, client.ValidSTHResponseTreeHeadSignature),
+			client.GetSTHPath, client.HashLengthError{}},
+		{"get-sth bad signature", getSTH,
+			fmt.Sprintf(`{"tree_size":1,"timestamp":1,"sha256_root_hash":"%s","tree_head_signature":"AAAA"}`, client.ValidSTHResponseSHA256RootHash),
+			client.GetSTHPath, client.DigitallySignedError{}},
+		{"add-chain bad JSON", addChain, "{", client.AddChainPath, client.JSONError{}},
+		{"add-chain short log ID", addChain, sctBody("AAAA", validSig), client.AddChainPath, client.HashLengthError{}},
+		{"add-chain bad signature", addChain, sctBody(validID, "AAAA"), client.AddChainPath, client.DigitallySignedError{}},
+		{"add-json bad JSON", addJSON, "[", client.AddJSONPath, client.JSONError{}},
+		{"get-sth-consistency bad hash", getConsistency, `{"consistency":["AAAA"]}`, client.GetSTHConsistencyPath, client.HashLengthError{}},
+		{"get-proof-by-hash bad hash", getProof, `{"leaf_index":1,"audit_path":["AAAA"]}`, client.GetProofByHashPath, client.HashLengthError{}},
+	}
+
+	for _, test := range tests {
+		hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(test.body))
+		}))
+		err := test.call(client.New(hs.URL, &http.Client{}))
+		hs.Close()
+
+		rspErr, ok := err.(client.RspError)
+		if !ok {
+			t.Errorf("%s: got error %v (%T), want RspError", test.desc, err, err)
+			continue
+		}
+		if rspErr.StatusCode != http.StatusOK {
+			t.Errorf("%s: StatusCode=%d, want %d", test.desc, rspErr.StatusCode, http.StatusOK)
+		}
+		if !bytes.Equal(rspErr.Body, []byte(test.body)) {
+			t.Errorf("%s: Body=%q, want %q", test.desc, rspErr.Body, test.body)
+		}
+		if rspErr.Endpoint != test.endpoint {
+			t.Errorf("%s: Endpoint=%q, want %q", test.desc, rspErr.Endpoint, test.endpoint)
+		}
+		if got, want := reflect.TypeOf(rspErr.Err), reflect.TypeOf(test.wantErr); got != want {
+			t.Errorf("%s: Err=%v (%v), want %v", test.desc, rspErr.Err, got, want)
+		}
+	}
+}
+
+func TestTransportErrorIsNotRspError(t *testing.T) {
+	l := newLog(t)
+	uri := l.URL()
+	l.Close()
+
+	_, err := client.New(uri, &http.Client{}).GetSTH()
+	if err == nil {
+		t.Fatal("GetSTH() from closed log succeeded, want error")
+	}
+	if _, ok := err.(client.RspError); ok {
+		t.Fatalf("GetSTH() from closed log returned RspError %v, want transport error", err)
+	}
+}
@@ -0,0 +1,59 @@
+package client_test
+
+// Tests which run against a ctfake log live in this external test package,
+// since ctfake imports this package through server.
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+	"github.com/google/certificate-transparency/go/ctfake"
+	"github.com/google/certificate-transparency/go/testonly"
+)
+
+// newLog returns a ctfake log which accepts chains issued by ca-cert.pem.
+func newLog(t *testing.T) *ctfake.Log {
+	l, err := ctfake.NewLog(ctfake.Config{Roots: []ct.ASN1Cert{testonly.ReadPEM(t, "ca-cert.pem")}})
+	if err != nil {
+		t.Fatalf("NewLog()=%v", err)
+	}
+	return l
+}
+
+// newVerifyingClient returns a client for |l| which checks signatures
+// against the public key of |keyLog|.
+func newVerifyingClient(t *testing.T, l, keyLog *ctfake.Log) *client.LogClient {
+	c, err := client.NewWithPubKey(l.URL(), &http.Client{}, keyLog.PublicKeyPEM())
+	if err != nil {
+		t.Fatalf("NewWithPubKey()=%v", err)
+	}
+	return c
+}
+
+// testChain returns a chain, which newLog's logs accept, for the named
+// certificate issued by ca-cert.pem.
+func testChain(t *testing.T, name string) []ct.ASN1Cert {
+	return []ct.ASN1Cert{testonly.ReadPEM(t, name), testonly.ReadPEM(t, "ca-cert.pem")}
+}
+
+// recordingTransport counts the requests made through it, and records the
+// range of each get-entries request.
+type recordingTransport struct {
+	mu       sync.Mutex
+	attempts int
+	requests []string
+}
+
+func (rt *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.attempts++
+	if r.URL.Path == client.GetEntriesPath {
+		rt.requests = append(rt.requests, fmt.Sprintf("%s-%s", r.URL.Query().Get("start"), r.URL.Query().Get("end")))
+	}
+	rt.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(r)
+}
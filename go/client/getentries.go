@@ -1,13 +1,13 @@
 package client
 
 import (
-	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
 
 	ct "github.com/google/certificate-transparency/go"
 	"golang.org/x/net/context"
@@ -26,63 +26,309 @@ type GetEntriesResponse struct {
 
 // GetRawEntries exposes the /ct/v1/get-entries result with only the JSON parsing done.
 func GetRawEntries(ctx context.Context, httpClient *http.Client, logURL string, start, end int64) (*GetEntriesResponse, error) {
+	params, err := getEntriesParams(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp GetEntriesResponse
+	if _, _, err := fetchAndParse(ctx, httpClient, logURL, GetEntriesPath, params, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// getEntriesParams returns the query parameters for a get-entries request
+// for the entries [|start|, |end|].
+func getEntriesParams(start, end int64) (url.Values, error) {
 	if end < 0 {
 		return nil, errors.New("end should be >= 0")
 	}
 	if end < start {
 		return nil, errors.New("start should be <= end")
 	}
+	return url.Values{
+		"start": []string{strconv.FormatInt(start, 10)},
+		"end":   []string{strconv.FormatInt(end, 10)},
+	}, nil
+}
+
+// GetEntries attempts to retrieve the entries in the sequence [|start|, |end|] from the CT log server. (see section 4.6.)
+// Returns a slice of LeafInputs or a non-nil error.
+func (c *LogClient) GetEntries(start, end int64) ([]ct.LogEntry, error) {
+	return c.GetEntriesWithContext(context.TODO(), start, end)
+}
 
-	baseURL, err := url.Parse(strings.TrimRight(logURL, "/") + GetEntriesPath)
+// GetEntriesWithContext attempts to retrieve the entries in the sequence
+// [|start|, |end|] from the CT log server, and fails if the provided context
+// expires before the entries are retrieved. (see section 4.6.)
+// Returns a slice of LeafInputs or a non-nil error.
+func (c *LogClient) GetEntriesWithContext(ctx context.Context, start, end int64) ([]ct.LogEntry, error) {
+	params, err := getEntriesParams(start, end)
 	if err != nil {
 		return nil, err
 	}
+	var resp GetEntriesResponse
+	if _, _, err := c.fetch(ctx, GetEntriesPath, params, &resp); err != nil {
+		return nil, err
+	}
+	entries := make([]ct.LogEntry, len(resp.Entries))
+	for index, entry := range resp.Entries {
+		logEntry, err := entry.logEntry(start + int64(index))
+		if err != nil {
+			return nil, err
+		}
+		entries[index] = *logEntry
+	}
+	return entries, nil
+}
 
-	baseURL.RawQuery = url.Values{
-		"start": []string{strconv.FormatInt(start, 10)},
-		"end":   []string{strconv.FormatInt(end, 10)},
-	}.Encode()
+// logEntry decodes the leaf entry, which is at |index| in the log. The
+// entry's certificate isn't parsed, so that one the x509 package can't handle
+// doesn't prevent the rest of the entries from being fetched.
+func (entry *LeafEntry) logEntry(index int64) (*ct.LogEntry, error) {
+	return ct.RawLogEntryFromLeaf(index, entry.LeafInput, entry.ExtraData)
+}
 
-	var resp GetEntriesResponse
-	err = fetchAndParse(context.TODO(), httpClient, baseURL.String(), &resp)
+// StreamEntries retrieves the entries in the sequence [|start|, |end|] from
+// the CT log server, as for GetEntriesWithContext, but rather than holding
+// the whole response in memory it decodes the entries one at a time as they
+// arrive and passes each to |fn|. The log may return fewer entries than were
+// asked for. If |fn| returns an error then no further entries are decoded and
+// the error is returned.
+// Only the initial request is retried according to the client's RetryPolicy;
+// once entries have been passed to |fn| failures are returned to the caller.
+// Returns the number of entries passed to |fn|.
+func (c *LogClient) StreamEntries(ctx context.Context, start, end int64, fn func(*ct.LogEntry) error) (int64, error) {
+	s, err := c.openEntryStream(ctx, start, end)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
+	defer s.Close()
+	var n int64
+	for {
+		entry, err := s.next()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if err := fn(entry); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
 
-	return &resp, nil
+// entryStream decodes the entries in a get-entries response one at a time.
+type entryStream struct {
+	body  io.ReadCloser
+	dec   *json.Decoder
+	index int64 // the log index of the next entry
+	end   int64 // the last index which was requested
+	// Whether the decoder is positioned within the "entries" array.
+	inEntries bool
 }
 
-// GetEntries attempts to retrieve the entries in the sequence [|start|, |end|] from the CT log server. (see section 4.6.)
-// Returns a slice of LeafInputs or a non-nil error.
-func (c *LogClient) GetEntries(start, end int64) ([]ct.LogEntry, error) {
-	resp, err := GetRawEntries(context.TODO(), c.httpClient, c.uri, start, end)
+// openEntryStream makes a get-entries request for [|start|, |end|], retrying
+// as directed by the client's RetryPolicy until the log responds with a 200.
+// The caller must Close the returned entryStream.
+func (c *LogClient) openEntryStream(ctx context.Context, start, end int64) (*entryStream, error) {
+	params, err := getEntriesParams(start, end)
 	if err != nil {
 		return nil, err
 	}
-	entries := make([]ct.LogEntry, len(resp.Entries))
-	for index, entry := range resp.Entries {
-		leaf, err := ct.ReadMerkleTreeLeaf(bytes.NewBuffer(entry.LeafInput))
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var resp *http.Response
+	err = c.retry(ctx, c.getPolicy(), func() error {
+		var err error
+		resp, err = doGet(ctx, c.httpClient, c.uri, GetEntriesPath, params)
 		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			body, err := readBody(resp)
+			if err != nil {
+				return err
+			}
+			return statusError(GetEntriesPath, resp, body)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entryStream{
+		body:  resp.Body,
+		dec:   json.NewDecoder(resp.Body),
+		index: start,
+		end:   end,
+	}, nil
+}
+
+// jsonError returns an RspError for a response whose body could not be
+// decoded. The body is not included, as it has not been held in memory.
+func (s *entryStream) jsonError(err error) error {
+	return RspError{Err: JSONError{err}, Endpoint: GetEntriesPath, StatusCode: http.StatusOK}
+}
+
+// next returns the next entry in the response, or io.EOF if there are no
+// more. Any entries beyond those which were requested are ignored.
+func (s *entryStream) next() (*ct.LogEntry, error) {
+	if !s.inEntries {
+		if err := s.findEntries(); err != nil {
 			return nil, err
 		}
-		entries[index].Leaf = *leaf
+	}
+	if s.index > s.end || !s.dec.More() {
+		return nil, io.EOF
+	}
+	var entry LeafEntry
+	if err := s.dec.Decode(&entry); err != nil {
+		return nil, s.jsonError(err)
+	}
+	logEntry, err := entry.logEntry(s.index)
+	if err != nil {
+		return nil, err
+	}
+	s.index++
+	return logEntry, nil
+}
+
+// findEntries advances the decoder to the start of the "entries" array,
+// skipping any other fields in the response. Returns io.EOF if the response
+// has no "entries" field.
+func (s *entryStream) findEntries() error {
+	if err := s.expectDelim('{'); err != nil {
+		return err
+	}
+	for s.dec.More() {
+		t, err := s.dec.Token()
+		if err != nil {
+			return s.jsonError(err)
+		}
+		if t == "entries" {
+			if err := s.expectDelim('['); err != nil {
+				return err
+			}
+			s.inEntries = true
+			return nil
+		}
+		var skip json.RawMessage
+		if err := s.dec.Decode(&skip); err != nil {
+			return s.jsonError(err)
+		}
+	}
+	return io.EOF
+}
+
+func (s *entryStream) expectDelim(want json.Delim) error {
+	t, err := s.dec.Token()
+	if err != nil {
+		return s.jsonError(err)
+	}
+	if t != want {
+		return s.jsonError(fmt.Errorf("got %v, want %v", t, want))
+	}
+	return nil
+}
 
-		var chain []ct.ASN1Cert
-		switch leaf.TimestampedEntry.EntryType {
-		case ct.X509LogEntryType:
-			chain, err = ct.UnmarshalX509ChainArray(entry.ExtraData)
+// Close releases the connection used by the entryStream.
+func (s *entryStream) Close() error {
+	return s.body.Close()
+}
 
-		case ct.PrecertLogEntryType:
-			chain, err = ct.UnmarshalPrecertChainArray(entry.ExtraData)
+// EntryIterator iterates over a range of entries in a log, decoding them one
+// at a time and making further get-entries requests as needed when the log
+// returns fewer entries than were asked for. Use it like a bufio.Scanner:
+//
+//	it := c.NewEntryIterator(ctx, 0, 9999, 1000)
+//	defer it.Close()
+//	for it.Next() {
+//		entry := it.Entry()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type EntryIterator struct {
+	c         *LogClient
+	ctx       context.Context
+	next      int64 // the index of the next entry to return
+	end       int64
+	batchSize int64
+	stream    *entryStream
+	streamed  int64 // the number of entries read from stream
+	entry     *ct.LogEntry
+	err       error
+}
 
-		default:
-			return nil, fmt.Errorf("saw unknown entry type: %v", leaf.TimestampedEntry.EntryType)
+// NewEntryIterator returns an EntryIterator over the entries in the sequence
+// [|start|, |end|], which asks the log for at most |batchSize| entries per
+// request (or the whole range, if |batchSize| <= 0).
+func (c *LogClient) NewEntryIterator(ctx context.Context, start, end, batchSize int64) *EntryIterator {
+	if batchSize <= 0 {
+		batchSize = end - start + 1
+	}
+	return &EntryIterator{c: c, ctx: ctx, next: start, end: end, batchSize: batchSize}
+}
+
+// Next advances the iterator to the next entry, which is then available
+// through Entry. Returns false when there are no more entries in the range,
+// or an error occurs.
+func (it *EntryIterator) Next() bool {
+	it.entry = nil
+	for it.err == nil && it.next <= it.end {
+		if it.stream == nil {
+			end := it.next + it.batchSize - 1
+			if end > it.end {
+				end = it.end
+			}
+			it.stream, it.err = it.c.openEntryStream(it.ctx, it.next, end)
+			it.streamed = 0
+			continue
+		}
+		entry, err := it.stream.next()
+		if err == io.EOF {
+			it.stream.Close()
+			it.stream = nil
+			if it.streamed == 0 {
+				it.err = fmt.Errorf("log returned no entries starting at %d", it.next)
+			}
+			continue
 		}
 		if err != nil {
-			return nil, err
+			it.err = err
+			break
 		}
-		entries[index].Chain = chain
-		entries[index].Index = start + int64(index)
+		it.streamed++
+		it.next++
+		it.entry = entry
+		return true
+	}
+	it.Close()
+	return false
+}
+
+// Entry returns the entry found by the last call to Next.
+func (it *EntryIterator) Entry() *ct.LogEntry {
+	return it.entry
+}
+
+// Err returns the error, if any, which stopped the iteration.
+func (it *EntryIterator) Err() error {
+	return it.err
+}
+
+// Close releases any connection held by the iterator. It is safe to call
+// Close more than once, and it need not be called if Next has returned false.
+func (it *EntryIterator) Close() {
+	if it.stream != nil {
+		it.stream.Close()
+		it.stream = nil
 	}
-	return entries, nil
 }
@@ -0,0 +1,168 @@
+package client_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+	"github.com/google/certificate-transparency/go/ctfake"
+	"golang.org/x/net/context"
+)
+
+// entriesLog returns a ctfake log holding |size| JSON entries, each recording
+// its own index, which returns at most |maxEntries| entries per get-entries
+// request, and a client for it which records its get-entries requests.
+func entriesLog(t *testing.T, size, maxEntries int) (*ctfake.Log, *client.LogClient, *recordingTransport) {
+	l := newLog(t)
+	for i := 0; i < size; i++ {
+		if _, err := l.AddJSON(i); err != nil {
+			l.Close()
+			t.Fatalf("AddJSON(%d)=%v", i, err)
+		}
+	}
+	l.SetMisbehaviour(ctfake.Misbehaviour{MaxEntries: maxEntries})
+	rt := &recordingTransport{}
+	return l, client.New(l.URL(), &http.Client{Transport: rt}), rt
+}
+
+func checkEntry(t *testing.T, entry *ct.LogEntry, index int64) {
+	if entry.Index != index {
+		t.Errorf("got entry with index %d, want %d", entry.Index, index)
+	}
+	var data struct {
+		Data int64
+	}
+	if err := json.Unmarshal(entry.JSONData, &data); err != nil {
+		t.Errorf("entry %d has bad JSON data %q: %v", index, entry.JSONData, err)
+	} else if data.Data != index {
+		t.Errorf("entry %d holds data for entry %d", index, data.Data)
+	}
+}
+
+func TestStreamEntries(t *testing.T) {
+	l, c, _ := entriesLog(t, 10, 4)
+	defer l.Close()
+
+	next := int64(2)
+	n, err := c.StreamEntries(context.Background(), 2, 8, func(entry *ct.LogEntry) error {
+		checkEntry(t, entry, next)
+		next++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamEntries()=%v", err)
+	}
+	// The log only returns 4 entries per request.
+	if n != 4 || next != 6 {
+		t.Errorf("StreamEntries() streamed %d entries, up to %d; want 4 up to 6", n, next)
+	}
+
+	// Errors from the callback stop the stream.
+	stop := errors.New("stop")
+	n, err = c.StreamEntries(context.Background(), 0, 3, func(entry *ct.LogEntry) error {
+		if entry.Index == 1 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || n != 1 {
+		t.Errorf("StreamEntries()=%d, %v; want 1, %v", n, err, stop)
+	}
+}
+
+func TestStreamEntriesErrors(t *testing.T) {
+	tests := []struct {
+		desc    string
+		status  int
+		body    string
+		wantErr interface{} // the expected type of RspError.Err, or nil for any
+	}{
+		{"404", http.StatusNotFound, "not found", nil},
+		{"not JSON", http.StatusOK, "not json", client.JSONError{}},
+		{"not an object", http.StatusOK, "[]", client.JSONError{}},
+		{"truncated", http.StatusOK, fmt.Sprintf(`{"entries":[{"leaf_input":"%s","extra_data":"%s"},{"leaf`, client.CertEntryB64, client.CertEntryExtraDataB64), client.JSONError{}},
+	}
+	for _, test := range tests {
+		hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.status)
+			w.Write([]byte(test.body))
+		}))
+		_, err := client.New(hs.URL, &http.Client{}).StreamEntries(context.Background(), 0, 1, func(*ct.LogEntry) error { return nil })
+		hs.Close()
+
+		rspErr, ok := err.(client.RspError)
+		if !ok {
+			t.Errorf("%s: got error %v, want RspError", test.desc, err)
+			continue
+		}
+		if rspErr.StatusCode != test.status || rspErr.Endpoint != client.GetEntriesPath {
+			t.Errorf("%s: got %+v", test.desc, rspErr)
+		}
+		if test.wantErr != nil {
+			if got, want := reflect.TypeOf(rspErr.Err), reflect.TypeOf(test.wantErr); got != want {
+				t.Errorf("%s: Err=%v (%v), want %v", test.desc, rspErr.Err, got, want)
+			}
+		}
+	}
+}
+
+func TestEntryIterator(t *testing.T) {
+	l, c, rt := entriesLog(t, 20, 3)
+	defer l.Close()
+
+	it := c.NewEntryIterator(context.Background(), 1, 10, 5)
+	defer it.Close()
+	next := int64(1)
+	for it.Next() {
+		checkEntry(t, it.Entry(), next)
+		next++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err()=%v", err)
+	}
+	if next != 11 {
+		t.Errorf("iterated up to %d, want 11", next)
+	}
+	// Each request for 5 entries only gets 3, so the next batch starts from
+	// wherever the log stopped.
+	want := []string{"1-5", "4-8", "7-10", "10-10"}
+	if !reflect.DeepEqual(rt.requests, want) {
+		t.Errorf("made requests %v, want %v", rt.requests, want)
+	}
+}
+
+func TestEntryIteratorErrors(t *testing.T) {
+	// The log has fewer entries than are asked for.
+	l, c, _ := entriesLog(t, 5, 10)
+	defer l.Close()
+
+	it := c.NewEntryIterator(context.Background(), 0, 9, 0)
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if n != 5 {
+		t.Errorf("iterated over %d entries, want 5", n)
+	}
+	if it.Err() == nil {
+		t.Error("Err()=nil, want error for missing entries")
+	}
+
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer hs.Close()
+	it = client.New(hs.URL, &http.Client{}).NewEntryIterator(context.Background(), 0, 9, 0)
+	if it.Next() {
+		t.Fatal("Next()=true from failing log")
+	}
+	if rspErr, ok := it.Err().(client.RspError); !ok || rspErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Err()=%v, want RspError with status 400", it.Err())
+	}
+}
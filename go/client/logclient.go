@@ -8,15 +8,16 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
-	"time"
+	"strings"
 
 	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/x509"
 	"golang.org/x/net/context"
 )
 
@@ -29,12 +30,22 @@ const (
 	GetEntriesPath        = "/ct/v1/get-entries"
 	GetProofByHashPath    = "/ct/v1/get-proof-by-hash"
 	GetSTHConsistencyPath = "/ct/v1/get-sth-consistency"
+	GetRootsPath          = "/ct/v1/get-roots"
+	GetEntryAndProofPath  = "/ct/v1/get-entry-and-proof"
 )
 
 // LogClient represents a client for a given CT Log instance
 type LogClient struct {
 	uri        string       // the base URI of the log. e.g. http://ct.googleapis/pilot
 	httpClient *http.Client // used to interact with the log via HTTP
+	// verifier checks the signatures on STHs and SCTs returned by the log,
+	// or is nil if the log's public key is unknown.
+	verifier *ct.SignatureVerifier
+	logID    ct.SHA256Hash // the SHA256 hash of the log's public key, if known
+	// retryPolicy controls the retrying of failed requests, or is nil to use
+	// the historical defaults.
+	retryPolicy *RetryPolicy
+	clock       Clock // used when waiting between retries
 }
 
 //////////////////////////////////////////////////////////////////////////////////
@@ -42,16 +53,16 @@ type LogClient struct {
 // These represent the structures returned by the CT Log server.
 //////////////////////////////////////////////////////////////////////////////////
 
-// addChainRequest represents the JSON request body sent to the add-chain CT
+// AddChainRequest represents the JSON request body sent to the add-chain CT
 // method.
-type addChainRequest struct {
+type AddChainRequest struct {
 	Chain [][]byte `json:"chain"`
 }
 
-// addChainResponse represents the JSON response to the add-chain CT method.
+// AddChainResponse represents the JSON response to the add-chain CT method.
 // An SCT represents a Log's promise to integrate a [pre-]certificate into the
 // log within a defined period of time.
-type addChainResponse struct {
+type AddChainResponse struct {
 	SCTVersion ct.Version `json:"sct_version"` // SCT structure version
 	ID         []byte     `json:"id"`          // Log ID
 	Timestamp  uint64     `json:"timestamp"`   // Timestamp of issuance
@@ -59,22 +70,22 @@ type addChainResponse struct {
 	Signature  []byte     `json:"signature"`   // Log signature for this SCT
 }
 
-// addJSONRequest represents the JSON request body sent ot the add-json CT
+// AddJSONRequest represents the JSON request body sent ot the add-json CT
 // method.
-type addJSONRequest struct {
+type AddJSONRequest struct {
 	Data interface{} `json:"data"`
 }
 
-// getSTHResponse respresents the JSON response to the get-sth CT method
-type getSTHResponse struct {
+// GetSTHResponse respresents the JSON response to the get-sth CT method
+type GetSTHResponse struct {
 	TreeSize          uint64 `json:"tree_size"`           // Number of certs in the current tree
 	Timestamp         uint64 `json:"timestamp"`           // Time that the tree was created
 	SHA256RootHash    []byte `json:"sha256_root_hash"`    // Root hash of the tree
 	TreeHeadSignature []byte `json:"tree_head_signature"` // Log signature for this STH
 }
 
-// getConsistencyProofResponse represents the JSON response to the get-consistency-proof CT method
-type getConsistencyProofResponse struct {
+// GetSTHConsistencyResponse represents the JSON response to the get-sth-consistency CT method
+type GetSTHConsistencyResponse struct {
 	Consistency [][]byte `json:"consistency"`
 }
 
@@ -84,16 +95,16 @@ type getAuditProofResponse struct {
 	TreeSize uint64   `json:"tree_size"` // the tree size against which this proof is constructed
 }
 
-// getAcceptedRootsResponse represents the JSON response to the CT get-roots method.
-type getAcceptedRootsResponse struct {
-	Certificates []string `json:"certificates"`
+// GetRootsResponse represents the JSON response to the CT get-roots method.
+type GetRootsResponse struct {
+	Certificates [][]byte `json:"certificates"`
 }
 
-// getEntryAndProodReponse represents the JSON response to the CT get-entry-and-proof method
-type getEntryAndProofResponse struct {
-	LeafInput string   `json:"leaf_input"` // the entry itself
-	ExtraData string   `json:"extra_data"` // any chain provided when the entry was added to the log
-	AuditPath []string `json:"audit_path"` // the corresponding proof
+// GetEntryAndProofResponse represents the JSON response to the CT get-entry-and-proof method
+type GetEntryAndProofResponse struct {
+	LeafInput []byte   `json:"leaf_input"` // the entry itself
+	ExtraData []byte   `json:"extra_data"` // any chain provided when the entry was added to the log
+	AuditPath [][]byte `json:"audit_path"` // the corresponding proof
 }
 
 // GetProofByHashResponse represents the JSON response to the CT get-proof-by-hash method.
@@ -106,140 +117,211 @@ type GetProofByHashResponse struct {
 // |uri| is the base URI of the CT log instance to interact with, e.g.
 // http://ct.googleapis.com/pilot
 // |hc| is the underlying client to be used for HTTP requests to the CT log.
-func New(uri string, hc *http.Client) *LogClient {
+// |opts| configure optional behaviour, such as the RetryPolicy to use.
+func New(uri string, hc *http.Client, opts ...Option) *LogClient {
 	if hc == nil {
 		hc = new(http.Client)
 	}
-	return &LogClient{uri: uri, httpClient: hc}
+	c := &LogClient{uri: uri, httpClient: hc, clock: systemClock{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-// Makes a HTTP call to |uri|, and attempts to parse the response as a
-// JSON representation of the structure in |res|. Uses |ctx| to
-// control the HTTP call (so it can have a timeout or be cancelled by
-// the caller), and |httpClient| to make the actual HTTP call.
-// Returns a non-nil |error| if there was a problem.
-func fetchAndParse(ctx context.Context, httpClient *http.Client, uri string, res interface{}) error {
-	req, err := http.NewRequest(http.MethodGet, uri, nil)
+// NewWithPubKey constructs a new LogClient instance which verifies the
+// signatures on all STHs and SCTs returned by the log.
+// |uri|, |hc| and |opts| are as for New.
+// |pemEncodedKey| is the log's public key, in PEM format.
+func NewWithPubKey(uri string, hc *http.Client, pemEncodedKey string, opts ...Option) (*LogClient, error) {
+	pubKey, keyHash, rest, err := ct.PublicKeyFromPEM([]byte(pemEncodedKey))
 	if err != nil {
-		return err
+		return nil, err
 	}
-	req.Cancel = ctx.Done()
-	resp, err := httpClient.Do(req)
+	if len(rest) > 0 {
+		return nil, errors.New("extra data found after PEM key decoded")
+	}
+	verifier, err := ct.NewSignatureVerifier(pubKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
-	// Make sure everything is read, so http.Client can reuse the connection.
-	defer ioutil.ReadAll(resp.Body)
+	c := New(uri, hc, opts...)
+	c.verifier = verifier
+	c.logID = keyHash
+	return c, nil
+}
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("got HTTP Status %s", resp.Status)
+// Makes a HTTP GET call to the |path| endpoint of the log at |uri|, with the
+// query parameters |params|, and attempts to parse the response as a JSON
+// representation of the structure in |res|. Uses |ctx| to control the HTTP
+// call (so it can have a timeout or be cancelled by the caller), and
+// |httpClient| to make the actual HTTP call.
+// Returns the HTTP response and its body, or a non-nil |error| if there was a
+// problem. Non-200 responses and unparseable bodies result in an RspError.
+func fetchAndParse(ctx context.Context, httpClient *http.Client, uri, path string, params url.Values, res interface{}) (*http.Response, []byte, error) {
+	resp, err := doGet(ctx, httpClient, uri, path, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := readBody(resp)
+	if err != nil {
+		return resp, body, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, body, statusError(path, resp, body)
+	}
+	if err := json.Unmarshal(body, res); err != nil {
+		return resp, body, RspError{Err: JSONError{err}, Endpoint: path, StatusCode: resp.StatusCode, Body: body}
 	}
+	return resp, body, nil
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
-		return err
+// doGet makes a HTTP GET call to the |path| endpoint of the log at |uri|,
+// with the query parameters |params|, and returns the response without
+// reading its body.
+func doGet(ctx context.Context, httpClient *http.Client, uri, path string, params url.Values) (*http.Response, error) {
+	u := strings.TrimRight(uri, "/") + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
 	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Cancel = ctx.Done()
+	return httpClient.Do(req)
+}
 
-	return nil
+// Makes a HTTP GET call to the |path| endpoint of the log, as for
+// fetchAndParse, retrying as directed by the client's RetryPolicy.
+func (c *LogClient) fetch(ctx context.Context, path string, params url.Values, res interface{}) (httpResp *http.Response, body []byte, err error) {
+	err = c.retry(ctx, c.getPolicy(), func() error {
+		httpResp, body, err = fetchAndParse(ctx, c.httpClient, c.uri, path, params, res)
+		return err
+	})
+	return
 }
 
-// Makes a HTTP POST call to |uri|, and attempts to parse the response as a JSON
-// representation of the structure in |res|.
-// Returns a non-nil |error| if there was a problem.
-func (c *LogClient) postAndParse(uri string, req interface{}, res interface{}) (*http.Response, string, error) {
+// Makes a HTTP POST call to the |path| endpoint of the log, and attempts to
+// parse the response as a JSON representation of the structure in |res|. If
+// |ctx| is non-nil it is used to control the HTTP call.
+// Returns the HTTP response and its body, or a non-nil |error| if there was a
+// problem. Non-200 responses and unparseable bodies result in an RspError.
+func (c *LogClient) postAndParse(ctx context.Context, path string, req interface{}, res interface{}) (*http.Response, []byte, error) {
 	postBody, err := json.Marshal(req)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
-	httpReq, err := http.NewRequest(http.MethodPost, uri, bytes.NewReader(postBody))
+	httpReq, err := http.NewRequest(http.MethodPost, strings.TrimRight(c.uri, "/")+path, bytes.NewReader(postBody))
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
+	}
+	if ctx != nil {
+		httpReq.Cancel = ctx.Done()
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 	resp, err := c.httpClient.Do(httpReq)
-	// Read all of the body, if there is one, so that the http.Client can do
-	// Keep-Alive:
-	var body []byte
-	if resp != nil {
-		body, err = ioutil.ReadAll(resp.Body)
-		resp.Body.Close()
+	if err != nil {
+		return nil, nil, err
 	}
+	body, err := readBody(resp)
 	if err != nil {
-		return resp, string(body), err
+		return resp, body, err
 	}
-	if resp.StatusCode == 200 {
-		if err != nil {
-			return resp, string(body), err
-		}
-		if err = json.Unmarshal(body, &res); err != nil {
-			return resp, string(body), err
-		}
+	if resp.StatusCode != http.StatusOK {
+		return resp, body, statusError(path, resp, body)
+	}
+	if err = json.Unmarshal(body, &res); err != nil {
+		return resp, body, RspError{Err: JSONError{err}, Endpoint: path, StatusCode: resp.StatusCode, Body: body}
 	}
-	return resp, string(body), nil
+	return resp, body, nil
 }
 
-func backoffForRetry(ctx context.Context, d time.Duration) error {
-	backoffTimer := time.NewTimer(d)
-	if ctx != nil {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-backoffTimer.C:
+// statusError returns an RspError for the non-200 response |resp| from the
+// |path| endpoint.
+func statusError(path string, resp *http.Response, body []byte) RspError {
+	return RspError{
+		Err:        fmt.Errorf("got HTTP Status %s", resp.Status),
+		Endpoint:   path,
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		header:     resp.Header,
+	}
+}
+
+// readBody reads and closes the body of |resp|.
+// Reading all of the body allows the http.Client to reuse the connection.
+func readBody(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
+
+// checkHashLengths returns an RspError wrapping a HashLengthError if any of
+// |hashes|, parsed from the |field| field of a response from the |path|
+// endpoint, is not a SHA256 hash.
+func checkHashLengths(path, field string, hashes [][]byte, resp *http.Response, body []byte) error {
+	for _, h := range hashes {
+		if len(h) != sha256.Size {
+			return RspError{Err: HashLengthError{Field: field, Got: len(h), Want: sha256.Size}, Endpoint: path, StatusCode: resp.StatusCode, Body: body}
 		}
-	} else {
-		<-backoffTimer.C
 	}
 	return nil
 }
 
+// unmarshalSignature decodes the TLS-encoded DigitallySigned structure |sig|,
+// which came from a response from the |path| endpoint, returning an RspError
+// wrapping a DigitallySignedError on failure.
+func unmarshalSignature(path string, sig []byte, resp *http.Response, body []byte) (*ct.DigitallySigned, error) {
+	ds, err := ct.UnmarshalDigitallySigned(bytes.NewReader(sig))
+	if err != nil {
+		return nil, RspError{Err: DigitallySignedError{err}, Endpoint: path, StatusCode: resp.StatusCode, Body: body}
+	}
+	return ds, nil
+}
+
 // Attempts to add |chain| to the log, using the api end-point specified by
-// |path|. If provided context expires before submission is complete an
-// error will be returned.
+// |path|, retrying as directed by the client's RetryPolicy. If provided
+// context expires before submission is complete an error will be returned.
 func (c *LogClient) addChainWithRetry(ctx context.Context, path string, chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
-	var resp addChainResponse
-	var req addChainRequest
+	var req AddChainRequest
 	for _, link := range chain {
 		req.Chain = append(req.Chain, link)
 	}
-	httpStatus := "Unknown"
-	backoffSeconds := 0
-	done := false
-	for !done {
-		if backoffSeconds > 0 {
-			log.Printf("Got %s, backing-off %d seconds", httpStatus, backoffSeconds)
-		}
-		err := backoffForRetry(ctx, time.Second*time.Duration(backoffSeconds))
-		if err != nil {
+	sct, err := c.submit(ctx, path, &req)
+	if err != nil {
+		return nil, err
+	}
+	if c.verifier != nil {
+		if err := c.verifySCT(path, sct, chain); err != nil {
 			return nil, err
 		}
-		if backoffSeconds > 0 {
-			backoffSeconds = 0
-		}
-		httpResp, _, err := c.postAndParse(c.uri+path, &req, &resp)
-		if err != nil {
-			backoffSeconds = 10
-			continue
-		}
-		switch {
-		case httpResp.StatusCode == 200:
-			done = true
-		case httpResp.StatusCode == 408:
-			// request timeout, retry immediately
-		case httpResp.StatusCode == 503:
-			// Retry
-			backoffSeconds = 10
-			if retryAfter := httpResp.Header.Get("Retry-After"); retryAfter != "" {
-				if seconds, err := strconv.Atoi(retryAfter); err == nil {
-					backoffSeconds = seconds
-				}
-			}
-		default:
-			return nil, fmt.Errorf("got HTTP Status %s", httpResp.Status)
-		}
-		httpStatus = httpResp.Status
 	}
+	return sct, nil
+}
 
-	ds, err := ct.UnmarshalDigitallySigned(bytes.NewReader(resp.Signature))
+// submit posts |req| to the |path| endpoint of the log, retrying as directed
+// by the client's RetryPolicy, and returns the resulting SCT.
+func (c *LogClient) submit(ctx context.Context, path string, req interface{}) (*ct.SignedCertificateTimestamp, error) {
+	var resp AddChainResponse
+	var httpResp *http.Response
+	var body []byte
+	err := c.retry(ctx, c.submitPolicy(), func() (err error) {
+		httpResp, body, err = c.postAndParse(ctx, path, req, &resp)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buildSCT(path, &resp, httpResp, body)
+}
+
+// buildSCT converts the parsed response |resp| from the |path| endpoint into
+// an SCT.
+func buildSCT(path string, resp *AddChainResponse, httpResp *http.Response, body []byte) (*ct.SignedCertificateTimestamp, error) {
+	if len(resp.ID) != sha256.Size {
+		return nil, RspError{Err: HashLengthError{Field: "id", Got: len(resp.ID), Want: sha256.Size}, Endpoint: path, StatusCode: httpResp.StatusCode, Body: body}
+	}
+	ds, err := unmarshalSignature(path, resp.Signature, httpResp, body)
 	if err != nil {
 		return nil, err
 	}
@@ -269,61 +351,78 @@ func (c *LogClient) AddChainWithContext(ctx context.Context, chain []ct.ASN1Cert
 	return c.addChainWithRetry(ctx, AddChainPath, chain)
 }
 
+// AddPreChainWithContext adds the (DER represented) Precertificate |chain| to
+// the log and fails if the provided context expires before the chain is
+// submitted.
+func (c *LogClient) AddPreChainWithContext(ctx context.Context, chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return c.addChainWithRetry(ctx, AddPreChainPath, chain)
+}
+
 // AddJSON submits arbitrary data to to XJSON server.
 func (c *LogClient) AddJSON(data interface{}) (*ct.SignedCertificateTimestamp, error) {
-	req := addJSONRequest{
+	return c.AddJSONWithContext(context.TODO(), data)
+}
+
+// AddJSONWithContext submits arbitrary data to to XJSON server, and fails if
+// the provided context expires before the data is submitted.
+func (c *LogClient) AddJSONWithContext(ctx context.Context, data interface{}) (*ct.SignedCertificateTimestamp, error) {
+	req := AddJSONRequest{
 		Data: data,
 	}
-	var resp addChainResponse
-	_, _, err := c.postAndParse(c.uri+AddJSONPath, &req, &resp)
-	if err != nil {
-		return nil, err
-	}
-	ds, err := ct.UnmarshalDigitallySigned(bytes.NewReader(resp.Signature))
-	if err != nil {
-		return nil, err
-	}
-	var logID ct.SHA256Hash
-	copy(logID[:], resp.ID)
-	return &ct.SignedCertificateTimestamp{
-		SCTVersion: resp.SCTVersion,
-		LogID:      logID,
-		Timestamp:  resp.Timestamp,
-		Extensions: ct.CTExtensions(resp.Extensions),
-		Signature:  *ds}, nil
+	return c.submit(ctx, AddJSONPath, &req)
 }
 
 // GetSTH retrieves the current STH from the log.
 // Returns a populated SignedTreeHead, or a non-nil error.
-func (c *LogClient) GetSTH() (sth *ct.SignedTreeHead, err error) {
-	var resp getSTHResponse
-	if err = fetchAndParse(context.TODO(), c.httpClient, c.uri+GetSTHPath, &resp); err != nil {
-		return
+func (c *LogClient) GetSTH() (*ct.SignedTreeHead, error) {
+	return c.GetSTHWithContext(context.TODO())
+}
+
+// GetSTHWithContext retrieves the current STH from the log, and fails if the
+// provided context expires before the STH is retrieved.
+// Returns a populated SignedTreeHead, or a non-nil error.
+func (c *LogClient) GetSTHWithContext(ctx context.Context) (sth *ct.SignedTreeHead, err error) {
+	var resp GetSTHResponse
+	httpResp, body, err := c.fetch(ctx, GetSTHPath, nil, &resp)
+	if err != nil {
+		return nil, err
 	}
 	sth = &ct.SignedTreeHead{
 		TreeSize:  resp.TreeSize,
 		Timestamp: resp.Timestamp,
 	}
 
-	if len(resp.SHA256RootHash) != sha256.Size {
-		return nil, fmt.Errorf("sha256_root_hash is invalid length, expected %d got %d", sha256.Size, len(resp.SHA256RootHash))
+	if err := checkHashLengths(GetSTHPath, "sha256_root_hash", [][]byte{resp.SHA256RootHash}, httpResp, body); err != nil {
+		return nil, err
 	}
 	copy(sth.SHA256RootHash[:], resp.SHA256RootHash)
 
-	ds, err := ct.UnmarshalDigitallySigned(bytes.NewReader(resp.TreeHeadSignature))
+	ds, err := unmarshalSignature(GetSTHPath, resp.TreeHeadSignature, httpResp, body)
 	if err != nil {
 		return nil, err
 	}
-	// TODO(alcutter): Verify signature
 	sth.TreeHeadSignature = *ds
+	if c.verifier != nil {
+		sth.LogID = c.logID
+		if err := c.verifier.VerifySTHSignature(*sth); err != nil {
+			return nil, SignatureVerificationError{Endpoint: GetSTHPath, Err: err}
+		}
+	}
 	return
 }
 
 // GetSTHConsistency retrieves the consistency proof between two snapshots.
 func (c *LogClient) GetSTHConsistency(ctx context.Context, first, second uint64) ([][]byte, error) {
-	u := fmt.Sprintf("%s%s?first=%d&second=%d", c.uri, GetSTHConsistencyPath, first, second)
-	var resp getConsistencyProofResponse
-	if err := fetchAndParse(ctx, c.httpClient, u, &resp); err != nil {
+	params := url.Values{
+		"first":  []string{strconv.FormatUint(first, 10)},
+		"second": []string{strconv.FormatUint(second, 10)},
+	}
+	var resp GetSTHConsistencyResponse
+	httpResp, body, err := c.fetch(ctx, GetSTHConsistencyPath, params, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkHashLengths(GetSTHConsistencyPath, "consistency", resp.Consistency, httpResp, body); err != nil {
 		return nil, err
 	}
 	return resp.Consistency, nil
@@ -331,10 +430,53 @@ func (c *LogClient) GetSTHConsistency(ctx context.Context, first, second uint64)
 
 // GetProofByHash returns an audit path for the hash of an SCT.
 func (c *LogClient) GetProofByHash(ctx context.Context, hash []byte, treeSize uint64) (*GetProofByHashResponse, error) {
-	b64Hash := url.QueryEscape(base64.StdEncoding.EncodeToString(hash))
-	u := fmt.Sprintf("%s%s?tree_size=%d&hash=%v", c.uri, GetProofByHashPath, treeSize, b64Hash)
+	params := url.Values{
+		"tree_size": []string{strconv.FormatUint(treeSize, 10)},
+		"hash":      []string{base64.StdEncoding.EncodeToString(hash)},
+	}
 	var resp GetProofByHashResponse
-	if err := fetchAndParse(ctx, c.httpClient, u, &resp); err != nil {
+	httpResp, body, err := c.fetch(ctx, GetProofByHashPath, params, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkHashLengths(GetProofByHashPath, "audit_path", resp.AuditPath, httpResp, body); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetAcceptedRoots retrieves the set of acceptable root certificates for the
+// log.
+// Certificates which only produce non-fatal parse errors are still returned.
+func (c *LogClient) GetAcceptedRoots(ctx context.Context) ([]*x509.Certificate, error) {
+	var resp GetRootsResponse
+	if _, _, err := c.fetch(ctx, GetRootsPath, nil, &resp); err != nil {
+		return nil, err
+	}
+	roots := make([]*x509.Certificate, 0, len(resp.Certificates))
+	for i, der := range resp.Certificates {
+		cert, err := x509.ParseCertificate(der)
+		if x509.IsFatal(err) {
+			return nil, fmt.Errorf("failed to parse root certificate %d: %v", i, err)
+		}
+		roots = append(roots, cert)
+	}
+	return roots, nil
+}
+
+// GetEntryAndProof returns the log entry at index |leafIndex|, along with an
+// audit path proving its inclusion in the tree of size |treeSize|.
+func (c *LogClient) GetEntryAndProof(ctx context.Context, leafIndex, treeSize uint64) (*GetEntryAndProofResponse, error) {
+	params := url.Values{
+		"leaf_index": []string{strconv.FormatUint(leafIndex, 10)},
+		"tree_size":  []string{strconv.FormatUint(treeSize, 10)},
+	}
+	var resp GetEntryAndProofResponse
+	httpResp, body, err := c.fetch(ctx, GetEntryAndProofPath, params, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkHashLengths(GetEntryAndProofPath, "audit_path", resp.AuditPath, httpResp, body); err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -211,7 +211,8 @@ func TestAddChainWithContext(t *testing.T) {
 		{6, fiveSeconds, 5, 1, true},
 		{5, fiveSeconds, 10, 1, false},
 		{10, fiveSeconds, 1, 5, true},
-		{1, instant * 10, 0, 10, true},
+		// 408s are retried after a backoff, so the deadline expires first.
+		{1, time.Second, 0, 10, false},
 	}
 
 	for _, tc := range testCases {
@@ -322,3 +323,121 @@ func TestGetProofByHash(t *testing.T) {
 		}
 	}
 }
+
+func TestGetAcceptedRoots(t *testing.T) {
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ct/v1/get-roots" {
+			t.Fatalf("Incorrect URL path: %s", r.URL.Path)
+		}
+		fmt.Fprintf(w, `{"certificates":["%s"]}`, SubmissionCertB64)
+	}))
+	defer hs.Close()
+
+	c := New(hs.URL, &http.Client{})
+	roots, err := c.GetAcceptedRoots(context.Background())
+	if err != nil {
+		t.Fatalf("GetAcceptedRoots()=nil,%v; want roots,nil", err)
+	}
+	if len(roots) != 1 {
+		t.Fatalf("len(GetAcceptedRoots())=%d; want 1", len(roots))
+	}
+	if got, want := roots[0].Raw, b64(SubmissionCertB64); !bytes.Equal(got, want) {
+		t.Errorf("GetAcceptedRoots()[0].Raw=%x; want %x", got, want)
+	}
+}
+
+func TestGetAcceptedRootsBadCert(t *testing.T) {
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"certificates":["%s"]}`, base64.StdEncoding.EncodeToString([]byte("not a cert")))
+	}))
+	defer hs.Close()
+
+	c := New(hs.URL, &http.Client{})
+	if roots, err := c.GetAcceptedRoots(context.Background()); err == nil {
+		t.Fatalf("GetAcceptedRoots()=%v,nil; want nil,error", roots)
+	}
+}
+
+func TestGetEntryAndProof(t *testing.T) {
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ct/v1/get-entry-and-proof" {
+			t.Fatalf("Incorrect URL path: %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if got, want := q.Get("leaf_index"), "3"; got != want {
+			t.Fatalf("leaf_index=%q; want %q", got, want)
+		}
+		if got, want := q.Get("tree_size"), "5"; got != want {
+			t.Fatalf("tree_size=%q; want %q", got, want)
+		}
+		fmt.Fprintf(w, `{"leaf_input":"%s","extra_data":"%s","audit_path":["pMumx96PIUB3TX543ljlpQ/RgZRqitRfykupIZrXq0Q="]}`, CertEntryB64, CertEntryExtraDataB64)
+	}))
+	defer hs.Close()
+
+	c := New(hs.URL, &http.Client{})
+	resp, err := c.GetEntryAndProof(context.Background(), 3, 5)
+	if err != nil {
+		t.Fatalf("GetEntryAndProof()=nil,%v; want resp,nil", err)
+	}
+	if got, want := resp.LeafInput, b64(CertEntryB64); !bytes.Equal(got, want) {
+		t.Errorf("LeafInput=%x; want %x", got, want)
+	}
+	if got, want := resp.ExtraData, b64(CertEntryExtraDataB64); !bytes.Equal(got, want) {
+		t.Errorf("ExtraData=%x; want %x", got, want)
+	}
+	if got, want := resp.AuditPath, [][]byte{b64("pMumx96PIUB3TX543ljlpQ/RgZRqitRfykupIZrXq0Q=")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AuditPath=%x; want %x", got, want)
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	block := make(chan struct{})
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer hs.Close()
+	defer close(block)
+
+	c := New(hs.URL, &http.Client{})
+	certBytes := b64(SubmissionCertB64)
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{"GetSTHWithContext", func(ctx context.Context) error {
+			_, err := c.GetSTHWithContext(ctx)
+			return err
+		}},
+		{"GetEntriesWithContext", func(ctx context.Context) error {
+			_, err := c.GetEntriesWithContext(ctx, 0, 1)
+			return err
+		}},
+		{"GetAcceptedRoots", func(ctx context.Context) error {
+			_, err := c.GetAcceptedRoots(ctx)
+			return err
+		}},
+		{"GetEntryAndProof", func(ctx context.Context) error {
+			_, err := c.GetEntryAndProof(ctx, 1, 2)
+			return err
+		}},
+		{"AddPreChainWithContext", func(ctx context.Context) error {
+			_, err := c.AddPreChainWithContext(ctx, []ct.ASN1Cert{certBytes})
+			return err
+		}},
+		{"AddJSONWithContext", func(ctx context.Context) error {
+			_, err := c.AddJSONWithContext(ctx, "data")
+			return err
+		}},
+	}
+	for _, test := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		started := time.Now()
+		if err := test.call(ctx); err == nil {
+			t.Errorf("%s()=nil; want error", test.name)
+		}
+		if took := time.Since(started); took > 5*time.Second {
+			t.Errorf("%s() took %s; want it to give up once the context expired", test.name, took)
+		}
+		cancel()
+	}
+}
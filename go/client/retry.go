@@ -0,0 +1,213 @@
+package client
+
+import (
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// RetryPolicy controls how a LogClient retries requests which fail with a
+// transport error or a transient HTTP status (408, 429, 500, 502, 503 and
+// 504). Other failures, such as the log rejecting a submission or returning a
+// malformed response, are never retried.
+//
+// The delay before the n'th retry is InitialBackoff * Multiplier^(n-1),
+// capped at MaxBackoff, with up to a fraction Jitter of it removed at random
+// so that many clients don't retry in lockstep. If a 429 or 503 response
+// carries a Retry-After header asking for a positive delay then that delay is
+// used instead.
+type RetryPolicy struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64 // values below 1 are treated as 1
+	Jitter         float64 // in [0, 1]
+	// MaxAttempts is the maximum number of times a request is sent, including
+	// the first attempt. Zero means no limit.
+	MaxAttempts int
+	// MaxElapsed is the maximum time to spend on a request, across all
+	// attempts, after which the last error is returned. Zero means no limit.
+	MaxElapsed time.Duration
+
+	// legacyStatuses restricts the HTTP statuses which are retried to 408 and
+	// 503, as the client did before RetryPolicy was introduced.
+	legacyStatuses bool
+}
+
+// DefaultRetryPolicy is a reasonable RetryPolicy for talking to production
+// logs.
+var DefaultRetryPolicy = RetryPolicy{
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+	Multiplier:     2,
+	Jitter:         0.2,
+	MaxElapsed:     10 * time.Minute,
+}
+
+// Policies used when a LogClient isn't given a RetryPolicy, which preserve
+// the client's historical behaviour: get requests are tried once, and
+// submissions which fail with a transport error, a 408 or a 503 are retried
+// indefinitely every 10 seconds. Any other failure is returned immediately.
+var (
+	noRetryPolicy      = RetryPolicy{MaxAttempts: 1}
+	legacySubmitPolicy = RetryPolicy{InitialBackoff: 10 * time.Second, MaxBackoff: 10 * time.Second, legacyStatuses: true}
+)
+
+// backoff returns the delay to use before the |retry|'th retry (starting
+// from 1).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	m := p.Multiplier
+	if m < 1 {
+		m = 1
+	}
+	d := float64(p.InitialBackoff) * math.Pow(m, float64(retry-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d -= d * math.Min(p.Jitter, 1) * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// Clock is the source of time used by a LogClient when waiting between
+// retries. It exists so that tests can substitute a fake clock.
+type Clock interface {
+	Now() time.Time
+	// After waits for the duration |d| to elapse and then sends the current
+	// time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Option configures optional behaviour of a LogClient.
+type Option func(*LogClient)
+
+// WithRetryPolicy sets the policy used to retry all requests made to the log:
+// get-sth, get-entries, proof fetches and submissions alike.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *LogClient) {
+		c.retryPolicy = &p
+	}
+}
+
+// WithClock sets the clock used when waiting between retries.
+func WithClock(clock Clock) Option {
+	return func(c *LogClient) {
+		c.clock = clock
+	}
+}
+
+// getPolicy returns the RetryPolicy for get requests.
+func (c *LogClient) getPolicy() RetryPolicy {
+	if c.retryPolicy != nil {
+		return *c.retryPolicy
+	}
+	return noRetryPolicy
+}
+
+// submitPolicy returns the RetryPolicy for submissions.
+func (c *LogClient) submitPolicy() RetryPolicy {
+	if c.retryPolicy != nil {
+		return *c.retryPolicy
+	}
+	return legacySubmitPolicy
+}
+
+// retry calls |call| until it succeeds or fails with an error which shouldn't
+// be retried, waiting between attempts as directed by |p|.
+// Returns the error from the last attempt if |p| gives up, or the context's
+// error if |ctx| expires first.
+func (c *LogClient) retry(ctx context.Context, p RetryPolicy, call func() error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	start := c.clock.Now()
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		delay, ok := retryDelay(err, attempt, p, c.clock.Now())
+		if !ok {
+			return err
+		}
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return err
+		}
+		if p.MaxElapsed > 0 && c.clock.Now().Add(delay).Sub(start) > p.MaxElapsed {
+			return err
+		}
+		if delay > 0 {
+			log.Printf("%v, backing-off %s", err, delay)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-c.clock.After(delay):
+			}
+		}
+	}
+}
+
+// retryDelay returns how long to wait before retrying a request whose
+// |attempt|'th attempt failed with |err|, or false if the request shouldn't be
+// retried.
+func retryDelay(err error, attempt int, p RetryPolicy, now time.Time) (time.Duration, bool) {
+	rspErr, ok := err.(RspError)
+	if !ok {
+		// Transport failure.
+		return p.backoff(attempt), true
+	}
+	if p.legacyStatuses && rspErr.StatusCode != http.StatusRequestTimeout && rspErr.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	switch rspErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		// A Retry-After of zero, or in the past, mustn't send the client
+		// into a tight loop, so the backoff is used instead.
+		if d, ok := parseRetryAfter(rspErr.header.Get("Retry-After"), now); ok && d > 0 {
+			return d, true
+		}
+		return p.backoff(attempt), true
+	case http.StatusRequestTimeout, http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return p.backoff(attempt), true
+	}
+	return 0, false
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which may be
+// either a number of seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
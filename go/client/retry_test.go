@@ -0,0 +1,317 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+	"github.com/google/certificate-transparency/go/ctfake"
+	"github.com/google/certificate-transparency/go/testonly"
+	"golang.org/x/net/context"
+)
+
+// fakeClock is a Clock which never blocks, but records each wait and moves
+// its notion of the current time forward accordingly.
+type fakeClock struct {
+	now   time.Time
+	waits []time.Duration
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.waits = append(f.waits, d)
+	f.now = f.now.Add(d)
+	c := make(chan time.Time, 1)
+	c <- f.now
+	return c
+}
+
+// flakyServer returns a test server which responds to the first |failures|
+// requests with |status| (and the Retry-After header |retryAfter|, if set),
+// and then with |body|. The number of requests received is counted in
+// |attempts|. It is only needed for responses which a ctfake log won't give.
+func flakyServer(failures int32, status int, retryAfter, body string, attempts *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n := atomic.AddInt32(attempts, 1); failures < 0 || n <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	l := newLog(t)
+	defer l.Close()
+	l.SetMisbehaviour(ctfake.Misbehaviour{Unavailable: 5})
+
+	rt := &recordingTransport{}
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	c := client.New(l.URL(), &http.Client{Transport: rt}, client.WithClock(clock), client.WithRetryPolicy(client.RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+	}))
+	if _, err := c.GetSTH(); err != nil {
+		t.Fatalf("GetSTH()=%v", err)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	if !reflect.DeepEqual(clock.waits, want) {
+		t.Errorf("waited %v, want %v", clock.waits, want)
+	}
+	if rt.attempts != 6 {
+		t.Errorf("made %d attempts, want 6", rt.attempts)
+	}
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	tests := []struct {
+		retryAfter time.Duration
+		want       time.Duration
+	}{
+		{7 * time.Second, 7 * time.Second},
+		// Without a Retry-After, the policy's backoff is used.
+		{0, time.Second},
+	}
+	for _, test := range tests {
+		l := newLog(t)
+		l.SetMisbehaviour(ctfake.Misbehaviour{Unavailable: 1, RetryAfter: test.retryAfter})
+		clock := &fakeClock{now: time.Unix(1000, 0)}
+		c := client.New(l.URL(), &http.Client{}, client.WithClock(clock), client.WithRetryPolicy(client.RetryPolicy{InitialBackoff: time.Second}))
+		_, err := c.GetSTH()
+		l.Close()
+		if err != nil {
+			t.Errorf("Retry-After %v: GetSTH()=%v", test.retryAfter, err)
+			continue
+		}
+		if want := []time.Duration{test.want}; !reflect.DeepEqual(clock.waits, want) {
+			t.Errorf("Retry-After %v: waited %v, want %v", test.retryAfter, clock.waits, want)
+		}
+	}
+}
+
+func TestRetryAfterFormats(t *testing.T) {
+	now := time.Date(2016, 8, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		status     int
+		retryAfter string
+		want       time.Duration
+	}{
+		{http.StatusTooManyRequests, "3", 3 * time.Second},
+		{http.StatusTooManyRequests, now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		// Without a valid Retry-After, the policy's backoff is used.
+		{http.StatusTooManyRequests, "soon", time.Second},
+		// Nor does one asking for no delay at all.
+		{http.StatusTooManyRequests, "0", time.Second},
+		{http.StatusTooManyRequests, now.Add(-time.Minute).Format(http.TimeFormat), time.Second},
+		// 408s don't carry Retry-After.
+		{http.StatusRequestTimeout, "7", time.Second},
+	}
+	for _, test := range tests {
+		var attempts int32
+		hs := flakyServer(1, test.status, test.retryAfter, client.ValidSTHResponse, &attempts)
+		clock := &fakeClock{now: now}
+		c := client.New(hs.URL, &http.Client{}, client.WithClock(clock), client.WithRetryPolicy(client.RetryPolicy{InitialBackoff: time.Second}))
+		_, err := c.GetSTH()
+		hs.Close()
+		if err != nil {
+			t.Errorf("%d %q: GetSTH()=%v", test.status, test.retryAfter, err)
+			continue
+		}
+		if want := []time.Duration{test.want}; !reflect.DeepEqual(clock.waits, want) {
+			t.Errorf("%d %q: waited %v, want %v", test.status, test.retryAfter, clock.waits, want)
+		}
+	}
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	l := newLog(t)
+	defer l.Close()
+	l.SetMisbehaviour(ctfake.Misbehaviour{Unavailable: 10})
+
+	rt := &recordingTransport{}
+	c := client.New(l.URL(), &http.Client{Transport: rt}, client.WithClock(&fakeClock{}), client.WithRetryPolicy(client.RetryPolicy{InitialBackoff: time.Second, MaxAttempts: 3}))
+	_, err := c.AddChain(testChain(t, "test-cert.pem"))
+	if rspErr, ok := err.(client.RspError); !ok || rspErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("AddChain()=%v, want RspError with status 503", err)
+	}
+	if rt.attempts != 3 {
+		t.Fatalf("made %d attempts, want 3", rt.attempts)
+	}
+}
+
+func TestRetryMaxElapsed(t *testing.T) {
+	l := newLog(t)
+	defer l.Close()
+	l.SetMisbehaviour(ctfake.Misbehaviour{Unavailable: 10})
+
+	rt := &recordingTransport{}
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	c := client.New(l.URL(), &http.Client{Transport: rt}, client.WithClock(clock), client.WithRetryPolicy(client.RetryPolicy{
+		InitialBackoff: time.Second,
+		Multiplier:     2,
+		MaxElapsed:     10 * time.Second,
+	}))
+	if _, err := c.GetSTH(); err == nil {
+		t.Fatal("GetSTH() succeeded, want error")
+	}
+	// A fourth wait, of 8s, would take the total past 10s.
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	if !reflect.DeepEqual(clock.waits, want) {
+		t.Errorf("waited %v, want %v", clock.waits, want)
+	}
+	if rt.attempts != 4 {
+		t.Errorf("made %d attempts, want 4", rt.attempts)
+	}
+}
+
+func TestRetryGivesUpOnPermanentErrors(t *testing.T) {
+	l := newLog(t)
+	defer l.Close()
+	tests := []struct {
+		uri    string
+		status int
+	}{
+		// google-cert.pem isn't issued by one of the log's roots.
+		{l.URL(), http.StatusBadRequest},
+		{l.URL() + "/missing", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		rt := &recordingTransport{}
+		c := client.New(test.uri, &http.Client{Transport: rt}, client.WithClock(&fakeClock{}), client.WithRetryPolicy(client.DefaultRetryPolicy))
+		_, err := c.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, "google-cert.pem")})
+		if rspErr, ok := err.(client.RspError); !ok || rspErr.StatusCode != test.status {
+			t.Errorf("AddChain()=%v, want RspError with status %d", err, test.status)
+		}
+		if rt.attempts != 1 {
+			t.Errorf("status %d: made %d attempts, want 1", test.status, rt.attempts)
+		}
+	}
+}
+
+func TestRetryTransportErrors(t *testing.T) {
+	l := newLog(t)
+	uri := l.URL()
+	l.Close()
+
+	clock := &fakeClock{}
+	c := client.New(uri, &http.Client{}, client.WithClock(clock), client.WithRetryPolicy(client.RetryPolicy{InitialBackoff: time.Second, MaxAttempts: 4}))
+	if _, err := c.GetSTH(); err == nil {
+		t.Fatal("GetSTH() from closed log succeeded, want error")
+	}
+	if got := len(clock.waits); got != 3 {
+		t.Fatalf("waited %d times, want 3", got)
+	}
+}
+
+func TestRetryAppliesToAllRequests(t *testing.T) {
+	ctx := context.Background()
+	l := newLog(t)
+	defer l.Close()
+	for _, data := range []string{"a", "b"} {
+		if _, err := l.AddJSON(data); err != nil {
+			t.Fatalf("AddJSON(%q)=%v", data, err)
+		}
+	}
+	entries, err := client.New(l.URL(), &http.Client{}).GetEntries(0, 0)
+	if err != nil {
+		t.Fatalf("GetEntries()=%v", err)
+	}
+	leafHash, err := ct.LeafHashForLeaf(&entries[0].Leaf)
+	if err != nil {
+		t.Fatalf("LeafHashForLeaf()=%v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func(c *client.LogClient) error
+	}{
+		{"GetSTH", func(c *client.LogClient) error {
+			_, err := c.GetSTH()
+			return err
+		}},
+		{"GetEntries", func(c *client.LogClient) error {
+			_, err := c.GetEntries(0, 0)
+			return err
+		}},
+		{"GetSTHConsistency", func(c *client.LogClient) error {
+			_, err := c.GetSTHConsistency(ctx, 1, 2)
+			return err
+		}},
+		{"GetProofByHash", func(c *client.LogClient) error {
+			_, err := c.GetProofByHash(ctx, leafHash[:], 2)
+			return err
+		}},
+		{"AddChain", func(c *client.LogClient) error {
+			_, err := c.AddChain(testChain(t, "test-cert.pem"))
+			return err
+		}},
+		{"AddJSON", func(c *client.LogClient) error {
+			_, err := c.AddJSON("data")
+			return err
+		}},
+	}
+	for _, test := range tests {
+		l.SetMisbehaviour(ctfake.Misbehaviour{Unavailable: 2})
+		rt := &recordingTransport{}
+		c := client.New(l.URL(), &http.Client{Transport: rt}, client.WithClock(&fakeClock{}), client.WithRetryPolicy(client.RetryPolicy{InitialBackoff: time.Second}))
+		if err := test.call(c); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if rt.attempts != 3 {
+			t.Errorf("%s: made %d attempts, want 3", test.name, rt.attempts)
+		}
+	}
+}
+
+func TestNoRetryPolicyDoesNotRetryGets(t *testing.T) {
+	l := newLog(t)
+	defer l.Close()
+	l.SetMisbehaviour(ctfake.Misbehaviour{Unavailable: 1})
+
+	rt := &recordingTransport{}
+	if _, err := client.New(l.URL(), &http.Client{Transport: rt}).GetSTH(); err == nil {
+		t.Fatal("GetSTH() succeeded, want error")
+	}
+	if rt.attempts != 1 {
+		t.Fatalf("made %d attempts, want 1", rt.attempts)
+	}
+}
+
+func TestNoRetryPolicyRetriesSubmissionsOnlyFor408And503(t *testing.T) {
+	tests := []struct {
+		status       int
+		wantAttempts int32
+	}{
+		{http.StatusRequestTimeout, 3},
+		{http.StatusServiceUnavailable, 3},
+		{http.StatusTooManyRequests, 1},
+		{http.StatusInternalServerError, 1},
+		{http.StatusBadGateway, 1},
+		{http.StatusGatewayTimeout, 1},
+	}
+	for _, test := range tests {
+		var attempts int32
+		hs := flakyServer(2, test.status, "", client.AddJSONResp, &attempts)
+		_, err := client.New(hs.URL, &http.Client{}, client.WithClock(&fakeClock{})).AddChain(testChain(t, "test-cert.pem"))
+		hs.Close()
+		if gotErr, wantErr := err != nil, test.wantAttempts == 1; gotErr != wantErr {
+			t.Errorf("status %d: AddChain()=%v, want error %v", test.status, err, wantErr)
+		}
+		if attempts != test.wantAttempts {
+			t.Errorf("status %d: made %d attempts, want %d", test.status, attempts, test.wantAttempts)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package verifiedfetch fetches entries from a CT log and checks that each of
+// them is included in the tree described by a signed tree head, so that a log
+// can't serve entries which aren't in its own tree.
+//
+// It is kept separate from the client package because the merkletree package
+// it depends on also contains the cgo bindings to the C++ Merkle tree.
+package verifiedfetch
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+	"github.com/google/certificate-transparency/go/merkletree"
+	"golang.org/x/net/context"
+)
+
+// InclusionError is returned when the log fails to prove that an entry it
+// served is included in its tree.
+type InclusionError struct {
+	Index    int64  // the index at which the log served the entry
+	LeafHash []byte // the Merkle leaf hash of the entry
+	TreeSize uint64 // the size of the tree the entry should be in
+	Err      error  // why the entry couldn't be shown to be in the tree
+}
+
+func (e InclusionError) Error() string {
+	return fmt.Sprintf("entry %d (leaf hash %x) not verified in tree of size %d: %v", e.Index, e.LeafHash, e.TreeSize, e.Err)
+}
+
+func sha256Hash(b []byte) []byte {
+	h := sha256.Sum256(b)
+	return h[:]
+}
+
+// LeafHash returns the Merkle tree hash of |leaf|, as used by the log when
+// building its tree.
+func LeafHash(leaf *ct.MerkleTreeLeaf) ([]byte, error) {
+	leafData, err := leafInput(leaf)
+	if err != nil {
+		return nil, err
+	}
+	return merkletree.NewTreeHasher(sha256Hash).HashLeaf(leafData), nil
+}
+
+func leafInput(leaf *ct.MerkleTreeLeaf) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := ct.SerializeMerkleTreeLeaf(&buf, leaf); err != nil {
+		return nil, fmt.Errorf("failed to serialize MerkleTreeLeaf: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Fetcher fetches entries from a log, verifying that each is included in the
+// tree with a given STH.
+type Fetcher struct {
+	client   *client.LogClient
+	sth      ct.SignedTreeHead
+	verifier merkletree.MerkleVerifier
+}
+
+// NewFetcher returns a Fetcher which fetches entries using |c| and checks that
+// they are in the tree described by |sth|. The caller is responsible for
+// having verified |sth|'s signature, e.g. by fetching it with a LogClient
+// created with the log's public key.
+func NewFetcher(c *client.LogClient, sth *ct.SignedTreeHead) *Fetcher {
+	return &Fetcher{
+		client:   c,
+		sth:      *sth,
+		verifier: merkletree.NewMerkleVerifier(sha256Hash),
+	}
+}
+
+// GetEntries retrieves the entries in the sequence [|start|, |end|] from the
+// log, as for LogClient.GetEntriesWithContext, and fetches an inclusion proof
+// for each of them in the Fetcher's tree. The log may return fewer entries than
+// were asked for.
+// Returns an InclusionError if any entry is not shown to be in the tree.
+func (f *Fetcher) GetEntries(ctx context.Context, start, end int64) ([]ct.LogEntry, error) {
+	if end >= int64(f.sth.TreeSize) {
+		return nil, fmt.Errorf("end %d is beyond tree of size %d", end, f.sth.TreeSize)
+	}
+	entries, err := f.client.GetEntriesWithContext(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(entries)) > end-start+1 {
+		return nil, fmt.Errorf("log returned %d entries, more than the %d requested", len(entries), end-start+1)
+	}
+	for i := range entries {
+		if err := f.VerifyEntry(ctx, &entries[i]); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// VerifyEntry fetches an inclusion proof for |entry| from the log, and checks
+// that it shows the entry to be at entry.Index in the Fetcher's tree.
+// Returns an InclusionError if it does not.
+func (f *Fetcher) VerifyEntry(ctx context.Context, entry *ct.LogEntry) error {
+	leafData, err := leafInput(&entry.Leaf)
+	if err != nil {
+		return err
+	}
+	leafHash := merkletree.NewTreeHasher(sha256Hash).HashLeaf(leafData)
+	inclusionError := func(err error) error {
+		return InclusionError{Index: entry.Index, LeafHash: leafHash, TreeSize: f.sth.TreeSize, Err: err}
+	}
+
+	proof, err := f.client.GetProofByHash(ctx, leafHash, f.sth.TreeSize)
+	if err != nil {
+		if rspErr, ok := err.(client.RspError); ok && rspErr.StatusCode >= 400 && rspErr.StatusCode < 500 {
+			// The log says it can't find the entry in its tree.
+			return inclusionError(err)
+		}
+		return err
+	}
+	if proof.LeafIndex != entry.Index {
+		return inclusionError(fmt.Errorf("log gave proof for index %d", proof.LeafIndex))
+	}
+	if err := f.verifier.VerifyInclusionProof(proof.LeafIndex, int64(f.sth.TreeSize), proof.AuditPath, f.sth.SHA256RootHash[:], leafData); err != nil {
+		return inclusionError(err)
+	}
+	return nil
+}
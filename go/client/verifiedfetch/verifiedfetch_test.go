@@ -0,0 +1,194 @@
+package verifiedfetch
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+	"github.com/google/certificate-transparency/go/merkletree"
+	"golang.org/x/net/context"
+)
+
+// emptyChain is the extra_data for an X509 entry with no intermediates.
+var emptyChain = []byte{0, 0, 0}
+
+// testEntry returns the get-entries data for the |i|'th test entry. Every
+// third entry is a precertificate.
+func testEntry(i int) client.LeafEntry {
+	leaf := ct.CreateX509MerkleTreeLeaf(ct.ASN1Cert(fmt.Sprintf("cert %d", i)), uint64(1000+i))
+	extraData := emptyChain
+	if i%3 == 2 {
+		leaf.TimestampedEntry.EntryType = ct.PrecertLogEntryType
+		leaf.TimestampedEntry.X509Entry = nil
+		leaf.TimestampedEntry.PrecertEntry = ct.PreCert{
+			IssuerKeyHash:  sha256.Sum256([]byte("issuer key")),
+			TBSCertificate: []byte(fmt.Sprintf("tbs %d", i)),
+		}
+		// The precertificate itself, followed by an empty chain.
+		precert := []byte(fmt.Sprintf("precert %d", i))
+		extraData = append([]byte{0, 0, byte(len(precert))}, precert...)
+		extraData = append(extraData, emptyChain...)
+	}
+	var buf bytes.Buffer
+	if err := ct.SerializeMerkleTreeLeaf(&buf, leaf); err != nil {
+		panic(err)
+	}
+	return client.LeafEntry{LeafInput: buf.Bytes(), ExtraData: extraData}
+}
+
+// fakeLog is a log whose tree holds |leaves|, but which serves |served| from
+// get-entries.
+type fakeLog struct {
+	tree   *merkletree.InMemoryMerkleTree
+	leaves map[string]int64 // leaf hash to index
+	served []client.LeafEntry
+	// indexOffset is added to the leaf index returned by get-proof-by-hash.
+	indexOffset int64
+}
+
+func newFakeLog(size int) *fakeLog {
+	l := &fakeLog{
+		tree:   merkletree.NewInMemoryMerkleTree(sha256Hash),
+		leaves: make(map[string]int64),
+	}
+	for i := 0; i < size; i++ {
+		entry := testEntry(i)
+		l.tree.AddLeaf(entry.LeafInput)
+		hash, _ := l.tree.LeafHash(uint64(i + 1))
+		l.leaves[string(hash)] = int64(i)
+		l.served = append(l.served, entry)
+	}
+	return l
+}
+
+func (l *fakeLog) sth() *ct.SignedTreeHead {
+	sth := &ct.SignedTreeHead{TreeSize: l.tree.LeafCount()}
+	root, _ := l.tree.CurrentRoot()
+	copy(sth.SHA256RootHash[:], root)
+	return sth
+}
+
+func (l *fakeLog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	switch r.URL.Path {
+	case client.GetEntriesPath:
+		start, _ := strconv.Atoi(q.Get("start"))
+		end, _ := strconv.Atoi(q.Get("end"))
+		var resp client.GetEntriesResponse
+		for i := start; i <= end && i < len(l.served); i++ {
+			resp.Entries = append(resp.Entries, l.served[i])
+		}
+		json.NewEncoder(w).Encode(resp)
+	case client.GetProofByHashPath:
+		hash, _ := base64.StdEncoding.DecodeString(q.Get("hash"))
+		treeSize, _ := strconv.ParseUint(q.Get("tree_size"), 10, 64)
+		index, ok := l.leaves[string(hash)]
+		if !ok || uint64(index) >= treeSize {
+			http.Error(w, "hash not found", http.StatusBadRequest)
+			return
+		}
+		path, err := l.tree.PathToRootAtSnapshot(uint64(index+1), treeSize)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(client.GetProofByHashResponse{LeafIndex: index + l.indexOffset, AuditPath: path})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestGetEntries(t *testing.T) {
+	l := newFakeLog(7)
+	hs := httptest.NewServer(l)
+	defer hs.Close()
+
+	f := NewFetcher(client.New(hs.URL, &http.Client{}), l.sth())
+	entries, err := f.GetEntries(context.Background(), 2, 6)
+	if err != nil {
+		t.Fatalf("GetEntries()=%v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("got %d entries, want 5", len(entries))
+	}
+	for i, e := range entries {
+		index := i + 2
+		te := e.Leaf.TimestampedEntry
+		if index%3 == 2 {
+			if want := fmt.Sprintf("tbs %d", index); te.EntryType != ct.PrecertLogEntryType || string(te.PrecertEntry.TBSCertificate) != want {
+				t.Errorf("entry %d is %v %q, want precert %q", index, te.EntryType, te.PrecertEntry.TBSCertificate, want)
+			}
+			if want := fmt.Sprintf("precert %d", index); len(e.Chain) != 1 || string(e.Chain[0]) != want {
+				t.Errorf("entry %d has chain %q, want [%q]", index, e.Chain, want)
+			}
+			continue
+		}
+		if want := ct.ASN1Cert(fmt.Sprintf("cert %d", index)); !bytes.Equal(te.X509Entry, want) {
+			t.Errorf("entry %d is %q, want %q", index, te.X509Entry, want)
+		}
+	}
+
+	if _, err := f.GetEntries(context.Background(), 5, 7); err == nil {
+		t.Error("GetEntries() beyond the tree succeeded")
+	}
+}
+
+func TestGetEntriesDetectsBadLog(t *testing.T) {
+	tests := []struct {
+		desc   string
+		tamper func(l *fakeLog, sth *ct.SignedTreeHead)
+	}{
+		{"entry not in tree", func(l *fakeLog, sth *ct.SignedTreeHead) {
+			l.served[3] = testEntry(100)
+		}},
+		{"entries swapped", func(l *fakeLog, sth *ct.SignedTreeHead) {
+			l.served[2], l.served[3] = l.served[3], l.served[2]
+		}},
+		{"precert not in tree", func(l *fakeLog, sth *ct.SignedTreeHead) {
+			l.served[5] = testEntry(101)
+		}},
+		{"proof for wrong index", func(l *fakeLog, sth *ct.SignedTreeHead) {
+			l.indexOffset = 1
+		}},
+		{"different tree", func(l *fakeLog, sth *ct.SignedTreeHead) {
+			sth.SHA256RootHash[0] ^= 1
+		}},
+	}
+	for _, test := range tests {
+		l := newFakeLog(7)
+		sth := l.sth()
+		test.tamper(l, sth)
+		hs := httptest.NewServer(l)
+		_, err := NewFetcher(client.New(hs.URL, &http.Client{}), sth).GetEntries(context.Background(), 0, 6)
+		hs.Close()
+		if _, ok := err.(InclusionError); !ok {
+			t.Errorf("%s: GetEntries()=%v, want InclusionError", test.desc, err)
+		}
+	}
+}
+
+func TestLeafHash(t *testing.T) {
+	l := newFakeLog(3)
+	for i := 0; i < 3; i++ {
+		leaf, err := ct.ReadMerkleTreeLeaf(bytes.NewReader(testEntry(i).LeafInput))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := LeafHash(leaf)
+		if err != nil {
+			t.Fatalf("LeafHash()=%v", err)
+		}
+		want, _ := l.tree.LeafHash(uint64(i + 1))
+		if !bytes.Equal(got, want) {
+			t.Errorf("LeafHash(%d)=%x, want %x", i, got, want)
+		}
+	}
+}
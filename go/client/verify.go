@@ -0,0 +1,46 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	ct "github.com/google/certificate-transparency/go"
+)
+
+// verifySCT checks that |sct|, returned by the log's |path| endpoint in
+// response to the submission of |chain|, was issued by this log and carries a
+// valid signature over the submitted [pre-]certificate.
+func (c *LogClient) verifySCT(path string, sct *ct.SignedCertificateTimestamp, chain []ct.ASN1Cert) error {
+	if sct.LogID != c.logID {
+		return SignatureVerificationError{
+			Endpoint: path,
+			Err:      fmt.Errorf("SCT has LogID %s, want %s", sct.LogID.Base64String(), c.logID.Base64String()),
+		}
+	}
+	leaf, err := merkleTreeLeafForChain(path, chain, sct.Timestamp)
+	if err != nil {
+		return fmt.Errorf("failed to build log entry for SCT verification: %v", err)
+	}
+	leaf.TimestampedEntry.Extensions = sct.Extensions
+	if err := c.verifier.VerifySCTSignature(*sct, ct.LogEntry{Leaf: *leaf}); err != nil {
+		return SignatureVerificationError{Endpoint: path, Err: err}
+	}
+	return nil
+}
+
+// merkleTreeLeafForChain builds the MerkleTreeLeaf that a log will have signed
+// over when issuing an SCT with |timestamp| for |chain|, submitted to |path|.
+func merkleTreeLeafForChain(path string, chain []ct.ASN1Cert, timestamp uint64) (*ct.MerkleTreeLeaf, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("empty chain")
+	}
+	switch path {
+	case AddChainPath:
+		return ct.CreateX509MerkleTreeLeaf(chain[0], timestamp), nil
+	case AddPreChainPath:
+		leaf, _, err := ct.CreatePrecertMerkleTreeLeaf(chain, timestamp)
+		return leaf, err
+	default:
+		return nil, fmt.Errorf("don't know how to build a log entry for %s", path)
+	}
+}
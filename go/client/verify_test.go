@@ -0,0 +1,190 @@
+package client_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+	"github.com/google/certificate-transparency/go/ctfake"
+	"github.com/google/certificate-transparency/go/testonly"
+)
+
+func readTestSCT(t *testing.T, name string) *ct.SignedCertificateTimestamp {
+	sct, err := ct.DeserializeSCT(bytes.NewReader(testonly.ReadFile(t, name)))
+	if err != nil {
+		t.Fatalf("Failed to deserialize SCT from %s: %v", name, err)
+	}
+	return sct
+}
+
+// sctServer returns a test server which responds to every request with |sct|.
+// It serves SCTs issued by the reference log, which a ctfake log can't.
+func sctServer(t *testing.T, sct *ct.SignedCertificateTimestamp) *httptest.Server {
+	sig, err := ct.MarshalDigitallySigned(sct.Signature)
+	if err != nil {
+		t.Fatalf("Failed to marshal signature: %v", err)
+	}
+	resp, err := json.Marshal(client.AddChainResponse{
+		SCTVersion: sct.SCTVersion,
+		ID:         sct.LogID[:],
+		Timestamp:  sct.Timestamp,
+		Extensions: string(sct.Extensions),
+		Signature:  sig,
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal response: %v", err)
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(resp)
+	}))
+}
+
+func TestNewWithPubKey(t *testing.T) {
+	if _, err := client.NewWithPubKey("http://127.0.0.1", nil, string(testonly.ReadFile(t, "ct-server-key-public.pem"))); err != nil {
+		t.Fatalf("NewWithPubKey()=%v", err)
+	}
+	if _, err := client.NewWithPubKey("http://127.0.0.1", nil, "not a key"); err == nil {
+		t.Fatal("NewWithPubKey() with invalid key succeeded, want error")
+	}
+}
+
+func TestGetSTHVerifiesSignature(t *testing.T) {
+	l := newLog(t)
+	defer l.Close()
+	c := newVerifyingClient(t, l, l)
+	sth, err := c.GetSTH()
+	if err != nil {
+		t.Fatalf("GetSTH()=%v", err)
+	}
+	if sth.LogID != l.LogID() {
+		t.Fatalf("GetSTH() returned LogID %s, want %s", sth.LogID.Base64String(), l.LogID().Base64String())
+	}
+
+	l.SetMisbehaviour(ctfake.Misbehaviour{BadSignatures: true})
+	_, err = c.GetSTH()
+	if _, ok := err.(client.SignatureVerificationError); !ok {
+		t.Fatalf("GetSTH() with bad signature returned %v, want SignatureVerificationError", err)
+	}
+
+	// A client constructed from a different key should also reject the STH.
+	l.SetMisbehaviour(ctfake.Misbehaviour{})
+	other := newLog(t)
+	defer other.Close()
+	_, err = newVerifyingClient(t, l, other).GetSTH()
+	if _, ok := err.(client.SignatureVerificationError); !ok {
+		t.Fatalf("GetSTH() with wrong key returned %v, want SignatureVerificationError", err)
+	}
+}
+
+func TestAddChainVerifiesSCT(t *testing.T) {
+	l := newLog(t)
+	defer l.Close()
+	sct, err := newVerifyingClient(t, l, l).AddChain(testChain(t, "test-cert.pem"))
+	if err != nil {
+		t.Fatalf("AddChain()=%v", err)
+	}
+	if got, want := ct.SHA256Hash(sct.LogID), l.LogID(); got != want {
+		t.Fatalf("AddChain() returned SCT with LogID %s, want %s", got.Base64String(), want.Base64String())
+	}
+
+	bad := newLog(t)
+	defer bad.Close()
+	bad.SetMisbehaviour(ctfake.Misbehaviour{BadSignatures: true})
+	_, err = newVerifyingClient(t, bad, bad).AddChain(testChain(t, "test-cert.pem"))
+	if _, ok := err.(client.SignatureVerificationError); !ok {
+		t.Fatalf("AddChain() with bad SCT returned %v, want SignatureVerificationError", err)
+	}
+}
+
+func TestAddChainChecksLogID(t *testing.T) {
+	l := newLog(t)
+	defer l.Close()
+	other := newLog(t)
+	defer other.Close()
+	_, err := newVerifyingClient(t, l, other).AddChain(testChain(t, "test-cert.pem"))
+	if _, ok := err.(client.SignatureVerificationError); !ok {
+		t.Fatalf("AddChain() with wrong LogID returned %v, want SignatureVerificationError", err)
+	}
+}
+
+func TestAddPreChainVerifiesSCT(t *testing.T) {
+	for _, badSignatures := range []bool{false, true} {
+		l := newLog(t)
+		l.SetMisbehaviour(ctfake.Misbehaviour{BadSignatures: badSignatures})
+		_, err := newVerifyingClient(t, l, l).AddPreChain(testChain(t, "test-embedded-pre-cert.pem"))
+		l.Close()
+		if _, ok := err.(client.SignatureVerificationError); ok != badSignatures {
+			t.Errorf("AddPreChain() with bad signatures %v returned %v", badSignatures, err)
+		}
+	}
+}
+
+// The reference log's SCTs check that the client builds precertificate
+// leaves the same way as other implementations, not just as ctfake does.
+func TestAddPreChainVerifiesReferenceSCTs(t *testing.T) {
+	pemKey := string(testonly.ReadFile(t, "ct-server-key-public.pem"))
+	for _, test := range []struct {
+		precert string
+		issuers []string
+	}{
+		{"test-embedded-pre-cert", []string{"ca-cert.pem"}},
+		{"test-embedded-with-preca-pre-cert", []string{"ca-pre-cert.pem", "ca-cert.pem"}},
+		{"test-embedded-with-intermediate-pre-cert", []string{"intermediate-cert.pem", "ca-cert.pem"}},
+		{"test-embedded-with-intermediate-preca-pre-cert", []string{"intermediate-pre-cert.pem", "intermediate-cert.pem", "ca-cert.pem"}},
+	} {
+		chain := []ct.ASN1Cert{testonly.ReadPEM(t, test.precert+".pem")}
+		for _, issuer := range test.issuers {
+			chain = append(chain, testonly.ReadPEM(t, issuer))
+		}
+		sct := readTestSCT(t, test.precert+".proof")
+		ts := sctServer(t, sct)
+		c, err := client.NewWithPubKey(ts.URL, &http.Client{}, pemKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.AddPreChain(chain); err != nil {
+			t.Errorf("%s: AddPreChain()=%v", test.precert, err)
+		}
+		// Submitting the same chain to add-chain must fail, since the log
+		// signed over the precertificate's TBSCertificate.
+		_, err = c.AddChain(chain)
+		if _, ok := err.(client.SignatureVerificationError); !ok {
+			t.Errorf("%s: AddChain() of precert returned %v, want SignatureVerificationError", test.precert, err)
+		}
+		ts.Close()
+	}
+}
+
+func TestAddPreChainNeedsIssuer(t *testing.T) {
+	ts := sctServer(t, readTestSCT(t, "test-embedded-pre-cert.proof"))
+	defer ts.Close()
+	c, err := client.NewWithPubKey(ts.URL, &http.Client{}, string(testonly.ReadFile(t, "ct-server-key-public.pem")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := []ct.ASN1Cert{testonly.ReadPEM(t, "test-embedded-pre-cert.pem")}
+	_, err = c.AddPreChain(chain)
+	if err == nil {
+		t.Fatal("AddPreChain() without issuer succeeded, want error")
+	}
+	if _, ok := err.(client.SignatureVerificationError); ok {
+		t.Fatalf("AddPreChain() without issuer returned %v, want non-signature error", err)
+	}
+	chain = []ct.ASN1Cert{testonly.ReadPEM(t, "test-embedded-with-preca-pre-cert.pem"), testonly.ReadPEM(t, "ca-pre-cert.pem")}
+	if _, err := c.AddPreChain(chain); err == nil {
+		t.Fatal("AddPreChain() without final issuer succeeded, want error")
+	}
+}
+
+func TestUnverifiedClientAcceptsAnySCT(t *testing.T) {
+	l := newLog(t)
+	defer l.Close()
+	l.SetMisbehaviour(ctfake.Misbehaviour{BadSignatures: true})
+	if _, err := client.New(l.URL(), &http.Client{}).AddChain(testChain(t, "test-cert.pem")); err != nil {
+		t.Fatalf("AddChain()=%v", err)
+	}
+}
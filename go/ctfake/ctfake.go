@@ -0,0 +1,249 @@
+// Package ctfake provides an in-process fake CT log for tests. A Log serves
+// all of the RFC6962 /ct/v1/* endpoints from an httptest.Server, using a
+// server.Server with in-memory storage, and signs SCTs and STHs with a key
+// generated when it starts. Unlike a real log it integrates each entry into
+// the tree as soon as it is added. It can also be told to misbehave in various
+// ways, so that clients' handling of bad logs can be tested without a network.
+package ctfake
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/merkletree"
+	"github.com/google/certificate-transparency/go/server"
+	ctx509 "github.com/google/certificate-transparency/go/x509"
+)
+
+// Config configures a fake Log.
+type Config struct {
+	// Roots holds the DER encoded certificates which submitted chains must
+	// chain to. At least one root is required.
+	Roots []ct.ASN1Cert
+	// Now returns the current time, used to timestamp SCTs and STHs.
+	// Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Misbehaviour describes the ways in which a Log deviates from RFC6962.
+// The zero value is a well behaved log.
+type Misbehaviour struct {
+	// MaxEntries limits the number of entries returned by each get-entries
+	// request; zero means no limit.
+	MaxEntries int
+	// Unavailable is the number of subsequent requests which will fail with
+	// 503 Service Unavailable.
+	Unavailable int
+	// RetryAfter is sent in the Retry-After header of those 503s, if non-zero.
+	RetryAfter time.Duration
+	// ForkSTH makes get-sth return (correctly signed) STHs for a tree whose
+	// last entry differs from the one served by get-entries, and which is
+	// therefore inconsistent with the log's other STHs and proofs.
+	ForkSTH bool
+	// BadSignatures makes the signatures on all SCTs and STHs invalid.
+	BadSignatures bool
+}
+
+// Log is a fake CT log.
+type Log struct {
+	httpServer *httptest.Server
+	server     *server.Server
+	storage    storage
+	key        *ecdsa.PrivateKey
+	signer     *ct.Signer // signs forked STHs
+	now        func() time.Time
+
+	mu           sync.Mutex
+	misbehaviour Misbehaviour
+}
+
+func sha256Hash(b []byte) []byte {
+	h := sha256.Sum256(b)
+	return h[:]
+}
+
+// NewLog starts a new, empty, fake log. The caller must Close it when done.
+func NewLog(cfg Config) (*Log, error) {
+	if len(cfg.Roots) == 0 {
+		return nil, errors.New("no roots")
+	}
+	var roots []*ctx509.Certificate
+	for i, der := range cfg.Roots {
+		root, err := ctx509.ParseCertificate(der)
+		if ctx509.IsFatal(err) {
+			return nil, fmt.Errorf("failed to parse root %d: %v", i, err)
+		}
+		roots = append(roots, root)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate log key: %v", err)
+	}
+	l := &Log{key: key, now: cfg.Now}
+	if l.now == nil {
+		l.now = time.Now
+	}
+	if l.signer, err = ct.NewSigner(signer{key, l}); err != nil {
+		return nil, err
+	}
+	l.storage = storage{server.NewMemoryStorage(), l}
+	l.server, err = server.New(server.Config{
+		Signer:  signer{key, l},
+		Roots:   roots,
+		Storage: l.storage,
+		Now:     l.now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	l.httpServer = httptest.NewServer(l.handler())
+	return l, nil
+}
+
+// URL returns the base URI of the log, for passing to client.New.
+func (l *Log) URL() string {
+	return l.httpServer.URL
+}
+
+// Close shuts down the log's server.
+func (l *Log) Close() {
+	l.httpServer.Close()
+}
+
+// PublicKey returns the log's public key.
+func (l *Log) PublicKey() crypto.PublicKey {
+	return l.key.Public()
+}
+
+// PublicKeyPEM returns the log's public key in PEM format, as accepted by
+// client.NewWithPubKey.
+func (l *Log) PublicKeyPEM() string {
+	der, _ := x509.MarshalPKIXPublicKey(l.key.Public())
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// LogID returns the log's ID, the SHA-256 hash of its public key.
+func (l *Log) LogID() ct.SHA256Hash {
+	return l.server.LogID()
+}
+
+// SetMisbehaviour changes the way the log deviates from RFC6962.
+func (l *Log) SetMisbehaviour(m Misbehaviour) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.misbehaviour = m
+}
+
+func (l *Log) currentMisbehaviour() Misbehaviour {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.misbehaviour
+}
+
+// TreeSize returns the number of entries in the log.
+func (l *Log) TreeSize() uint64 {
+	size, _ := l.storage.TreeSize()
+	return uint64(size)
+}
+
+// AddChain adds the X509 |chain| to the log, as the add-chain endpoint does.
+func (l *Log) AddChain(chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return l.sequenced(l.server.AddChain(chain))
+}
+
+// AddPreChain adds the precertificate |chain| to the log, as the
+// add-pre-chain endpoint does.
+func (l *Log) AddPreChain(chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return l.sequenced(l.server.AddPreChain(chain))
+}
+
+// AddJSON adds |data| to the log, as the add-json endpoint does.
+func (l *Log) AddJSON(data interface{}) (*ct.SignedCertificateTimestamp, error) {
+	return l.sequenced(l.server.AddJSON(data))
+}
+
+// sequenced integrates the entry for which |sct| was just issued into the
+// tree, rather than leaving it pending as a real log would.
+func (l *Log) sequenced(sct *ct.SignedCertificateTimestamp, err error) (*ct.SignedCertificateTimestamp, error) {
+	if err != nil {
+		return nil, err
+	}
+	if err := l.server.Sequence(); err != nil {
+		return nil, err
+	}
+	return sct, nil
+}
+
+// STH returns a freshly signed tree head for the current tree, subject to any
+// misbehaviour which affects get-sth.
+func (l *Log) STH() (*ct.SignedTreeHead, error) {
+	if err := l.server.Sequence(); err != nil {
+		return nil, err
+	}
+	return l.storage.LatestSTH()
+}
+
+// signer is the log's key, which produces invalid signatures when the log has
+// been told to.
+type signer struct {
+	*ecdsa.PrivateKey
+	l *Log
+}
+
+func (s signer) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	sig, err := s.PrivateKey.Sign(rand, digest, opts)
+	if err == nil && s.l.currentMisbehaviour().BadSignatures {
+		sig[len(sig)-1] ^= 1
+	}
+	return sig, err
+}
+
+// storage is the log's server.Storage, which returns forked STHs when the log
+// has been told to.
+type storage struct {
+	*server.MemoryStorage
+	l *Log
+}
+
+func (s storage) LatestSTH() (*ct.SignedTreeHead, error) {
+	sth, err := s.MemoryStorage.LatestSTH()
+	if err != nil || sth == nil || sth.TreeSize == 0 || !s.l.currentMisbehaviour().ForkSTH {
+		return sth, err
+	}
+	return s.fork(sth)
+}
+
+// fork returns an STH with the same size and timestamp as |sth|, signed by
+// the log, for a tree whose last entry differs from the log's.
+func (s storage) fork(sth *ct.SignedTreeHead) (*ct.SignedTreeHead, error) {
+	tree := merkletree.NewInMemoryMerkleTree(sha256Hash)
+	if sth.TreeSize > 1 {
+		entries, err := s.GetEntries(0, int64(sth.TreeSize)-2)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			tree.AddLeaf(e.LeafInput)
+		}
+	}
+	tree.AddLeaf([]byte("fork"))
+	root, err := tree.CurrentRoot()
+	if err != nil {
+		return nil, err
+	}
+	var rootHash ct.SHA256Hash
+	copy(rootHash[:], root)
+	return s.l.signer.CreateSTH(sth.TreeSize, sth.Timestamp, rootHash)
+}
@@ -0,0 +1,231 @@
+package ctfake
+
+import (
+	"bytes"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+	"github.com/google/certificate-transparency/go/client/verifiedfetch"
+	"github.com/google/certificate-transparency/go/merkletree"
+	"github.com/google/certificate-transparency/go/testonly"
+	"golang.org/x/net/context"
+)
+
+// newTestLog returns a log which accepts chains issued by ca-cert.pem, and a
+// client for it which verifies signatures.
+func newTestLog(t *testing.T, opts ...client.Option) (*Log, *client.LogClient) {
+	l, err := NewLog(Config{Roots: []ct.ASN1Cert{testonly.ReadPEM(t, "ca-cert.pem")}})
+	if err != nil {
+		t.Fatalf("NewLog()=%v", err)
+	}
+	c, err := client.NewWithPubKey(l.URL(), &http.Client{}, l.PublicKeyPEM(), opts...)
+	if err != nil {
+		l.Close()
+		t.Fatalf("NewWithPubKey()=%v", err)
+	}
+	return l, c
+}
+
+func sha256Verifier() merkletree.MerkleVerifier {
+	return merkletree.NewMerkleVerifier(sha256Hash)
+}
+
+func TestEndToEnd(t *testing.T) {
+	l, c := newTestLog(t)
+	defer l.Close()
+	ctx := context.Background()
+	ca := testonly.ReadPEM(t, "ca-cert.pem")
+
+	// The client verifies the signatures on SCTs and STHs.
+	sct, err := c.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-cert.pem"), ca})
+	if err != nil {
+		t.Fatalf("AddChain()=%v", err)
+	}
+	if sct.LogID != l.LogID() {
+		t.Errorf("SCT has LogID %x, want %x", sct.LogID, l.LogID())
+	}
+	again, err := c.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-cert.pem"), ca})
+	if err != nil || again.Timestamp != sct.Timestamp {
+		t.Errorf("resubmitting chain gave %v, %v; want original SCT", again, err)
+	}
+	if _, err := c.AddPreChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-embedded-pre-cert.pem"), ca}); err != nil {
+		t.Fatalf("AddPreChain()=%v", err)
+	}
+	sth1, err := c.GetSTHWithContext(ctx)
+	if err != nil {
+		t.Fatalf("GetSTH()=%v", err)
+	}
+	if sth1.TreeSize != 2 {
+		t.Fatalf("STH has TreeSize %d, want 2", sth1.TreeSize)
+	}
+
+	// Entries are served, and are included in the tree.
+	entries, err := verifiedfetch.NewFetcher(c, sth1).GetEntries(ctx, 0, 1)
+	if err != nil {
+		t.Fatalf("GetEntries()=%v", err)
+	}
+	if got := entries[0].Leaf.TimestampedEntry.EntryType; got != ct.X509LogEntryType {
+		t.Errorf("entry 0 has type %v", got)
+	}
+	if got := entries[1].Leaf.TimestampedEntry.EntryType; got != ct.PrecertLogEntryType {
+		t.Errorf("entry 1 has type %v", got)
+	}
+	if len(entries[1].Chain) != 2 || !bytes.Equal(entries[1].Chain[1], ca) {
+		t.Errorf("precert entry has chain of length %d", len(entries[1].Chain))
+	}
+
+	rsp, err := c.GetEntryAndProof(ctx, 1, sth1.TreeSize)
+	if err != nil {
+		t.Fatalf("GetEntryAndProof()=%v", err)
+	}
+	if err := sha256Verifier().VerifyInclusionProof(1, int64(sth1.TreeSize), rsp.AuditPath, sth1.SHA256RootHash[:], rsp.LeafInput); err != nil {
+		t.Errorf("VerifyInclusionProof()=%v", err)
+	}
+
+	// The tree grows consistently.
+	if _, err := c.AddJSON(map[string]string{"a": "b"}); err != nil {
+		t.Fatalf("AddJSON()=%v", err)
+	}
+	sth2, err := c.GetSTHWithContext(ctx)
+	if err != nil {
+		t.Fatalf("GetSTH()=%v", err)
+	}
+	proof, err := c.GetSTHConsistency(ctx, sth1.TreeSize, sth2.TreeSize)
+	if err != nil {
+		t.Fatalf("GetSTHConsistency()=%v", err)
+	}
+	if err := sha256Verifier().VerifyConsistencyProof(int64(sth1.TreeSize), int64(sth2.TreeSize), sth1.SHA256RootHash[:], sth2.SHA256RootHash[:], proof); err != nil {
+		t.Errorf("VerifyConsistencyProof()=%v", err)
+	}
+
+	roots, err := c.GetAcceptedRoots(ctx)
+	if err != nil {
+		t.Fatalf("GetAcceptedRoots()=%v", err)
+	}
+	if len(roots) != 1 || !bytes.Equal(roots[0].Raw, ca) {
+		t.Errorf("GetAcceptedRoots() returned %d roots, want ca-cert.pem", len(roots))
+	}
+}
+
+func TestRootsEnforced(t *testing.T) {
+	l, c := newTestLog(t)
+	defer l.Close()
+
+	// test-cert.pem is issued by the root, so may be submitted without it.
+	if _, err := c.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-cert.pem")}); err != nil {
+		t.Errorf("AddChain(issued by root)=%v", err)
+	}
+	_, err := c.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, "google-cert.pem")})
+	if rspErr, ok := err.(client.RspError); !ok || rspErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("AddChain(unknown root)=%v, want RspError with status 400", err)
+	}
+	_, err = c.AddPreChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-cert.pem"), testonly.ReadPEM(t, "ca-cert.pem")})
+	if rspErr, ok := err.(client.RspError); !ok || rspErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("AddPreChain(not a precert)=%v, want RspError with status 400", err)
+	}
+}
+
+func TestMaxEntries(t *testing.T) {
+	l, c := newTestLog(t)
+	defer l.Close()
+	// All of these are issued by ca-cert.pem.
+	for _, name := range []string{"test-cert.pem", "test-embedded-cert.pem", "intermediate-cert.pem"} {
+		if _, err := l.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, name)}); err != nil {
+			t.Fatalf("AddChain(%s)=%v", name, err)
+		}
+	}
+	l.SetMisbehaviour(Misbehaviour{MaxEntries: 2})
+
+	entries, err := c.GetEntries(0, 2)
+	if err != nil {
+		t.Fatalf("GetEntries()=%v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("GetEntries() returned %d entries, want 2", len(entries))
+	}
+	it := c.NewEntryIterator(context.Background(), 0, 2, 0)
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if err := it.Err(); err != nil || n != 3 {
+		t.Errorf("EntryIterator returned %d entries and error %v, want 3 entries", n, err)
+	}
+}
+
+// fakeClock is a client.Clock which never blocks, but records each wait.
+type fakeClock struct {
+	waits []time.Duration
+}
+
+func (f *fakeClock) Now() time.Time {
+	return time.Unix(0, 0)
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.waits = append(f.waits, d)
+	c := make(chan time.Time, 1)
+	c <- time.Unix(0, 0)
+	return c
+}
+
+func TestUnavailable(t *testing.T) {
+	clock := &fakeClock{}
+	l, c := newTestLog(t, client.WithClock(clock), client.WithRetryPolicy(client.RetryPolicy{InitialBackoff: time.Second}))
+	defer l.Close()
+	l.SetMisbehaviour(Misbehaviour{Unavailable: 2, RetryAfter: 7 * time.Second})
+
+	if _, err := c.GetSTH(); err != nil {
+		t.Fatalf("GetSTH()=%v", err)
+	}
+	if want := []time.Duration{7 * time.Second, 7 * time.Second}; !reflect.DeepEqual(clock.waits, want) {
+		t.Errorf("client waited %v, want %v", clock.waits, want)
+	}
+}
+
+func TestBadSignatures(t *testing.T) {
+	l, c := newTestLog(t)
+	defer l.Close()
+	l.SetMisbehaviour(Misbehaviour{BadSignatures: true})
+
+	if _, err := c.GetSTH(); err == nil {
+		t.Error("GetSTH() accepted bad signature")
+	} else if _, ok := err.(client.SignatureVerificationError); !ok {
+		t.Errorf("GetSTH()=%v, want SignatureVerificationError", err)
+	}
+	if _, err := c.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-cert.pem")}); err == nil {
+		t.Error("AddChain() accepted bad signature")
+	} else if _, ok := err.(client.SignatureVerificationError); !ok {
+		t.Errorf("AddChain()=%v, want SignatureVerificationError", err)
+	}
+}
+
+func TestForkSTH(t *testing.T) {
+	l, c := newTestLog(t)
+	defer l.Close()
+	ctx := context.Background()
+	if _, err := c.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-cert.pem")}); err != nil {
+		t.Fatalf("AddChain()=%v", err)
+	}
+	sth, err := c.GetSTHWithContext(ctx)
+	if err != nil {
+		t.Fatalf("GetSTH()=%v", err)
+	}
+
+	l.SetMisbehaviour(Misbehaviour{ForkSTH: true})
+	forked, err := c.GetSTHWithContext(ctx)
+	if err != nil {
+		t.Fatalf("GetSTH()=%v, want correctly signed fork", err)
+	}
+	if forked.TreeSize != sth.TreeSize || forked.SHA256RootHash == sth.SHA256RootHash {
+		t.Fatalf("forked STH %v should have the same size as, and different root to, %v", forked, sth)
+	}
+	// The served entries aren't in the forked tree.
+	if _, err := verifiedfetch.NewFetcher(c, forked).GetEntries(ctx, 0, 0); err == nil {
+		t.Error("GetEntries() verified entry in forked tree")
+	}
+}
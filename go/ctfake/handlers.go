@@ -0,0 +1,67 @@
+package ctfake
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/certificate-transparency/go/client"
+)
+
+// handler returns the log's http.Handler, which applies any misbehaviour to
+// requests before passing them on to the server.Server.
+func (l *Log) handler() http.Handler {
+	h := l.server.Handler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.unavailable(w) {
+			return
+		}
+		// Integrate any new entries, and sign a fresh STH, both before and
+		// after serving each request, so that the log never has pending
+		// entries and get-sth reflects the current time and misbehaviour.
+		if err := l.server.Sequence(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer l.server.Sequence()
+		if r.URL.Path == client.GetEntriesPath {
+			l.limitEntries(r)
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// unavailable sends a 503 response and returns true if the log has been told
+// to fail this request.
+func (l *Log) unavailable(w http.ResponseWriter) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.misbehaviour.Unavailable <= 0 {
+		return false
+	}
+	l.misbehaviour.Unavailable--
+	if l.misbehaviour.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(l.misbehaviour.RetryAfter.Seconds())))
+	}
+	http.Error(w, "log unavailable", http.StatusServiceUnavailable)
+	return true
+}
+
+// limitEntries reduces the end of the get-entries request |r| so that no more
+// than Misbehaviour.MaxEntries entries are returned.
+func (l *Log) limitEntries(r *http.Request) {
+	max := uint64(l.currentMisbehaviour().MaxEntries)
+	if max == 0 {
+		return
+	}
+	q := r.URL.Query()
+	start, err := strconv.ParseUint(q.Get("start"), 10, 64)
+	if err != nil {
+		return
+	}
+	end, err := strconv.ParseUint(q.Get("end"), 10, 64)
+	if err != nil || end < start || end-start < max {
+		return
+	}
+	q.Set("end", strconv.FormatUint(start+max-1, 10))
+	r.URL.RawQuery = q.Encode()
+}
@@ -0,0 +1,336 @@
+package ctpb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/golang/protobuf/proto"
+	ct "github.com/google/certificate-transparency/go"
+)
+
+// DigitallySignedToProto returns the protobuf form of |ds|.
+func DigitallySignedToProto(ds ct.DigitallySigned) (*DigitallySigned, error) {
+	hash := DigitallySigned_HashAlgorithm(ds.HashAlgorithm)
+	if _, ok := DigitallySigned_HashAlgorithm_name[int32(hash)]; !ok {
+		return nil, fmt.Errorf("unknown hash algorithm %v", ds.HashAlgorithm)
+	}
+	sig := DigitallySigned_SignatureAlgorithm(ds.SignatureAlgorithm)
+	if _, ok := DigitallySigned_SignatureAlgorithm_name[int32(sig)]; !ok {
+		return nil, fmt.Errorf("unknown signature algorithm %v", ds.SignatureAlgorithm)
+	}
+	return &DigitallySigned{
+		HashAlgorithm: hash.Enum(),
+		SigAlgorithm:  sig.Enum(),
+		Signature:     ds.Signature,
+	}, nil
+}
+
+// DigitallySignedFromProto returns the ct.DigitallySigned equivalent of |pb|.
+func DigitallySignedFromProto(pb *DigitallySigned) (*ct.DigitallySigned, error) {
+	if pb == nil {
+		return nil, errors.New("missing signature")
+	}
+	hash := pb.GetHashAlgorithm()
+	if _, ok := DigitallySigned_HashAlgorithm_name[int32(hash)]; !ok {
+		return nil, fmt.Errorf("unknown hash algorithm %d", hash)
+	}
+	sig := pb.GetSigAlgorithm()
+	if _, ok := DigitallySigned_SignatureAlgorithm_name[int32(sig)]; !ok {
+		return nil, fmt.Errorf("unknown signature algorithm %d", sig)
+	}
+	if len(pb.Signature) > math.MaxUint16 {
+		return nil, fmt.Errorf("signature too long: %d bytes", len(pb.Signature))
+	}
+	return &ct.DigitallySigned{
+		HashAlgorithm:      ct.HashAlgorithm(hash),
+		SignatureAlgorithm: ct.SignatureAlgorithm(sig),
+		Signature:          pb.Signature,
+	}, nil
+}
+
+// SCTToProto returns the protobuf form of |sct|.
+func SCTToProto(sct ct.SignedCertificateTimestamp) (*SignedCertificateTimestamp, error) {
+	if sct.SCTVersion != ct.V1 {
+		return nil, fmt.Errorf("unsupported SCT version %v", sct.SCTVersion)
+	}
+	sig, err := DigitallySignedToProto(sct.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedCertificateTimestamp{
+		Version:    Version_V1.Enum(),
+		Id:         logIDToProto(sct.LogID),
+		Timestamp:  proto.Uint64(sct.Timestamp),
+		Signature:  sig,
+		Extensions: sct.Extensions,
+	}, nil
+}
+
+// SCTFromProto returns the ct.SignedCertificateTimestamp equivalent of |pb|.
+// Only V1 SCTs can be converted.
+func SCTFromProto(pb *SignedCertificateTimestamp) (*ct.SignedCertificateTimestamp, error) {
+	if pb == nil {
+		return nil, errors.New("missing SCT")
+	}
+	if v := pb.GetVersion(); v != Version_V1 {
+		return nil, fmt.Errorf("unsupported SCT version %v", v)
+	}
+	if len(pb.SctExtension) > 0 {
+		return nil, errors.New("V1 SCT has V2 extensions")
+	}
+	logID, err := logIDFromProto(pb.Id)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := DigitallySignedFromProto(pb.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return &ct.SignedCertificateTimestamp{
+		SCTVersion: ct.V1,
+		LogID:      logID,
+		Timestamp:  pb.GetTimestamp(),
+		Extensions: pb.Extensions,
+		Signature:  *sig,
+	}, nil
+}
+
+// STHToProto returns the protobuf form of |sth|.
+func STHToProto(sth ct.SignedTreeHead) (*SignedTreeHead, error) {
+	if sth.Version != ct.V1 {
+		return nil, fmt.Errorf("unsupported STH version %v", sth.Version)
+	}
+	if sth.TreeSize > math.MaxInt64 {
+		return nil, fmt.Errorf("tree size %d too large", sth.TreeSize)
+	}
+	sig, err := DigitallySignedToProto(sth.TreeHeadSignature)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedTreeHead{
+		Version:        Version_V1.Enum(),
+		Id:             logIDToProto(sth.LogID),
+		Timestamp:      proto.Uint64(sth.Timestamp),
+		TreeSize:       proto.Int64(int64(sth.TreeSize)),
+		Sha256RootHash: append([]byte(nil), sth.SHA256RootHash[:]...),
+		Signature:      sig,
+	}, nil
+}
+
+// STHFromProto returns the ct.SignedTreeHead equivalent of |pb|.
+// Only V1 STHs can be converted.
+func STHFromProto(pb *SignedTreeHead) (*ct.SignedTreeHead, error) {
+	if pb == nil {
+		return nil, errors.New("missing STH")
+	}
+	if v := pb.GetVersion(); v != Version_V1 {
+		return nil, fmt.Errorf("unsupported STH version %v", v)
+	}
+	if len(pb.SthExtension) > 0 {
+		return nil, errors.New("V1 STH has V2 extensions")
+	}
+	if pb.GetTreeSize() < 0 {
+		return nil, fmt.Errorf("negative tree size %d", pb.GetTreeSize())
+	}
+	logID, err := logIDFromProto(pb.Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(pb.Sha256RootHash) != sha256.Size {
+		return nil, fmt.Errorf("invalid root hash length %d", len(pb.Sha256RootHash))
+	}
+	sig, err := DigitallySignedFromProto(pb.Signature)
+	if err != nil {
+		return nil, err
+	}
+	sth := &ct.SignedTreeHead{
+		Version:           ct.V1,
+		TreeSize:          uint64(pb.GetTreeSize()),
+		Timestamp:         pb.GetTimestamp(),
+		TreeHeadSignature: *sig,
+		LogID:             logID,
+	}
+	copy(sth.SHA256RootHash[:], pb.Sha256RootHash)
+	return sth, nil
+}
+
+// LogEntryToProto returns the protobuf form of |entry|. The protobuf doesn't
+// hold the entry's index, timestamp or extensions; see LoggedEntryToProto.
+// For Precertificate entries the first certificate in entry.Chain must be the
+// Precertificate itself, as it is in entries from get-entries.
+func LogEntryToProto(entry *ct.LogEntry) (*LogEntry, error) {
+	te := entry.Leaf.TimestampedEntry
+	switch te.EntryType {
+	case ct.X509LogEntryType:
+		return &LogEntry{
+			Type: LogEntryType_X509_ENTRY.Enum(),
+			X509Entry: &X509ChainEntry{
+				LeafCertificate:  te.X509Entry,
+				CertificateChain: chainToProto(entry.Chain),
+			},
+		}, nil
+	case ct.PrecertLogEntryType:
+		if len(entry.Chain) == 0 {
+			return nil, errors.New("precertificate entry has no precertificate in its chain")
+		}
+		return &LogEntry{
+			Type: LogEntryType_PRECERT_ENTRY.Enum(),
+			PrecertEntry: &PrecertChainEntry{
+				PreCertificate:      entry.Chain[0],
+				PrecertificateChain: chainToProto(entry.Chain[1:]),
+				PreCert: &PreCert{
+					IssuerKeyHash:  append([]byte(nil), te.PrecertEntry.IssuerKeyHash[:]...),
+					TbsCertificate: te.PrecertEntry.TBSCertificate,
+				},
+			},
+		}, nil
+	case ct.XJSONLogEntryType:
+		return &LogEntry{
+			Type:       LogEntryType_X_JSON_ENTRY.Enum(),
+			XJsonEntry: &XJSONEntry{Json: proto.String(string(te.JSONData))},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown entry type %v", te.EntryType)
+}
+
+// LogEntryFromProto returns the ct.LogEntry equivalent of |pb|, with its
+// index, timestamp and extensions unset, and its certificate unparsed; call
+// ParseCertificate on the result to parse it.
+func LogEntryFromProto(pb *LogEntry) (*ct.LogEntry, error) {
+	if pb == nil {
+		return nil, errors.New("missing log entry")
+	}
+	entry := &ct.LogEntry{
+		Leaf: ct.MerkleTreeLeaf{
+			Version:  ct.V1,
+			LeafType: ct.TimestampedEntryLeafType,
+		},
+	}
+	te := &entry.Leaf.TimestampedEntry
+	switch pb.GetType() {
+	case LogEntryType_X509_ENTRY:
+		x := pb.X509Entry
+		if x == nil || len(x.LeafCertificate) == 0 {
+			return nil, errors.New("X.509 entry has no certificate")
+		}
+		te.EntryType = ct.X509LogEntryType
+		te.X509Entry = x.LeafCertificate
+		entry.Chain = chainFromProto(x.CertificateChain)
+	case LogEntryType_PRECERT_ENTRY:
+		p := pb.PrecertEntry
+		if p == nil || len(p.PreCertificate) == 0 {
+			return nil, errors.New("precertificate entry has no precertificate")
+		}
+		if p.PreCert == nil || len(p.PreCert.TbsCertificate) == 0 {
+			return nil, errors.New("precertificate entry has no TBSCertificate")
+		}
+		if len(p.PreCert.IssuerKeyHash) != sha256.Size {
+			return nil, fmt.Errorf("invalid issuer key hash length %d", len(p.PreCert.IssuerKeyHash))
+		}
+		te.EntryType = ct.PrecertLogEntryType
+		copy(te.PrecertEntry.IssuerKeyHash[:], p.PreCert.IssuerKeyHash)
+		te.PrecertEntry.TBSCertificate = p.PreCert.TbsCertificate
+		entry.Chain = append([]ct.ASN1Cert{p.PreCertificate}, chainFromProto(p.PrecertificateChain)...)
+	case LogEntryType_X_JSON_ENTRY:
+		if pb.XJsonEntry == nil {
+			return nil, errors.New("JSON entry has no data")
+		}
+		te.EntryType = ct.XJSONLogEntryType
+		te.JSONData = []byte(pb.XJsonEntry.GetJson())
+		entry.JSONData = te.JSONData
+	default:
+		return nil, fmt.Errorf("unsupported entry type %v", pb.GetType())
+	}
+	return entry, nil
+}
+
+// LoggedEntryToProto returns the LoggedEntryPB, as stored by the C++ log, for
+// |entry| and the |sct| which the log issued for it.
+func LoggedEntryToProto(entry *ct.LogEntry, sct ct.SignedCertificateTimestamp) (*LoggedEntryPB, error) {
+	te := entry.Leaf.TimestampedEntry
+	if te.Timestamp != sct.Timestamp || !bytes.Equal(te.Extensions, sct.Extensions) {
+		return nil, errors.New("SCT doesn't match entry")
+	}
+	pbEntry, err := LogEntryToProto(entry)
+	if err != nil {
+		return nil, err
+	}
+	pbSCT, err := SCTToProto(sct)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := ct.LeafHashForLeaf(&entry.Leaf)
+	if err != nil {
+		return nil, err
+	}
+	return &LoggedEntryPB{
+		SequenceNumber: proto.Int64(entry.Index),
+		MerkleLeafHash: hash[:],
+		Contents: &LoggedEntryPB_Contents{
+			Sct:   pbSCT,
+			Entry: pbEntry,
+		},
+	}, nil
+}
+
+// LoggedEntryFromProto returns the ct.LogEntry and SCT held in |pb|. If |pb|
+// carries a Merkle leaf hash then it must match the entry.
+// As for LogEntryFromProto, the entry's certificate is left unparsed.
+func LoggedEntryFromProto(pb *LoggedEntryPB) (*ct.LogEntry, *ct.SignedCertificateTimestamp, error) {
+	if pb == nil || pb.Contents == nil {
+		return nil, nil, errors.New("missing logged entry contents")
+	}
+	entry, err := LogEntryFromProto(pb.Contents.Entry)
+	if err != nil {
+		return nil, nil, err
+	}
+	sct, err := SCTFromProto(pb.Contents.Sct)
+	if err != nil {
+		return nil, nil, err
+	}
+	entry.Index = pb.GetSequenceNumber()
+	entry.Leaf.TimestampedEntry.Timestamp = sct.Timestamp
+	entry.Leaf.TimestampedEntry.Extensions = sct.Extensions
+	if pb.MerkleLeafHash != nil {
+		hash, err := ct.LeafHashForLeaf(&entry.Leaf)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !bytes.Equal(hash[:], pb.MerkleLeafHash) {
+			return nil, nil, fmt.Errorf("Merkle leaf hash %x doesn't match entry, want %x", pb.MerkleLeafHash, hash)
+		}
+	}
+	return entry, sct, nil
+}
+
+func logIDToProto(id ct.SHA256Hash) *LogID {
+	return &LogID{KeyId: append([]byte(nil), id[:]...)}
+}
+
+func logIDFromProto(pb *LogID) (ct.SHA256Hash, error) {
+	var id ct.SHA256Hash
+	if len(pb.GetKeyId()) != len(id) {
+		return id, fmt.Errorf("invalid log ID length %d", len(pb.GetKeyId()))
+	}
+	copy(id[:], pb.KeyId)
+	return id, nil
+}
+
+func chainToProto(chain []ct.ASN1Cert) [][]byte {
+	var pb [][]byte
+	for _, c := range chain {
+		pb = append(pb, c)
+	}
+	return pb
+}
+
+func chainFromProto(pb [][]byte) []ct.ASN1Cert {
+	var chain []ct.ASN1Cert
+	for _, c := range pb {
+		chain = append(chain, c)
+	}
+	return chain
+}
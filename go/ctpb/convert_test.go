@@ -0,0 +1,255 @@
+package ctpb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/testonly"
+)
+
+func readTestSCT(t *testing.T, name string) *ct.SignedCertificateTimestamp {
+	sct, err := ct.DeserializeSCT(bytes.NewReader(testonly.ReadFile(t, name)))
+	if err != nil {
+		t.Fatalf("%s: DeserializeSCT()=%v", name, err)
+	}
+	return sct
+}
+
+// wireRoundTrip marshals |m| and unmarshals it into |out|, as happens when the
+// protobuf is passed to or from the C++ log.
+func wireRoundTrip(t *testing.T, m, out proto.Message) {
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("proto.Marshal()=%v", err)
+	}
+	if err := proto.Unmarshal(b, out); err != nil {
+		t.Fatalf("proto.Unmarshal()=%v", err)
+	}
+}
+
+func TestSCTRoundTrip(t *testing.T) {
+	sct := readTestSCT(t, "test-cert.proof")
+	sct.Extensions = ct.CTExtensions("ext")
+	pb, err := SCTToProto(*sct)
+	if err != nil {
+		t.Fatalf("SCTToProto()=%v", err)
+	}
+	if pb.GetTimestamp() != sct.Timestamp || !bytes.Equal(pb.GetId().GetKeyId(), sct.LogID[:]) {
+		t.Errorf("SCTToProto() has timestamp %d and log ID %x, want %d and %x", pb.GetTimestamp(), pb.GetId().GetKeyId(), sct.Timestamp, sct.LogID)
+	}
+	if pb.GetSignature().GetHashAlgorithm() != DigitallySigned_SHA256 || pb.GetSignature().GetSigAlgorithm() != DigitallySigned_ECDSA {
+		t.Errorf("SCTToProto() has signature algorithms %v/%v, want SHA256/ECDSA", pb.GetSignature().GetHashAlgorithm(), pb.GetSignature().GetSigAlgorithm())
+	}
+	var got SignedCertificateTimestamp
+	wireRoundTrip(t, pb, &got)
+	back, err := SCTFromProto(&got)
+	if err != nil {
+		t.Fatalf("SCTFromProto()=%v", err)
+	}
+	if !reflect.DeepEqual(back, sct) {
+		t.Errorf("SCTFromProto(SCTToProto(%v))=%v", sct, back)
+	}
+}
+
+func TestSCTFromProtoErrors(t *testing.T) {
+	valid := func() *SignedCertificateTimestamp {
+		pb, err := SCTToProto(*readTestSCT(t, "test-cert.proof"))
+		if err != nil {
+			t.Fatalf("SCTToProto()=%v", err)
+		}
+		return pb
+	}
+	for _, test := range []struct {
+		desc   string
+		modify func(pb *SignedCertificateTimestamp)
+	}{
+		{"nil", nil},
+		{"no version", func(pb *SignedCertificateTimestamp) { pb.Version = nil }},
+		{"V2", func(pb *SignedCertificateTimestamp) { pb.Version = Version_V2.Enum() }},
+		{"V2 extensions", func(pb *SignedCertificateTimestamp) { pb.SctExtension = []*SctExtension{{}} }},
+		{"no log ID", func(pb *SignedCertificateTimestamp) { pb.Id = nil }},
+		{"short log ID", func(pb *SignedCertificateTimestamp) { pb.Id.KeyId = pb.Id.KeyId[1:] }},
+		{"no signature", func(pb *SignedCertificateTimestamp) { pb.Signature = nil }},
+		{"unknown hash algorithm", func(pb *SignedCertificateTimestamp) {
+			pb.Signature.HashAlgorithm = DigitallySigned_HashAlgorithm(7).Enum()
+		}},
+		{"unknown signature algorithm", func(pb *SignedCertificateTimestamp) {
+			pb.Signature.SigAlgorithm = DigitallySigned_SignatureAlgorithm(4).Enum()
+		}},
+	} {
+		var pb *SignedCertificateTimestamp
+		if test.modify != nil {
+			pb = valid()
+			test.modify(pb)
+		}
+		if _, err := SCTFromProto(pb); err == nil {
+			t.Errorf("%s: SCTFromProto() succeeded, want error", test.desc)
+		}
+	}
+}
+
+func TestDigitallySignedToProtoErrors(t *testing.T) {
+	for _, ds := range []ct.DigitallySigned{
+		{HashAlgorithm: 7, SignatureAlgorithm: ct.ECDSA},
+		{HashAlgorithm: ct.SHA256, SignatureAlgorithm: 4},
+	} {
+		if _, err := DigitallySignedToProto(ds); err == nil {
+			t.Errorf("DigitallySignedToProto(%v) succeeded, want error", ds)
+		}
+	}
+}
+
+func testSTH() ct.SignedTreeHead {
+	sth := ct.SignedTreeHead{
+		Version:   ct.V1,
+		TreeSize:  1234,
+		Timestamp: 5678,
+		TreeHeadSignature: ct.DigitallySigned{
+			HashAlgorithm:      ct.SHA256,
+			SignatureAlgorithm: ct.RSA,
+			Signature:          []byte("signature"),
+		},
+	}
+	copy(sth.SHA256RootHash[:], bytes.Repeat([]byte{1}, 32))
+	copy(sth.LogID[:], bytes.Repeat([]byte{2}, 32))
+	return sth
+}
+
+func TestSTHRoundTrip(t *testing.T) {
+	sth := testSTH()
+	pb, err := STHToProto(sth)
+	if err != nil {
+		t.Fatalf("STHToProto()=%v", err)
+	}
+	var got SignedTreeHead
+	wireRoundTrip(t, pb, &got)
+	back, err := STHFromProto(&got)
+	if err != nil {
+		t.Fatalf("STHFromProto()=%v", err)
+	}
+	if !reflect.DeepEqual(*back, sth) {
+		t.Errorf("STHFromProto(STHToProto(%+v))=%+v", sth, *back)
+	}
+
+	sth.TreeSize = 1 << 63
+	if _, err := STHToProto(sth); err == nil {
+		t.Error("STHToProto() of huge tree succeeded, want error")
+	}
+}
+
+func TestSTHFromProtoErrors(t *testing.T) {
+	for _, test := range []struct {
+		desc   string
+		modify func(pb *SignedTreeHead)
+	}{
+		{"no version", func(pb *SignedTreeHead) { pb.Version = nil }},
+		{"V2 extensions", func(pb *SignedTreeHead) { pb.SthExtension = []*SthExtension{{}} }},
+		{"negative tree size", func(pb *SignedTreeHead) { pb.TreeSize = proto.Int64(-1) }},
+		{"short log ID", func(pb *SignedTreeHead) { pb.Id.KeyId = nil }},
+		{"short root hash", func(pb *SignedTreeHead) { pb.Sha256RootHash = pb.Sha256RootHash[1:] }},
+		{"no signature", func(pb *SignedTreeHead) { pb.Signature = nil }},
+	} {
+		pb, err := STHToProto(testSTH())
+		if err != nil {
+			t.Fatalf("STHToProto()=%v", err)
+		}
+		test.modify(pb)
+		if _, err := STHFromProto(pb); err == nil {
+			t.Errorf("%s: STHFromProto() succeeded, want error", test.desc)
+		}
+	}
+}
+
+func testLogEntries(t *testing.T) map[string]*ct.LogEntry {
+	ca := testonly.ReadPEM(t, "ca-cert.pem")
+	preChain := []ct.ASN1Cert{testonly.ReadPEM(t, "test-embedded-with-preca-pre-cert.pem"), testonly.ReadPEM(t, "ca-pre-cert.pem"), ca}
+	precertLeaf, _, err := ct.CreatePrecertMerkleTreeLeaf(preChain, 0)
+	if err != nil {
+		t.Fatalf("CreatePrecertMerkleTreeLeaf()=%v", err)
+	}
+	jsonLeaf := ct.CreateJSONMerkleTreeLeaf("data", 0)
+	return map[string]*ct.LogEntry{
+		"X509": {
+			Leaf:  *ct.CreateX509MerkleTreeLeaf(testonly.ReadPEM(t, "test-cert.pem"), 0),
+			Chain: []ct.ASN1Cert{ca},
+		},
+		"Precert": {
+			Leaf:  *precertLeaf,
+			Chain: preChain,
+		},
+		"XJSON": {
+			Leaf:     *jsonLeaf,
+			JSONData: jsonLeaf.TimestampedEntry.JSONData,
+		},
+	}
+}
+
+func TestLoggedEntryRoundTrip(t *testing.T) {
+	sct := readTestSCT(t, "test-cert.proof")
+	sct.Extensions = ct.CTExtensions("ext")
+	for name, entry := range testLogEntries(t) {
+		entry.Index = 42
+		entry.Leaf.TimestampedEntry.Timestamp = sct.Timestamp
+		entry.Leaf.TimestampedEntry.Extensions = sct.Extensions
+		pb, err := LoggedEntryToProto(entry, *sct)
+		if err != nil {
+			t.Errorf("%s: LoggedEntryToProto()=%v", name, err)
+			continue
+		}
+		var got LoggedEntryPB
+		wireRoundTrip(t, pb, &got)
+		backEntry, backSCT, err := LoggedEntryFromProto(&got)
+		if err != nil {
+			t.Errorf("%s: LoggedEntryFromProto()=%v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(backEntry, entry) {
+			t.Errorf("%s: LoggedEntryFromProto() entry=%+v, want %+v", name, backEntry, entry)
+		}
+		if !reflect.DeepEqual(backSCT, sct) {
+			t.Errorf("%s: LoggedEntryFromProto() SCT=%v, want %v", name, backSCT, sct)
+		}
+
+		got.MerkleLeafHash[0] ^= 1
+		if _, _, err := LoggedEntryFromProto(&got); err == nil {
+			t.Errorf("%s: LoggedEntryFromProto() with wrong leaf hash succeeded, want error", name)
+		}
+		got.MerkleLeafHash = nil
+		if _, _, err := LoggedEntryFromProto(&got); err != nil {
+			t.Errorf("%s: LoggedEntryFromProto() without leaf hash=%v", name, err)
+		}
+	}
+
+	entry := testLogEntries(t)["X509"]
+	if _, err := LoggedEntryToProto(entry, *sct); err == nil {
+		t.Error("LoggedEntryToProto() with mismatched SCT succeeded, want error")
+	}
+}
+
+func TestLogEntryFromProtoErrors(t *testing.T) {
+	for _, test := range []struct {
+		desc string
+		pb   *LogEntry
+	}{
+		{"nil", nil},
+		{"unknown type", &LogEntry{}},
+		{"V2 precert", &LogEntry{Type: LogEntryType_PRECERT_ENTRY_V2.Enum()}},
+		{"missing X.509 entry", &LogEntry{Type: LogEntryType_X509_ENTRY.Enum()}},
+		{"missing precert entry", &LogEntry{Type: LogEntryType_PRECERT_ENTRY.Enum()}},
+		{"missing PreCert", &LogEntry{Type: LogEntryType_PRECERT_ENTRY.Enum(), PrecertEntry: &PrecertChainEntry{
+			PreCertificate: []byte("precert"),
+		}}},
+		{"short issuer key hash", &LogEntry{Type: LogEntryType_PRECERT_ENTRY.Enum(), PrecertEntry: &PrecertChainEntry{
+			PreCertificate: []byte("precert"),
+			PreCert:        &PreCert{IssuerKeyHash: []byte{1}, TbsCertificate: []byte("tbs")},
+		}}},
+		{"missing JSON entry", &LogEntry{Type: LogEntryType_X_JSON_ENTRY.Enum()}},
+	} {
+		if _, err := LogEntryFromProto(test.pb); err == nil {
+			t.Errorf("%s: LogEntryFromProto() succeeded, want error", test.desc)
+		}
+	}
+}
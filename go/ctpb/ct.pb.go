@@ -0,0 +1,1461 @@
+// Code generated by protoc-gen-go.
+// source: ct.proto
+// DO NOT EDIT!
+
+/*
+Package ctpb is a generated protocol buffer package.
+
+It is generated from these files:
+
+	ct.proto
+
+It has these top-level messages:
+
+	DigitallySigned
+	X509ChainEntry
+	PreCert
+	CertInfo
+	PrecertChainEntry
+	XJSONEntry
+	LogEntry
+	LogID
+	SctExtension
+	SignedCertificateTimestamp
+	SignedCertificateTimestampList
+	SignedEntry
+	TimestampedEntry
+	MerkleTreeLeaf
+	MerkleAuditProof
+	ShortMerkleAuditProof
+	LoggedEntryPB
+	SthExtension
+	SignedTreeHead
+	SSLClientCTData
+	ClusterNodeState
+	ClusterControl
+	ClusterConfig
+	SequenceMapping
+*/
+package ctpb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type LogEntryType int32
+
+const (
+	LogEntryType_X509_ENTRY       LogEntryType = 0
+	LogEntryType_PRECERT_ENTRY    LogEntryType = 1
+	LogEntryType_PRECERT_ENTRY_V2 LogEntryType = 2
+	// Not part of the I-D, and outside the valid range.
+	LogEntryType_X_JSON_ENTRY       LogEntryType = 32768
+	LogEntryType_UNKNOWN_ENTRY_TYPE LogEntryType = 65536
+)
+
+var LogEntryType_name = map[int32]string{
+	0:     "X509_ENTRY",
+	1:     "PRECERT_ENTRY",
+	2:     "PRECERT_ENTRY_V2",
+	32768: "X_JSON_ENTRY",
+	65536: "UNKNOWN_ENTRY_TYPE",
+}
+var LogEntryType_value = map[string]int32{
+	"X509_ENTRY":         0,
+	"PRECERT_ENTRY":      1,
+	"PRECERT_ENTRY_V2":   2,
+	"X_JSON_ENTRY":       32768,
+	"UNKNOWN_ENTRY_TYPE": 65536,
+}
+
+func (x LogEntryType) Enum() *LogEntryType {
+	p := new(LogEntryType)
+	*p = x
+	return p
+}
+func (x LogEntryType) String() string {
+	return proto.EnumName(LogEntryType_name, int32(x))
+}
+func (x *LogEntryType) UnmarshalJSON(data []byte) error {
+	value, err := proto.UnmarshalJSONEnum(LogEntryType_value, data, "LogEntryType")
+	if err != nil {
+		return err
+	}
+	*x = LogEntryType(value)
+	return nil
+}
+func (LogEntryType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type SignatureType int32
+
+const (
+	SignatureType_CERTIFICATE_TIMESTAMP SignatureType = 0
+	// TODO(ekasper): called tree_hash in I-D.
+	SignatureType_TREE_HEAD SignatureType = 1
+)
+
+var SignatureType_name = map[int32]string{
+	0: "CERTIFICATE_TIMESTAMP",
+	1: "TREE_HEAD",
+}
+var SignatureType_value = map[string]int32{
+	"CERTIFICATE_TIMESTAMP": 0,
+	"TREE_HEAD":             1,
+}
+
+func (x SignatureType) Enum() *SignatureType {
+	p := new(SignatureType)
+	*p = x
+	return p
+}
+func (x SignatureType) String() string {
+	return proto.EnumName(SignatureType_name, int32(x))
+}
+func (x *SignatureType) UnmarshalJSON(data []byte) error {
+	value, err := proto.UnmarshalJSONEnum(SignatureType_value, data, "SignatureType")
+	if err != nil {
+		return err
+	}
+	*x = SignatureType(value)
+	return nil
+}
+func (SignatureType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+type Version int32
+
+const (
+	Version_V1 Version = 0
+	Version_V2 Version = 1
+	// Not part of the I-D, and outside the valid range.
+	Version_UNKNOWN_VERSION Version = 256
+)
+
+var Version_name = map[int32]string{
+	0:   "V1",
+	1:   "V2",
+	256: "UNKNOWN_VERSION",
+}
+var Version_value = map[string]int32{
+	"V1":              0,
+	"V2":              1,
+	"UNKNOWN_VERSION": 256,
+}
+
+func (x Version) Enum() *Version {
+	p := new(Version)
+	*p = x
+	return p
+}
+func (x Version) String() string {
+	return proto.EnumName(Version_name, int32(x))
+}
+func (x *Version) UnmarshalJSON(data []byte) error {
+	value, err := proto.UnmarshalJSONEnum(Version_value, data, "Version")
+	if err != nil {
+		return err
+	}
+	*x = Version(value)
+	return nil
+}
+func (Version) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+type MerkleLeafType int32
+
+const (
+	MerkleLeafType_TIMESTAMPED_ENTRY MerkleLeafType = 0
+	MerkleLeafType_UNKNOWN_LEAF_TYPE MerkleLeafType = 256
+)
+
+var MerkleLeafType_name = map[int32]string{
+	0:   "TIMESTAMPED_ENTRY",
+	256: "UNKNOWN_LEAF_TYPE",
+}
+var MerkleLeafType_value = map[string]int32{
+	"TIMESTAMPED_ENTRY": 0,
+	"UNKNOWN_LEAF_TYPE": 256,
+}
+
+func (x MerkleLeafType) Enum() *MerkleLeafType {
+	p := new(MerkleLeafType)
+	*p = x
+	return p
+}
+func (x MerkleLeafType) String() string {
+	return proto.EnumName(MerkleLeafType_name, int32(x))
+}
+func (x *MerkleLeafType) UnmarshalJSON(data []byte) error {
+	value, err := proto.UnmarshalJSONEnum(MerkleLeafType_value, data, "MerkleLeafType")
+	if err != nil {
+		return err
+	}
+	*x = MerkleLeafType(value)
+	return nil
+}
+func (MerkleLeafType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+type DigitallySigned_HashAlgorithm int32
+
+const (
+	DigitallySigned_NONE   DigitallySigned_HashAlgorithm = 0
+	DigitallySigned_MD5    DigitallySigned_HashAlgorithm = 1
+	DigitallySigned_SHA1   DigitallySigned_HashAlgorithm = 2
+	DigitallySigned_SHA224 DigitallySigned_HashAlgorithm = 3
+	DigitallySigned_SHA256 DigitallySigned_HashAlgorithm = 4
+	DigitallySigned_SHA384 DigitallySigned_HashAlgorithm = 5
+	DigitallySigned_SHA512 DigitallySigned_HashAlgorithm = 6
+)
+
+var DigitallySigned_HashAlgorithm_name = map[int32]string{
+	0: "NONE",
+	1: "MD5",
+	2: "SHA1",
+	3: "SHA224",
+	4: "SHA256",
+	5: "SHA384",
+	6: "SHA512",
+}
+var DigitallySigned_HashAlgorithm_value = map[string]int32{
+	"NONE":   0,
+	"MD5":    1,
+	"SHA1":   2,
+	"SHA224": 3,
+	"SHA256": 4,
+	"SHA384": 5,
+	"SHA512": 6,
+}
+
+func (x DigitallySigned_HashAlgorithm) Enum() *DigitallySigned_HashAlgorithm {
+	p := new(DigitallySigned_HashAlgorithm)
+	*p = x
+	return p
+}
+func (x DigitallySigned_HashAlgorithm) String() string {
+	return proto.EnumName(DigitallySigned_HashAlgorithm_name, int32(x))
+}
+func (x *DigitallySigned_HashAlgorithm) UnmarshalJSON(data []byte) error {
+	value, err := proto.UnmarshalJSONEnum(DigitallySigned_HashAlgorithm_value, data, "DigitallySigned_HashAlgorithm")
+	if err != nil {
+		return err
+	}
+	*x = DigitallySigned_HashAlgorithm(value)
+	return nil
+}
+func (DigitallySigned_HashAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{0, 0}
+}
+
+type DigitallySigned_SignatureAlgorithm int32
+
+const (
+	DigitallySigned_ANONYMOUS DigitallySigned_SignatureAlgorithm = 0
+	DigitallySigned_RSA       DigitallySigned_SignatureAlgorithm = 1
+	DigitallySigned_DSA       DigitallySigned_SignatureAlgorithm = 2
+	DigitallySigned_ECDSA     DigitallySigned_SignatureAlgorithm = 3
+)
+
+var DigitallySigned_SignatureAlgorithm_name = map[int32]string{
+	0: "ANONYMOUS",
+	1: "RSA",
+	2: "DSA",
+	3: "ECDSA",
+}
+var DigitallySigned_SignatureAlgorithm_value = map[string]int32{
+	"ANONYMOUS": 0,
+	"RSA":       1,
+	"DSA":       2,
+	"ECDSA":     3,
+}
+
+func (x DigitallySigned_SignatureAlgorithm) Enum() *DigitallySigned_SignatureAlgorithm {
+	p := new(DigitallySigned_SignatureAlgorithm)
+	*p = x
+	return p
+}
+func (x DigitallySigned_SignatureAlgorithm) String() string {
+	return proto.EnumName(DigitallySigned_SignatureAlgorithm_name, int32(x))
+}
+func (x *DigitallySigned_SignatureAlgorithm) UnmarshalJSON(data []byte) error {
+	value, err := proto.UnmarshalJSONEnum(DigitallySigned_SignatureAlgorithm_value, data, "DigitallySigned_SignatureAlgorithm")
+	if err != nil {
+		return err
+	}
+	*x = DigitallySigned_SignatureAlgorithm(value)
+	return nil
+}
+func (DigitallySigned_SignatureAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{0, 1}
+}
+
+// RFC 5246
+type DigitallySigned struct {
+	// 1 byte
+	HashAlgorithm *DigitallySigned_HashAlgorithm `protobuf:"varint,1,opt,name=hash_algorithm,json=hashAlgorithm,enum=ct.DigitallySigned_HashAlgorithm,def=0" json:"hash_algorithm,omitempty"`
+	// 1 byte
+	SigAlgorithm *DigitallySigned_SignatureAlgorithm `protobuf:"varint,2,opt,name=sig_algorithm,json=sigAlgorithm,enum=ct.DigitallySigned_SignatureAlgorithm,def=0" json:"sig_algorithm,omitempty"`
+	// 0..2^16-1 bytes
+	Signature        []byte `protobuf:"bytes,3,opt,name=signature" json:"signature,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *DigitallySigned) Reset()                    { *m = DigitallySigned{} }
+func (m *DigitallySigned) String() string            { return proto.CompactTextString(m) }
+func (*DigitallySigned) ProtoMessage()               {}
+func (*DigitallySigned) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+const Default_DigitallySigned_HashAlgorithm DigitallySigned_HashAlgorithm = DigitallySigned_NONE
+const Default_DigitallySigned_SigAlgorithm DigitallySigned_SignatureAlgorithm = DigitallySigned_ANONYMOUS
+
+func (m *DigitallySigned) GetHashAlgorithm() DigitallySigned_HashAlgorithm {
+	if m != nil && m.HashAlgorithm != nil {
+		return *m.HashAlgorithm
+	}
+	return Default_DigitallySigned_HashAlgorithm
+}
+
+func (m *DigitallySigned) GetSigAlgorithm() DigitallySigned_SignatureAlgorithm {
+	if m != nil && m.SigAlgorithm != nil {
+		return *m.SigAlgorithm
+	}
+	return Default_DigitallySigned_SigAlgorithm
+}
+
+func (m *DigitallySigned) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type X509ChainEntry struct {
+	// For V1 this entry just includes the certificate in the leaf_certificate
+	// field
+	// <1..2^24-1>
+	LeafCertificate []byte `protobuf:"bytes,1,opt,name=leaf_certificate,json=leafCertificate" json:"leaf_certificate,omitempty"`
+	// For V2 it includes the cert and key hash using CertInfo. The
+	// leaf_certificate field is not used
+	CertInfo *CertInfo `protobuf:"bytes,3,opt,name=cert_info,json=certInfo" json:"cert_info,omitempty"`
+	// <0..2^24-1>
+	// A chain from the leaf to a trusted root
+	// (excluding leaf and possibly root).
+	CertificateChain [][]byte `protobuf:"bytes,2,rep,name=certificate_chain,json=certificateChain" json:"certificate_chain,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *X509ChainEntry) Reset()                    { *m = X509ChainEntry{} }
+func (m *X509ChainEntry) String() string            { return proto.CompactTextString(m) }
+func (*X509ChainEntry) ProtoMessage()               {}
+func (*X509ChainEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *X509ChainEntry) GetLeafCertificate() []byte {
+	if m != nil {
+		return m.LeafCertificate
+	}
+	return nil
+}
+
+func (m *X509ChainEntry) GetCertInfo() *CertInfo {
+	if m != nil {
+		return m.CertInfo
+	}
+	return nil
+}
+
+func (m *X509ChainEntry) GetCertificateChain() [][]byte {
+	if m != nil {
+		return m.CertificateChain
+	}
+	return nil
+}
+
+// opaque TBSCertificate<1..2^16-1>;
+//
+//	struct {
+//	  opaque issuer_key_hash[32];
+//	  TBSCertificate tbs_certificate;
+//	} PreCert;
+//
+// Retained for V1 API compatibility. May be removed in a future release.
+type PreCert struct {
+	IssuerKeyHash    []byte `protobuf:"bytes,1,opt,name=issuer_key_hash,json=issuerKeyHash" json:"issuer_key_hash,omitempty"`
+	TbsCertificate   []byte `protobuf:"bytes,2,opt,name=tbs_certificate,json=tbsCertificate" json:"tbs_certificate,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *PreCert) Reset()                    { *m = PreCert{} }
+func (m *PreCert) String() string            { return proto.CompactTextString(m) }
+func (*PreCert) ProtoMessage()               {}
+func (*PreCert) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *PreCert) GetIssuerKeyHash() []byte {
+	if m != nil {
+		return m.IssuerKeyHash
+	}
+	return nil
+}
+
+func (m *PreCert) GetTbsCertificate() []byte {
+	if m != nil {
+		return m.TbsCertificate
+	}
+	return nil
+}
+
+// In V2 this is used for both certificates and precertificates in SCTs. It
+// replaces PreCert and has the same structure. The older message remains for
+// compatibility with existing code that depends on this proto.
+type CertInfo struct {
+	IssuerKeyHash    []byte `protobuf:"bytes,1,opt,name=issuer_key_hash,json=issuerKeyHash" json:"issuer_key_hash,omitempty"`
+	TbsCertificate   []byte `protobuf:"bytes,2,opt,name=tbs_certificate,json=tbsCertificate" json:"tbs_certificate,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *CertInfo) Reset()                    { *m = CertInfo{} }
+func (m *CertInfo) String() string            { return proto.CompactTextString(m) }
+func (*CertInfo) ProtoMessage()               {}
+func (*CertInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *CertInfo) GetIssuerKeyHash() []byte {
+	if m != nil {
+		return m.IssuerKeyHash
+	}
+	return nil
+}
+
+func (m *CertInfo) GetTbsCertificate() []byte {
+	if m != nil {
+		return m.TbsCertificate
+	}
+	return nil
+}
+
+type PrecertChainEntry struct {
+	// <1..2^24-1>
+	PreCertificate []byte `protobuf:"bytes,1,opt,name=pre_certificate,json=preCertificate" json:"pre_certificate,omitempty"`
+	// <0..2^24-1>
+	// The chain certifying the precertificate, as submitted by the CA.
+	PrecertificateChain [][]byte `protobuf:"bytes,2,rep,name=precertificate_chain,json=precertificateChain" json:"precertificate_chain,omitempty"`
+	// PreCert input to the SCT. Can be computed from the above.
+	// Store it alongside the entry data so that the signers don't have to
+	// parse certificates to recompute it.
+	PreCert *PreCert `protobuf:"bytes,3,opt,name=pre_cert,json=preCert" json:"pre_cert,omitempty"`
+	// As above for V2 messages. Only one of these fields will be set in a
+	// valid message
+	CertInfo         *CertInfo `protobuf:"bytes,4,opt,name=cert_info,json=certInfo" json:"cert_info,omitempty"`
+	XXX_unrecognized []byte    `json:"-"`
+}
+
+func (m *PrecertChainEntry) Reset()                    { *m = PrecertChainEntry{} }
+func (m *PrecertChainEntry) String() string            { return proto.CompactTextString(m) }
+func (*PrecertChainEntry) ProtoMessage()               {}
+func (*PrecertChainEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *PrecertChainEntry) GetPreCertificate() []byte {
+	if m != nil {
+		return m.PreCertificate
+	}
+	return nil
+}
+
+func (m *PrecertChainEntry) GetPrecertificateChain() [][]byte {
+	if m != nil {
+		return m.PrecertificateChain
+	}
+	return nil
+}
+
+func (m *PrecertChainEntry) GetPreCert() *PreCert {
+	if m != nil {
+		return m.PreCert
+	}
+	return nil
+}
+
+func (m *PrecertChainEntry) GetCertInfo() *CertInfo {
+	if m != nil {
+		return m.CertInfo
+	}
+	return nil
+}
+
+type XJSONEntry struct {
+	Json             *string `protobuf:"bytes,1,opt,name=json" json:"json,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *XJSONEntry) Reset()                    { *m = XJSONEntry{} }
+func (m *XJSONEntry) String() string            { return proto.CompactTextString(m) }
+func (*XJSONEntry) ProtoMessage()               {}
+func (*XJSONEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *XJSONEntry) GetJson() string {
+	if m != nil && m.Json != nil {
+		return *m.Json
+	}
+	return ""
+}
+
+// TODO(alcutter): Consider using extensions here instead.
+type LogEntry struct {
+	Type             *LogEntryType      `protobuf:"varint,1,opt,name=type,enum=ct.LogEntryType,def=65536" json:"type,omitempty"`
+	X509Entry        *X509ChainEntry    `protobuf:"bytes,2,opt,name=x509_entry,json=x509Entry" json:"x509_entry,omitempty"`
+	PrecertEntry     *PrecertChainEntry `protobuf:"bytes,3,opt,name=precert_entry,json=precertEntry" json:"precert_entry,omitempty"`
+	XJsonEntry       *XJSONEntry        `protobuf:"bytes,4,opt,name=x_json_entry,json=xJsonEntry" json:"x_json_entry,omitempty"`
+	XXX_unrecognized []byte             `json:"-"`
+}
+
+func (m *LogEntry) Reset()                    { *m = LogEntry{} }
+func (m *LogEntry) String() string            { return proto.CompactTextString(m) }
+func (*LogEntry) ProtoMessage()               {}
+func (*LogEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+const Default_LogEntry_Type LogEntryType = LogEntryType_UNKNOWN_ENTRY_TYPE
+
+func (m *LogEntry) GetType() LogEntryType {
+	if m != nil && m.Type != nil {
+		return *m.Type
+	}
+	return Default_LogEntry_Type
+}
+
+func (m *LogEntry) GetX509Entry() *X509ChainEntry {
+	if m != nil {
+		return m.X509Entry
+	}
+	return nil
+}
+
+func (m *LogEntry) GetPrecertEntry() *PrecertChainEntry {
+	if m != nil {
+		return m.PrecertEntry
+	}
+	return nil
+}
+
+func (m *LogEntry) GetXJsonEntry() *XJSONEntry {
+	if m != nil {
+		return m.XJsonEntry
+	}
+	return nil
+}
+
+type LogID struct {
+	// 32 bytes
+	KeyId            []byte `protobuf:"bytes,1,opt,name=key_id,json=keyId" json:"key_id,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *LogID) Reset()                    { *m = LogID{} }
+func (m *LogID) String() string            { return proto.CompactTextString(m) }
+func (*LogID) ProtoMessage()               {}
+func (*LogID) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *LogID) GetKeyId() []byte {
+	if m != nil {
+		return m.KeyId
+	}
+	return nil
+}
+
+type SctExtension struct {
+	// Valid range is 0-65534
+	SctExtensionType *uint32 `protobuf:"varint,1,opt,name=sct_extension_type,json=sctExtensionType" json:"sct_extension_type,omitempty"`
+	// Data is opaque and type specific. <0..2^16-1> bytes
+	SctExtensionData []byte `protobuf:"bytes,2,opt,name=sct_extension_data,json=sctExtensionData" json:"sct_extension_data,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *SctExtension) Reset()                    { *m = SctExtension{} }
+func (m *SctExtension) String() string            { return proto.CompactTextString(m) }
+func (*SctExtension) ProtoMessage()               {}
+func (*SctExtension) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *SctExtension) GetSctExtensionType() uint32 {
+	if m != nil && m.SctExtensionType != nil {
+		return *m.SctExtensionType
+	}
+	return 0
+}
+
+func (m *SctExtension) GetSctExtensionData() []byte {
+	if m != nil {
+		return m.SctExtensionData
+	}
+	return nil
+}
+
+// TODO(ekasper): implement support for id.
+type SignedCertificateTimestamp struct {
+	Version *Version `protobuf:"varint,1,opt,name=version,enum=ct.Version,def=256" json:"version,omitempty"`
+	Id      *LogID   `protobuf:"bytes,2,opt,name=id" json:"id,omitempty"`
+	// UTC time in milliseconds, since January 1, 1970, 00:00.
+	Timestamp *uint64          `protobuf:"varint,3,opt,name=timestamp" json:"timestamp,omitempty"`
+	Signature *DigitallySigned `protobuf:"bytes,4,opt,name=signature" json:"signature,omitempty"`
+	// V1 extensions
+	Extensions []byte `protobuf:"bytes,5,opt,name=extensions" json:"extensions,omitempty"`
+	// V2 extensions <0..2^16-1>. Must be ordered by type (lowest first)
+	SctExtension     []*SctExtension `protobuf:"bytes,6,rep,name=sct_extension,json=sctExtension" json:"sct_extension,omitempty"`
+	XXX_unrecognized []byte          `json:"-"`
+}
+
+func (m *SignedCertificateTimestamp) Reset()                    { *m = SignedCertificateTimestamp{} }
+func (m *SignedCertificateTimestamp) String() string            { return proto.CompactTextString(m) }
+func (*SignedCertificateTimestamp) ProtoMessage()               {}
+func (*SignedCertificateTimestamp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+const Default_SignedCertificateTimestamp_Version Version = Version_UNKNOWN_VERSION
+
+func (m *SignedCertificateTimestamp) GetVersion() Version {
+	if m != nil && m.Version != nil {
+		return *m.Version
+	}
+	return Default_SignedCertificateTimestamp_Version
+}
+
+func (m *SignedCertificateTimestamp) GetId() *LogID {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *SignedCertificateTimestamp) GetTimestamp() uint64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
+}
+
+func (m *SignedCertificateTimestamp) GetSignature() *DigitallySigned {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *SignedCertificateTimestamp) GetExtensions() []byte {
+	if m != nil {
+		return m.Extensions
+	}
+	return nil
+}
+
+func (m *SignedCertificateTimestamp) GetSctExtension() []*SctExtension {
+	if m != nil {
+		return m.SctExtension
+	}
+	return nil
+}
+
+type SignedCertificateTimestampList struct {
+	// One or more SCTs, <1..2^16-1> bytes each
+	SctList          [][]byte `protobuf:"bytes,1,rep,name=sct_list,json=sctList" json:"sct_list,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *SignedCertificateTimestampList) Reset()         { *m = SignedCertificateTimestampList{} }
+func (m *SignedCertificateTimestampList) String() string { return proto.CompactTextString(m) }
+func (*SignedCertificateTimestampList) ProtoMessage()    {}
+func (*SignedCertificateTimestampList) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{10}
+}
+
+func (m *SignedCertificateTimestampList) GetSctList() [][]byte {
+	if m != nil {
+		return m.SctList
+	}
+	return nil
+}
+
+type SignedEntry struct {
+	// For V1 signed entries either the x509 or precert field will be set
+	X509    []byte   `protobuf:"bytes,1,opt,name=x509" json:"x509,omitempty"`
+	Precert *PreCert `protobuf:"bytes,2,opt,name=precert" json:"precert,omitempty"`
+	Json    []byte   `protobuf:"bytes,3,opt,name=json" json:"json,omitempty"`
+	// For V2 all entries use the CertInfo field and the above fields are
+	// not set
+	CertInfo         *CertInfo `protobuf:"bytes,4,opt,name=cert_info,json=certInfo" json:"cert_info,omitempty"`
+	XXX_unrecognized []byte    `json:"-"`
+}
+
+func (m *SignedEntry) Reset()                    { *m = SignedEntry{} }
+func (m *SignedEntry) String() string            { return proto.CompactTextString(m) }
+func (*SignedEntry) ProtoMessage()               {}
+func (*SignedEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *SignedEntry) GetX509() []byte {
+	if m != nil {
+		return m.X509
+	}
+	return nil
+}
+
+func (m *SignedEntry) GetPrecert() *PreCert {
+	if m != nil {
+		return m.Precert
+	}
+	return nil
+}
+
+func (m *SignedEntry) GetJson() []byte {
+	if m != nil {
+		return m.Json
+	}
+	return nil
+}
+
+func (m *SignedEntry) GetCertInfo() *CertInfo {
+	if m != nil {
+		return m.CertInfo
+	}
+	return nil
+}
+
+type TimestampedEntry struct {
+	Timestamp   *uint64       `protobuf:"varint,1,opt,name=timestamp" json:"timestamp,omitempty"`
+	EntryType   *LogEntryType `protobuf:"varint,2,opt,name=entry_type,json=entryType,enum=ct.LogEntryType" json:"entry_type,omitempty"`
+	SignedEntry *SignedEntry  `protobuf:"bytes,3,opt,name=signed_entry,json=signedEntry" json:"signed_entry,omitempty"`
+	// V1 extensions
+	Extensions []byte `protobuf:"bytes,4,opt,name=extensions" json:"extensions,omitempty"`
+	// V2 extensions <0..2^16-1>. Must be ordered by type (lowest first)
+	SctExtension     []*SctExtension `protobuf:"bytes,5,rep,name=sct_extension,json=sctExtension" json:"sct_extension,omitempty"`
+	XXX_unrecognized []byte          `json:"-"`
+}
+
+func (m *TimestampedEntry) Reset()                    { *m = TimestampedEntry{} }
+func (m *TimestampedEntry) String() string            { return proto.CompactTextString(m) }
+func (*TimestampedEntry) ProtoMessage()               {}
+func (*TimestampedEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *TimestampedEntry) GetTimestamp() uint64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
+}
+
+func (m *TimestampedEntry) GetEntryType() LogEntryType {
+	if m != nil && m.EntryType != nil {
+		return *m.EntryType
+	}
+	return LogEntryType_X509_ENTRY
+}
+
+func (m *TimestampedEntry) GetSignedEntry() *SignedEntry {
+	if m != nil {
+		return m.SignedEntry
+	}
+	return nil
+}
+
+func (m *TimestampedEntry) GetExtensions() []byte {
+	if m != nil {
+		return m.Extensions
+	}
+	return nil
+}
+
+func (m *TimestampedEntry) GetSctExtension() []*SctExtension {
+	if m != nil {
+		return m.SctExtension
+	}
+	return nil
+}
+
+// Stuff that's hashed into a Merkle leaf.
+type MerkleTreeLeaf struct {
+	// The version of the corresponding SCT.
+	Version          *Version          `protobuf:"varint,1,opt,name=version,enum=ct.Version,def=256" json:"version,omitempty"`
+	Type             *MerkleLeafType   `protobuf:"varint,2,opt,name=type,enum=ct.MerkleLeafType,def=256" json:"type,omitempty"`
+	TimestampedEntry *TimestampedEntry `protobuf:"bytes,3,opt,name=timestamped_entry,json=timestampedEntry" json:"timestamped_entry,omitempty"`
+	XXX_unrecognized []byte            `json:"-"`
+}
+
+func (m *MerkleTreeLeaf) Reset()                    { *m = MerkleTreeLeaf{} }
+func (m *MerkleTreeLeaf) String() string            { return proto.CompactTextString(m) }
+func (*MerkleTreeLeaf) ProtoMessage()               {}
+func (*MerkleTreeLeaf) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+const Default_MerkleTreeLeaf_Version Version = Version_UNKNOWN_VERSION
+const Default_MerkleTreeLeaf_Type MerkleLeafType = MerkleLeafType_UNKNOWN_LEAF_TYPE
+
+func (m *MerkleTreeLeaf) GetVersion() Version {
+	if m != nil && m.Version != nil {
+		return *m.Version
+	}
+	return Default_MerkleTreeLeaf_Version
+}
+
+func (m *MerkleTreeLeaf) GetType() MerkleLeafType {
+	if m != nil && m.Type != nil {
+		return *m.Type
+	}
+	return Default_MerkleTreeLeaf_Type
+}
+
+func (m *MerkleTreeLeaf) GetTimestampedEntry() *TimestampedEntry {
+	if m != nil {
+		return m.TimestampedEntry
+	}
+	return nil
+}
+
+// TODO(benl): No longer needed?
+//
+// Used by cpp/client/ct: it assembles the one from the I-D JSON
+// protocol.
+//
+// Used by cpp/server/blob-server: it uses one to call a variant of
+// LogLookup::AuditProof.
+type MerkleAuditProof struct {
+	Version           *Version         `protobuf:"varint,1,opt,name=version,enum=ct.Version,def=256" json:"version,omitempty"`
+	Id                *LogID           `protobuf:"bytes,2,opt,name=id" json:"id,omitempty"`
+	TreeSize          *int64           `protobuf:"varint,3,opt,name=tree_size,json=treeSize" json:"tree_size,omitempty"`
+	Timestamp         *uint64          `protobuf:"varint,4,opt,name=timestamp" json:"timestamp,omitempty"`
+	LeafIndex         *int64           `protobuf:"varint,5,opt,name=leaf_index,json=leafIndex" json:"leaf_index,omitempty"`
+	PathNode          [][]byte         `protobuf:"bytes,6,rep,name=path_node,json=pathNode" json:"path_node,omitempty"`
+	TreeHeadSignature *DigitallySigned `protobuf:"bytes,7,opt,name=tree_head_signature,json=treeHeadSignature" json:"tree_head_signature,omitempty"`
+	XXX_unrecognized  []byte           `json:"-"`
+}
+
+func (m *MerkleAuditProof) Reset()                    { *m = MerkleAuditProof{} }
+func (m *MerkleAuditProof) String() string            { return proto.CompactTextString(m) }
+func (*MerkleAuditProof) ProtoMessage()               {}
+func (*MerkleAuditProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+const Default_MerkleAuditProof_Version Version = Version_UNKNOWN_VERSION
+
+func (m *MerkleAuditProof) GetVersion() Version {
+	if m != nil && m.Version != nil {
+		return *m.Version
+	}
+	return Default_MerkleAuditProof_Version
+}
+
+func (m *MerkleAuditProof) GetId() *LogID {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *MerkleAuditProof) GetTreeSize() int64 {
+	if m != nil && m.TreeSize != nil {
+		return *m.TreeSize
+	}
+	return 0
+}
+
+func (m *MerkleAuditProof) GetTimestamp() uint64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
+}
+
+func (m *MerkleAuditProof) GetLeafIndex() int64 {
+	if m != nil && m.LeafIndex != nil {
+		return *m.LeafIndex
+	}
+	return 0
+}
+
+func (m *MerkleAuditProof) GetPathNode() [][]byte {
+	if m != nil {
+		return m.PathNode
+	}
+	return nil
+}
+
+func (m *MerkleAuditProof) GetTreeHeadSignature() *DigitallySigned {
+	if m != nil {
+		return m.TreeHeadSignature
+	}
+	return nil
+}
+
+type ShortMerkleAuditProof struct {
+	LeafIndex        *int64   `protobuf:"varint,1,req,name=leaf_index,json=leafIndex" json:"leaf_index,omitempty"`
+	PathNode         [][]byte `protobuf:"bytes,2,rep,name=path_node,json=pathNode" json:"path_node,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *ShortMerkleAuditProof) Reset()                    { *m = ShortMerkleAuditProof{} }
+func (m *ShortMerkleAuditProof) String() string            { return proto.CompactTextString(m) }
+func (*ShortMerkleAuditProof) ProtoMessage()               {}
+func (*ShortMerkleAuditProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *ShortMerkleAuditProof) GetLeafIndex() int64 {
+	if m != nil && m.LeafIndex != nil {
+		return *m.LeafIndex
+	}
+	return 0
+}
+
+func (m *ShortMerkleAuditProof) GetPathNode() [][]byte {
+	if m != nil {
+		return m.PathNode
+	}
+	return nil
+}
+
+// TODO(alcutter): Come up with a better name :/
+type LoggedEntryPB struct {
+	SequenceNumber   *int64                  `protobuf:"varint,1,opt,name=sequence_number,json=sequenceNumber" json:"sequence_number,omitempty"`
+	MerkleLeafHash   []byte                  `protobuf:"bytes,2,opt,name=merkle_leaf_hash,json=merkleLeafHash" json:"merkle_leaf_hash,omitempty"`
+	Contents         *LoggedEntryPB_Contents `protobuf:"bytes,3,req,name=contents" json:"contents,omitempty"`
+	XXX_unrecognized []byte                  `json:"-"`
+}
+
+func (m *LoggedEntryPB) Reset()                    { *m = LoggedEntryPB{} }
+func (m *LoggedEntryPB) String() string            { return proto.CompactTextString(m) }
+func (*LoggedEntryPB) ProtoMessage()               {}
+func (*LoggedEntryPB) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *LoggedEntryPB) GetSequenceNumber() int64 {
+	if m != nil && m.SequenceNumber != nil {
+		return *m.SequenceNumber
+	}
+	return 0
+}
+
+func (m *LoggedEntryPB) GetMerkleLeafHash() []byte {
+	if m != nil {
+		return m.MerkleLeafHash
+	}
+	return nil
+}
+
+func (m *LoggedEntryPB) GetContents() *LoggedEntryPB_Contents {
+	if m != nil {
+		return m.Contents
+	}
+	return nil
+}
+
+type LoggedEntryPB_Contents struct {
+	Sct              *SignedCertificateTimestamp `protobuf:"bytes,1,opt,name=sct" json:"sct,omitempty"`
+	Entry            *LogEntry                   `protobuf:"bytes,2,opt,name=entry" json:"entry,omitempty"`
+	XXX_unrecognized []byte                      `json:"-"`
+}
+
+func (m *LoggedEntryPB_Contents) Reset()                    { *m = LoggedEntryPB_Contents{} }
+func (m *LoggedEntryPB_Contents) String() string            { return proto.CompactTextString(m) }
+func (*LoggedEntryPB_Contents) ProtoMessage()               {}
+func (*LoggedEntryPB_Contents) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16, 0} }
+
+func (m *LoggedEntryPB_Contents) GetSct() *SignedCertificateTimestamp {
+	if m != nil {
+		return m.Sct
+	}
+	return nil
+}
+
+func (m *LoggedEntryPB_Contents) GetEntry() *LogEntry {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+type SthExtension struct {
+	// Valid range is 0-65534
+	SthExtensionType *uint32 `protobuf:"varint,1,opt,name=sth_extension_type,json=sthExtensionType" json:"sth_extension_type,omitempty"`
+	// Data is opaque and type specific <0..2^16-1> bytes
+	SthExtensionData []byte `protobuf:"bytes,2,opt,name=sth_extension_data,json=sthExtensionData" json:"sth_extension_data,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *SthExtension) Reset()                    { *m = SthExtension{} }
+func (m *SthExtension) String() string            { return proto.CompactTextString(m) }
+func (*SthExtension) ProtoMessage()               {}
+func (*SthExtension) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *SthExtension) GetSthExtensionType() uint32 {
+	if m != nil && m.SthExtensionType != nil {
+		return *m.SthExtensionType
+	}
+	return 0
+}
+
+func (m *SthExtension) GetSthExtensionData() []byte {
+	if m != nil {
+		return m.SthExtensionData
+	}
+	return nil
+}
+
+type SignedTreeHead struct {
+	// The version of the tree head signature.
+	// (Note that each leaf has its own version, so a V2 tree
+	// can contain V1 leaves, too.
+	Version        *Version         `protobuf:"varint,1,opt,name=version,enum=ct.Version,def=256" json:"version,omitempty"`
+	Id             *LogID           `protobuf:"bytes,2,opt,name=id" json:"id,omitempty"`
+	Timestamp      *uint64          `protobuf:"varint,3,opt,name=timestamp" json:"timestamp,omitempty"`
+	TreeSize       *int64           `protobuf:"varint,4,opt,name=tree_size,json=treeSize" json:"tree_size,omitempty"`
+	Sha256RootHash []byte           `protobuf:"bytes,5,opt,name=sha256_root_hash,json=sha256RootHash" json:"sha256_root_hash,omitempty"`
+	Signature      *DigitallySigned `protobuf:"bytes,6,opt,name=signature" json:"signature,omitempty"`
+	// Only supported in V2. <0..2^16-1>
+	SthExtension     []*SthExtension `protobuf:"bytes,7,rep,name=sth_extension,json=sthExtension" json:"sth_extension,omitempty"`
+	XXX_unrecognized []byte          `json:"-"`
+}
+
+func (m *SignedTreeHead) Reset()                    { *m = SignedTreeHead{} }
+func (m *SignedTreeHead) String() string            { return proto.CompactTextString(m) }
+func (*SignedTreeHead) ProtoMessage()               {}
+func (*SignedTreeHead) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+const Default_SignedTreeHead_Version Version = Version_UNKNOWN_VERSION
+
+func (m *SignedTreeHead) GetVersion() Version {
+	if m != nil && m.Version != nil {
+		return *m.Version
+	}
+	return Default_SignedTreeHead_Version
+}
+
+func (m *SignedTreeHead) GetId() *LogID {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *SignedTreeHead) GetTimestamp() uint64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
+}
+
+func (m *SignedTreeHead) GetTreeSize() int64 {
+	if m != nil && m.TreeSize != nil {
+		return *m.TreeSize
+	}
+	return 0
+}
+
+func (m *SignedTreeHead) GetSha256RootHash() []byte {
+	if m != nil {
+		return m.Sha256RootHash
+	}
+	return nil
+}
+
+func (m *SignedTreeHead) GetSignature() *DigitallySigned {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *SignedTreeHead) GetSthExtension() []*SthExtension {
+	if m != nil {
+		return m.SthExtension
+	}
+	return nil
+}
+
+// Stuff the SSL client spits out from a connection.
+type SSLClientCTData struct {
+	ReconstructedEntry    *LogEntry                  `protobuf:"bytes,1,opt,name=reconstructed_entry,json=reconstructedEntry" json:"reconstructed_entry,omitempty"`
+	CertificateSha256Hash []byte                     `protobuf:"bytes,2,opt,name=certificate_sha256_hash,json=certificateSha256Hash" json:"certificate_sha256_hash,omitempty"`
+	AttachedSctInfo       []*SSLClientCTData_SCTInfo `protobuf:"bytes,3,rep,name=attached_sct_info,json=attachedSctInfo" json:"attached_sct_info,omitempty"`
+	XXX_unrecognized      []byte                     `json:"-"`
+}
+
+func (m *SSLClientCTData) Reset()                    { *m = SSLClientCTData{} }
+func (m *SSLClientCTData) String() string            { return proto.CompactTextString(m) }
+func (*SSLClientCTData) ProtoMessage()               {}
+func (*SSLClientCTData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *SSLClientCTData) GetReconstructedEntry() *LogEntry {
+	if m != nil {
+		return m.ReconstructedEntry
+	}
+	return nil
+}
+
+func (m *SSLClientCTData) GetCertificateSha256Hash() []byte {
+	if m != nil {
+		return m.CertificateSha256Hash
+	}
+	return nil
+}
+
+func (m *SSLClientCTData) GetAttachedSctInfo() []*SSLClientCTData_SCTInfo {
+	if m != nil {
+		return m.AttachedSctInfo
+	}
+	return nil
+}
+
+type SSLClientCTData_SCTInfo struct {
+	// There is an entry + sct -> leaf hash mapping.
+	Sct              *SignedCertificateTimestamp `protobuf:"bytes,1,opt,name=sct" json:"sct,omitempty"`
+	MerkleLeafHash   []byte                      `protobuf:"bytes,2,opt,name=merkle_leaf_hash,json=merkleLeafHash" json:"merkle_leaf_hash,omitempty"`
+	XXX_unrecognized []byte                      `json:"-"`
+}
+
+func (m *SSLClientCTData_SCTInfo) Reset()                    { *m = SSLClientCTData_SCTInfo{} }
+func (m *SSLClientCTData_SCTInfo) String() string            { return proto.CompactTextString(m) }
+func (*SSLClientCTData_SCTInfo) ProtoMessage()               {}
+func (*SSLClientCTData_SCTInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19, 0} }
+
+func (m *SSLClientCTData_SCTInfo) GetSct() *SignedCertificateTimestamp {
+	if m != nil {
+		return m.Sct
+	}
+	return nil
+}
+
+func (m *SSLClientCTData_SCTInfo) GetMerkleLeafHash() []byte {
+	if m != nil {
+		return m.MerkleLeafHash
+	}
+	return nil
+}
+
+type ClusterNodeState struct {
+	NodeId             *string         `protobuf:"bytes,1,opt,name=node_id,json=nodeId" json:"node_id,omitempty"`
+	ContiguousTreeSize *int64          `protobuf:"varint,2,opt,name=contiguous_tree_size,json=contiguousTreeSize" json:"contiguous_tree_size,omitempty"`
+	NewestSth          *SignedTreeHead `protobuf:"bytes,3,opt,name=newest_sth,json=newestSth" json:"newest_sth,omitempty"`
+	CurrentServingSth  *SignedTreeHead `protobuf:"bytes,4,opt,name=current_serving_sth,json=currentServingSth" json:"current_serving_sth,omitempty"`
+	// The following host_name/log_port pair are used to allow a log node to
+	// contact other nodes in the cluster, primarily for the purposes of
+	// replication.
+	// hostname/ip which can be used to contact [just] this log node
+	Hostname *string `protobuf:"bytes,5,opt,name=hostname" json:"hostname,omitempty"`
+	// port on which this log node is listening.
+	LogPort          *int32 `protobuf:"varint,6,opt,name=log_port,json=logPort" json:"log_port,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *ClusterNodeState) Reset()                    { *m = ClusterNodeState{} }
+func (m *ClusterNodeState) String() string            { return proto.CompactTextString(m) }
+func (*ClusterNodeState) ProtoMessage()               {}
+func (*ClusterNodeState) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *ClusterNodeState) GetNodeId() string {
+	if m != nil && m.NodeId != nil {
+		return *m.NodeId
+	}
+	return ""
+}
+
+func (m *ClusterNodeState) GetContiguousTreeSize() int64 {
+	if m != nil && m.ContiguousTreeSize != nil {
+		return *m.ContiguousTreeSize
+	}
+	return 0
+}
+
+func (m *ClusterNodeState) GetNewestSth() *SignedTreeHead {
+	if m != nil {
+		return m.NewestSth
+	}
+	return nil
+}
+
+func (m *ClusterNodeState) GetCurrentServingSth() *SignedTreeHead {
+	if m != nil {
+		return m.CurrentServingSth
+	}
+	return nil
+}
+
+func (m *ClusterNodeState) GetHostname() string {
+	if m != nil && m.Hostname != nil {
+		return *m.Hostname
+	}
+	return ""
+}
+
+func (m *ClusterNodeState) GetLogPort() int32 {
+	if m != nil && m.LogPort != nil {
+		return *m.LogPort
+	}
+	return 0
+}
+
+type ClusterControl struct {
+	AcceptNewEntries *bool  `protobuf:"varint,1,opt,name=accept_new_entries,json=acceptNewEntries,def=1" json:"accept_new_entries,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *ClusterControl) Reset()                    { *m = ClusterControl{} }
+func (m *ClusterControl) String() string            { return proto.CompactTextString(m) }
+func (*ClusterControl) ProtoMessage()               {}
+func (*ClusterControl) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+const Default_ClusterControl_AcceptNewEntries bool = true
+
+func (m *ClusterControl) GetAcceptNewEntries() bool {
+	if m != nil && m.AcceptNewEntries != nil {
+		return *m.AcceptNewEntries
+	}
+	return Default_ClusterControl_AcceptNewEntries
+}
+
+type ClusterConfig struct {
+	// The minimum number of nodes which must be able to serve a given STH.
+	// This setting allows you to configure the level of cluster resiliency
+	// against data (in the form of node/node database) loss.
+	// i.e.: Once an STH has been created, it must have been replicated to
+	// at least this many nodes before being considered as a candidate for
+	// the overall cluster serving STH.
+	MinimumServingNodes *int32 `protobuf:"varint,1,opt,name=minimum_serving_nodes,json=minimumServingNodes" json:"minimum_serving_nodes,omitempty"`
+	// The minimum fraction of nodes which must be able to serve a given STH.
+	// This setting allows you to configure the serving capacity redundancy of
+	// your cluster.
+	// e.g. you determine you need 3 nodes to serve your expected peak traffic
+	// levels, but want to be over-provisioned by 25% to ensure the cluster will
+	// continue to be able to handle the traffic in the case of a single node
+	// failure, you might set this to 0.75 to ensure that any cluster-wide
+	// serving STH candidate must be servable from at least 3 of your 4 nodes.
+	MinimumServingFraction *float64 `protobuf:"fixed64,2,opt,name=minimum_serving_fraction,json=minimumServingFraction" json:"minimum_serving_fraction,omitempty"`
+	// When the number of entries in the EtcedConsistentStore exceeds this value,
+	// the log server will reject all calls to add-[pre-]chain to protect itself
+	// and etcd.
+	EtcdRejectAddPendingThreshold *float64 `protobuf:"fixed64,3,opt,name=etcd_reject_add_pending_threshold,json=etcdRejectAddPendingThreshold,def=30000" json:"etcd_reject_add_pending_threshold,omitempty"`
+	XXX_unrecognized              []byte   `json:"-"`
+}
+
+func (m *ClusterConfig) Reset()                    { *m = ClusterConfig{} }
+func (m *ClusterConfig) String() string            { return proto.CompactTextString(m) }
+func (*ClusterConfig) ProtoMessage()               {}
+func (*ClusterConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+const Default_ClusterConfig_EtcdRejectAddPendingThreshold float64 = 30000
+
+func (m *ClusterConfig) GetMinimumServingNodes() int32 {
+	if m != nil && m.MinimumServingNodes != nil {
+		return *m.MinimumServingNodes
+	}
+	return 0
+}
+
+func (m *ClusterConfig) GetMinimumServingFraction() float64 {
+	if m != nil && m.MinimumServingFraction != nil {
+		return *m.MinimumServingFraction
+	}
+	return 0
+}
+
+func (m *ClusterConfig) GetEtcdRejectAddPendingThreshold() float64 {
+	if m != nil && m.EtcdRejectAddPendingThreshold != nil {
+		return *m.EtcdRejectAddPendingThreshold
+	}
+	return Default_ClusterConfig_EtcdRejectAddPendingThreshold
+}
+
+type SequenceMapping struct {
+	Mapping          []*SequenceMapping_Mapping `protobuf:"bytes,1,rep,name=mapping" json:"mapping,omitempty"`
+	XXX_unrecognized []byte                     `json:"-"`
+}
+
+func (m *SequenceMapping) Reset()                    { *m = SequenceMapping{} }
+func (m *SequenceMapping) String() string            { return proto.CompactTextString(m) }
+func (*SequenceMapping) ProtoMessage()               {}
+func (*SequenceMapping) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *SequenceMapping) GetMapping() []*SequenceMapping_Mapping {
+	if m != nil {
+		return m.Mapping
+	}
+	return nil
+}
+
+type SequenceMapping_Mapping struct {
+	EntryHash        []byte `protobuf:"bytes,1,opt,name=entry_hash,json=entryHash" json:"entry_hash,omitempty"`
+	SequenceNumber   *int64 `protobuf:"varint,2,opt,name=sequence_number,json=sequenceNumber" json:"sequence_number,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *SequenceMapping_Mapping) Reset()                    { *m = SequenceMapping_Mapping{} }
+func (m *SequenceMapping_Mapping) String() string            { return proto.CompactTextString(m) }
+func (*SequenceMapping_Mapping) ProtoMessage()               {}
+func (*SequenceMapping_Mapping) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23, 0} }
+
+func (m *SequenceMapping_Mapping) GetEntryHash() []byte {
+	if m != nil {
+		return m.EntryHash
+	}
+	return nil
+}
+
+func (m *SequenceMapping_Mapping) GetSequenceNumber() int64 {
+	if m != nil && m.SequenceNumber != nil {
+		return *m.SequenceNumber
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*DigitallySigned)(nil), "ct.DigitallySigned")
+	proto.RegisterType((*X509ChainEntry)(nil), "ct.X509ChainEntry")
+	proto.RegisterType((*PreCert)(nil), "ct.PreCert")
+	proto.RegisterType((*CertInfo)(nil), "ct.CertInfo")
+	proto.RegisterType((*PrecertChainEntry)(nil), "ct.PrecertChainEntry")
+	proto.RegisterType((*XJSONEntry)(nil), "ct.XJSONEntry")
+	proto.RegisterType((*LogEntry)(nil), "ct.LogEntry")
+	proto.RegisterType((*LogID)(nil), "ct.LogID")
+	proto.RegisterType((*SctExtension)(nil), "ct.SctExtension")
+	proto.RegisterType((*SignedCertificateTimestamp)(nil), "ct.SignedCertificateTimestamp")
+	proto.RegisterType((*SignedCertificateTimestampList)(nil), "ct.SignedCertificateTimestampList")
+	proto.RegisterType((*SignedEntry)(nil), "ct.SignedEntry")
+	proto.RegisterType((*TimestampedEntry)(nil), "ct.TimestampedEntry")
+	proto.RegisterType((*MerkleTreeLeaf)(nil), "ct.MerkleTreeLeaf")
+	proto.RegisterType((*MerkleAuditProof)(nil), "ct.MerkleAuditProof")
+	proto.RegisterType((*ShortMerkleAuditProof)(nil), "ct.ShortMerkleAuditProof")
+	proto.RegisterType((*LoggedEntryPB)(nil), "ct.LoggedEntryPB")
+	proto.RegisterType((*LoggedEntryPB_Contents)(nil), "ct.LoggedEntryPB.Contents")
+	proto.RegisterType((*SthExtension)(nil), "ct.SthExtension")
+	proto.RegisterType((*SignedTreeHead)(nil), "ct.SignedTreeHead")
+	proto.RegisterType((*SSLClientCTData)(nil), "ct.SSLClientCTData")
+	proto.RegisterType((*SSLClientCTData_SCTInfo)(nil), "ct.SSLClientCTData.SCTInfo")
+	proto.RegisterType((*ClusterNodeState)(nil), "ct.ClusterNodeState")
+	proto.RegisterType((*ClusterControl)(nil), "ct.ClusterControl")
+	proto.RegisterType((*ClusterConfig)(nil), "ct.ClusterConfig")
+	proto.RegisterType((*SequenceMapping)(nil), "ct.SequenceMapping")
+	proto.RegisterType((*SequenceMapping_Mapping)(nil), "ct.SequenceMapping.Mapping")
+	proto.RegisterEnum("ct.LogEntryType", LogEntryType_name, LogEntryType_value)
+	proto.RegisterEnum("ct.SignatureType", SignatureType_name, SignatureType_value)
+	proto.RegisterEnum("ct.Version", Version_name, Version_value)
+	proto.RegisterEnum("ct.MerkleLeafType", MerkleLeafType_name, MerkleLeafType_value)
+	proto.RegisterEnum("ct.DigitallySigned_HashAlgorithm", DigitallySigned_HashAlgorithm_name, DigitallySigned_HashAlgorithm_value)
+	proto.RegisterEnum("ct.DigitallySigned_SignatureAlgorithm", DigitallySigned_SignatureAlgorithm_name, DigitallySigned_SignatureAlgorithm_value)
+}
+
+func init() { proto.RegisterFile("ct.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 1861 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0xcd, 0x73, 0x1b, 0x49,
+	0x15, 0xcf, 0x8c, 0x24, 0x4b, 0x7a, 0xd6, 0xc7, 0xb8, 0x1d, 0xef, 0x2a, 0x5e, 0x92, 0xf2, 0x4e,
+	0x15, 0x59, 0x6f, 0xa0, 0xbc, 0xb6, 0x36, 0x76, 0x25, 0xa6, 0x28, 0x50, 0x64, 0x05, 0x6b, 0xd7,
+	0x96, 0x45, 0x8f, 0xd6, 0x24, 0x40, 0xd5, 0x30, 0x99, 0x69, 0x6b, 0x26, 0x91, 0x66, 0xc4, 0x74,
+	0x6b, 0x63, 0xef, 0xc9, 0x37, 0x8e, 0x1c, 0x38, 0x70, 0xe0, 0xc4, 0xbf, 0xc0, 0x89, 0x33, 0x07,
+	0x8e, 0xfc, 0x17, 0xdc, 0xb8, 0x73, 0xe1, 0x40, 0xf5, 0xc7, 0x48, 0x33, 0xb2, 0xbc, 0x84, 0x2d,
+	0xd8, 0x93, 0x5b, 0xaf, 0x7f, 0xfd, 0xfa, 0xbd, 0xdf, 0xfb, 0xea, 0x31, 0x94, 0x5c, 0xb6, 0x33,
+	0x89, 0x23, 0x16, 0x21, 0xdd, 0x65, 0xe6, 0x3f, 0x75, 0xa8, 0x1f, 0x05, 0xc3, 0x80, 0x39, 0xa3,
+	0xd1, 0x95, 0x15, 0x0c, 0x43, 0xe2, 0xa1, 0x1e, 0xd4, 0x7c, 0x87, 0xfa, 0xb6, 0x33, 0x1a, 0x46,
+	0x71, 0xc0, 0xfc, 0x71, 0x43, 0xdb, 0xd2, 0xb6, 0x6b, 0xcd, 0x0f, 0x77, 0x5c, 0xb6, 0xb3, 0x00,
+	0xde, 0x39, 0x76, 0xa8, 0xdf, 0x4a, 0x80, 0x87, 0xf9, 0xde, 0x59, 0xaf, 0x83, 0xab, 0x7e, 0x5a,
+	0x88, 0xce, 0xa1, 0x4a, 0x83, 0x61, 0x4a, 0x9d, 0x2e, 0xd4, 0x3d, 0x5c, 0xa6, 0x8e, 0xff, 0x71,
+	0xd8, 0x34, 0x26, 0x73, 0x9d, 0xe5, 0x56, 0xef, 0xac, 0xf7, 0xf2, 0xf4, 0xec, 0x0b, 0x0b, 0x57,
+	0x68, 0x30, 0x9c, 0xeb, 0xfd, 0x0e, 0x94, 0x69, 0x02, 0x6f, 0xe4, 0xb6, 0xb4, 0xed, 0x0a, 0x9e,
+	0x0b, 0xcc, 0x5f, 0x42, 0x35, 0x63, 0x1b, 0x2a, 0x81, 0xb0, 0xce, 0xb8, 0x83, 0x8a, 0x90, 0x3b,
+	0x3d, 0xda, 0x37, 0x34, 0x2e, 0xb2, 0x8e, 0x5b, 0x7b, 0x86, 0x8e, 0x00, 0x56, 0xac, 0xe3, 0x56,
+	0xb3, 0xf9, 0xd8, 0xc8, 0x25, 0xeb, 0xfd, 0x03, 0x23, 0xaf, 0xd6, 0x9f, 0x3e, 0x79, 0x6c, 0x14,
+	0xd4, 0x7a, 0x7f, 0xaf, 0x69, 0xac, 0x98, 0x3f, 0x06, 0x74, 0xd3, 0x54, 0x54, 0x85, 0xb9, 0xb1,
+	0xf2, 0x1e, 0x6c, 0xb5, 0x0c, 0x8d, 0x2f, 0x8e, 0xac, 0x96, 0xa1, 0xa3, 0x32, 0x14, 0x3a, 0x6d,
+	0xbe, 0xcc, 0x99, 0xbf, 0xd3, 0xa0, 0xf6, 0x62, 0x7f, 0xf7, 0x69, 0xdb, 0x77, 0x82, 0xb0, 0x13,
+	0xb2, 0xf8, 0x0a, 0x7d, 0x0c, 0xc6, 0x88, 0x38, 0x17, 0xb6, 0x4b, 0x62, 0x16, 0x5c, 0x04, 0xae,
+	0xc3, 0x88, 0xa0, 0xbe, 0x82, 0xeb, 0x5c, 0xde, 0x9e, 0x8b, 0xd1, 0xc7, 0x50, 0xe6, 0x28, 0x3b,
+	0x08, 0x2f, 0x22, 0xe1, 0xfb, 0x6a, 0xb3, 0xc2, 0xf9, 0xe4, 0x98, 0x6e, 0x78, 0x11, 0xe1, 0x92,
+	0xab, 0x56, 0xe8, 0x7b, 0xb0, 0x96, 0x52, 0x68, 0xbb, 0xfc, 0xbe, 0x86, 0xbe, 0x95, 0xdb, 0xae,
+	0x60, 0x23, 0xb5, 0x21, 0xec, 0x30, 0x7f, 0x0e, 0xc5, 0x7e, 0x4c, 0xb8, 0x16, 0xf4, 0x10, 0xea,
+	0x01, 0xa5, 0x53, 0x12, 0xdb, 0x6f, 0xc8, 0x95, 0xcd, 0x43, 0xaa, 0x8c, 0xa9, 0x4a, 0xf1, 0xe7,
+	0xe4, 0x8a, 0x13, 0x8c, 0x3e, 0x82, 0x3a, 0x7b, 0x45, 0x33, 0x46, 0xeb, 0x02, 0x57, 0x63, 0xaf,
+	0x68, 0xca, 0x66, 0xf3, 0x17, 0x50, 0x4a, 0xcc, 0xfb, 0xdf, 0x2b, 0xff, 0x8b, 0x06, 0x6b, 0xfd,
+	0x98, 0x70, 0x60, 0x8a, 0xd1, 0x8f, 0xa0, 0x3e, 0x89, 0xc9, 0x12, 0x42, 0x6b, 0x13, 0xe9, 0x65,
+	0xc2, 0xe7, 0x1e, 0xdc, 0x9d, 0xc8, 0xd3, 0xcb, 0x78, 0x5a, 0xcf, 0xee, 0x89, 0x0b, 0xd0, 0x43,
+	0x28, 0x25, 0xba, 0x55, 0x04, 0x56, 0x79, 0x04, 0x14, 0x7d, 0xb8, 0xa8, 0x6e, 0xc8, 0x86, 0x2a,
+	0xff, 0x75, 0xa1, 0x32, 0xb7, 0x00, 0x5e, 0x7c, 0x66, 0x9d, 0xf5, 0xa4, 0xf1, 0x08, 0xf2, 0xaf,
+	0x69, 0x14, 0x0a, 0x8b, 0xcb, 0x58, 0xac, 0xcd, 0xbf, 0x6b, 0x50, 0x3a, 0x89, 0x86, 0x12, 0xf0,
+	0x04, 0xf2, 0xec, 0x6a, 0x42, 0x54, 0x79, 0x1a, 0x5c, 0x69, 0xb2, 0x37, 0xb8, 0x9a, 0x90, 0x43,
+	0xf4, 0x45, 0xef, 0xf3, 0xde, 0xd9, 0xcf, 0x7a, 0x76, 0xa7, 0x37, 0xc0, 0x2f, 0xed, 0xc1, 0xcb,
+	0x7e, 0x07, 0x8b, 0x13, 0x68, 0x0f, 0xe0, 0x72, 0x7f, 0xf7, 0xa9, 0x4d, 0x38, 0x56, 0x30, 0xba,
+	0xda, 0x44, 0xfc, 0x7c, 0x36, 0x23, 0x71, 0x99, 0xa3, 0xe4, 0x65, 0x87, 0x50, 0x55, 0x2c, 0xa8,
+	0x53, 0xd2, 0xe7, 0x0d, 0xe5, 0x73, 0x96, 0x78, 0x5c, 0x51, 0x58, 0x79, 0x76, 0x17, 0x2a, 0x97,
+	0x36, 0xb7, 0x5f, 0x1d, 0x95, 0x2c, 0xd4, 0xc4, 0x85, 0x33, 0x7f, 0x31, 0x5c, 0x7e, 0x46, 0x23,
+	0x79, 0xde, 0x7c, 0x00, 0x85, 0x93, 0x68, 0xd8, 0x3d, 0x42, 0x1b, 0xb0, 0xc2, 0x33, 0x24, 0xf0,
+	0x54, 0xe0, 0x0a, 0x6f, 0xc8, 0x55, 0xd7, 0x33, 0x5f, 0x43, 0xc5, 0x72, 0x59, 0xe7, 0x92, 0x91,
+	0x90, 0x06, 0x51, 0x88, 0xbe, 0x0f, 0x88, 0xba, 0xcc, 0x26, 0x89, 0xc0, 0x9e, 0x11, 0x53, 0xc5,
+	0x06, 0x4d, 0x21, 0x39, 0x31, 0x37, 0xd1, 0x9e, 0xc3, 0x1c, 0x95, 0x58, 0x19, 0xf4, 0x91, 0xc3,
+	0x1c, 0xf3, 0xf7, 0x3a, 0x6c, 0xca, 0xf6, 0x94, 0xca, 0x98, 0x41, 0x30, 0x26, 0x94, 0x39, 0xe3,
+	0x09, 0x3a, 0x80, 0xe2, 0x97, 0x24, 0xe6, 0x68, 0x15, 0x08, 0x91, 0x06, 0xe7, 0x52, 0x74, 0x58,
+	0x4f, 0x62, 0x70, 0xde, 0xc1, 0x56, 0xf7, 0xac, 0x87, 0x13, 0x30, 0xba, 0x07, 0x7a, 0xe0, 0x29,
+	0xee, 0xcb, 0x2a, 0x76, 0xdd, 0x23, 0xac, 0x07, 0x1e, 0xef, 0x6c, 0x2c, 0xd1, 0x2f, 0x78, 0xce,
+	0xe3, 0xb9, 0x00, 0xed, 0xa5, 0xfb, 0x9e, 0xa4, 0x72, 0x7d, 0x49, 0x2f, 0x4d, 0x35, 0x43, 0xf4,
+	0x00, 0x60, 0xe6, 0x2c, 0x6d, 0x14, 0x84, 0xa3, 0x29, 0x09, 0xda, 0x87, 0x6a, 0x86, 0x90, 0xc6,
+	0xca, 0x56, 0x6e, 0x7b, 0x55, 0xa6, 0x54, 0x9a, 0x67, 0x5c, 0x49, 0xb3, 0x63, 0xfe, 0x00, 0x1e,
+	0xdc, 0x4e, 0xcc, 0x49, 0x40, 0x19, 0xba, 0x07, 0x25, 0xae, 0x78, 0x14, 0x50, 0xd6, 0xd0, 0x44,
+	0x2d, 0x15, 0xa9, 0xcb, 0xf8, 0x96, 0xf9, 0x1b, 0x0d, 0x56, 0xe5, 0xe9, 0x59, 0xba, 0xf3, 0x6c,
+	0x53, 0x71, 0x16, 0x6b, 0xf4, 0x5d, 0x28, 0xaa, 0x44, 0x52, 0x44, 0xdd, 0x28, 0x31, 0xbe, 0x37,
+	0xab, 0x14, 0x39, 0x04, 0xc4, 0xfa, 0xbf, 0x29, 0xbb, 0x7f, 0x68, 0x60, 0xcc, 0xcc, 0x4e, 0xcc,
+	0xc9, 0xc4, 0x40, 0x5b, 0x8c, 0xc1, 0x27, 0x00, 0x22, 0x95, 0x65, 0x9e, 0xe9, 0xcb, 0x0b, 0x10,
+	0x97, 0x49, 0xb2, 0x44, 0x4d, 0xe0, 0xc3, 0x2b, 0x24, 0x5e, 0xa6, 0x7a, 0xea, 0x82, 0xe0, 0x39,
+	0x09, 0x78, 0x95, 0xce, 0x7f, 0x2c, 0x44, 0x2d, 0xff, 0x9f, 0xa3, 0x56, 0x78, 0xa7, 0xa8, 0xfd,
+	0x55, 0x83, 0xda, 0x29, 0x89, 0xdf, 0x8c, 0xc8, 0x20, 0x26, 0xe4, 0x84, 0x38, 0x17, 0xdf, 0x38,
+	0x87, 0x9f, 0x42, 0x3e, 0x45, 0x80, 0xe8, 0x20, 0x52, 0x33, 0xd7, 0x2a, 0x7a, 0xd0, 0x5a, 0x72,
+	0xf6, 0xa4, 0xd3, 0x7a, 0x9e, 0x6e, 0x41, 0x2d, 0x58, 0x63, 0x73, 0xce, 0x33, 0xac, 0xdc, 0xe5,
+	0x7a, 0x16, 0x03, 0x82, 0x0d, 0xb6, 0x20, 0x31, 0xff, 0xa8, 0x83, 0x21, 0xaf, 0x6b, 0x4d, 0xbd,
+	0x80, 0xf5, 0xe3, 0x28, 0xba, 0xf8, 0x7f, 0x94, 0xe3, 0x07, 0x50, 0x66, 0x31, 0x21, 0x36, 0x0d,
+	0xbe, 0x92, 0x0f, 0x8d, 0x1c, 0x2e, 0x71, 0x81, 0x15, 0x7c, 0x45, 0xb2, 0x79, 0x92, 0x5f, 0xcc,
+	0x93, 0xfb, 0x00, 0x62, 0xa4, 0x07, 0xa1, 0x47, 0x2e, 0x45, 0xe1, 0xe5, 0x70, 0x99, 0x4b, 0xba,
+	0x5c, 0xc0, 0x35, 0x4f, 0x1c, 0xe6, 0xdb, 0x61, 0xe4, 0x11, 0x51, 0x73, 0x15, 0x5c, 0xe2, 0x82,
+	0x5e, 0xe4, 0x11, 0xd4, 0x86, 0x75, 0x71, 0xad, 0x4f, 0x1c, 0xcf, 0x9e, 0x57, 0x7c, 0xf1, 0xf6,
+	0x8a, 0x5f, 0xe3, 0xf8, 0x63, 0xe2, 0x78, 0xb3, 0xb7, 0x89, 0x69, 0xc1, 0x86, 0xe5, 0x47, 0x31,
+	0xbb, 0xc1, 0x53, 0xd6, 0x32, 0x6d, 0x4b, 0xff, 0x1a, 0xcb, 0xf4, 0xac, 0x65, 0xe6, 0xbf, 0x34,
+	0xa8, 0x9e, 0x44, 0xc3, 0xa1, 0x0a, 0x44, 0xff, 0x19, 0x1f, 0xb4, 0x94, 0xfc, 0x7a, 0x4a, 0x42,
+	0x97, 0xd8, 0xe1, 0x74, 0xfc, 0x8a, 0xc4, 0x82, 0xfd, 0x1c, 0xae, 0x25, 0xe2, 0x9e, 0x90, 0xa2,
+	0x6d, 0x30, 0xc6, 0xc2, 0x14, 0x5b, 0xdc, 0x2e, 0x26, 0xbf, 0x9a, 0xe8, 0xe3, 0x59, 0xe6, 0x88,
+	0xd1, 0x7f, 0x00, 0x25, 0x37, 0x0a, 0x19, 0x09, 0x19, 0x6d, 0xe4, 0xb6, 0xf4, 0xed, 0xd5, 0xe6,
+	0xa6, 0x0a, 0xcb, 0xfc, 0xde, 0x9d, 0xb6, 0x42, 0xe0, 0x19, 0x76, 0xf3, 0x57, 0x50, 0x4a, 0xa4,
+	0x68, 0x17, 0x72, 0xd4, 0x65, 0xc2, 0x94, 0xd5, 0xe6, 0x83, 0x79, 0xb1, 0x2d, 0xeb, 0x57, 0x98,
+	0x43, 0x91, 0x09, 0x85, 0xf4, 0x50, 0xac, 0xa4, 0x6b, 0x1a, 0xcb, 0x2d, 0x31, 0x7c, 0x98, 0x9f,
+	0x1d, 0x3e, 0xcc, 0xbf, 0x6d, 0xf8, 0xa4, 0x90, 0xb3, 0xe1, 0xc3, 0xfc, 0xdb, 0x86, 0x0f, 0xf3,
+	0xb3, 0xc3, 0xe7, 0x4f, 0x3a, 0xd4, 0xa4, 0xcd, 0x03, 0x15, 0xdb, 0x6f, 0x7f, 0xe0, 0x64, 0xf2,
+	0x3f, 0xbf, 0x90, 0xff, 0xdb, 0x60, 0x50, 0xdf, 0x69, 0xee, 0x1f, 0xd8, 0x71, 0x14, 0x31, 0x19,
+	0x50, 0x39, 0x60, 0x6a, 0x52, 0x8e, 0xa3, 0x88, 0x89, 0x80, 0x66, 0xe6, 0xd6, 0xca, 0x3b, 0xcd,
+	0x2d, 0xde, 0xe1, 0xd2, 0x5c, 0x35, 0x8a, 0xa9, 0x0e, 0x97, 0xa2, 0x0a, 0x57, 0xd2, 0xc4, 0x99,
+	0x7f, 0xd6, 0xa1, 0x6e, 0x59, 0x27, 0xed, 0x51, 0x40, 0x42, 0xd6, 0x1e, 0x70, 0x22, 0xd1, 0x0f,
+	0x61, 0x3d, 0x26, 0x6e, 0x14, 0x52, 0x16, 0x4f, 0x5d, 0x36, 0xeb, 0x38, 0xda, 0x92, 0x30, 0xa3,
+	0x0c, 0x50, 0xc8, 0xd0, 0x01, 0xbc, 0x9f, 0x7e, 0x1d, 0x2a, 0x97, 0x53, 0xe9, 0xbb, 0x91, 0xda,
+	0xb6, 0xc4, 0xae, 0x70, 0xfa, 0x27, 0xb0, 0xe6, 0x30, 0xe6, 0xb8, 0x3e, 0xf1, 0x6c, 0xea, 0xaa,
+	0x71, 0x94, 0x13, 0x5e, 0x7c, 0x20, 0xbc, 0xc8, 0x9a, 0xb9, 0x63, 0xb5, 0x07, 0x62, 0x3a, 0xd5,
+	0x93, 0x53, 0x96, 0x2b, 0x86, 0xd4, 0x26, 0x81, 0xa2, 0xda, 0xfb, 0x06, 0x59, 0xfd, 0xce, 0x55,
+	0x67, 0xfe, 0x56, 0x07, 0xa3, 0x3d, 0x9a, 0x52, 0x46, 0x62, 0x5e, 0xea, 0x16, 0xe3, 0xaf, 0xe3,
+	0xf7, 0xa1, 0xc8, 0xfb, 0x40, 0xf2, 0x0a, 0x2b, 0xe3, 0x15, 0xfe, 0xb3, 0xeb, 0xa1, 0xc7, 0x70,
+	0x97, 0xd7, 0x5d, 0x30, 0x9c, 0x46, 0x53, 0x6a, 0xcf, 0x93, 0x84, 0xeb, 0xce, 0x3d, 0xd3, 0x1b,
+	0x1a, 0x46, 0xf3, 0xfd, 0x41, 0x92, 0x32, 0x7b, 0x00, 0x21, 0x79, 0x4b, 0x28, 0xb3, 0x29, 0xf3,
+	0x55, 0xcf, 0x47, 0x73, 0x37, 0x92, 0x44, 0xc7, 0x65, 0x89, 0xb2, 0x98, 0x8f, 0x9e, 0xc1, 0xba,
+	0x3b, 0x8d, 0x63, 0x12, 0x32, 0x9b, 0x92, 0xf8, 0xcb, 0x20, 0x1c, 0x8a, 0xb3, 0xf9, 0x5b, 0xcf,
+	0xae, 0x29, 0xb8, 0x25, 0xd1, 0x5c, 0xc7, 0x26, 0x94, 0xfc, 0x88, 0xb2, 0xd0, 0x19, 0x13, 0x91,
+	0xa1, 0x65, 0x3c, 0xfb, 0xcd, 0xdf, 0x29, 0xa3, 0x68, 0x68, 0x4f, 0xa2, 0x98, 0x89, 0xd4, 0x2c,
+	0xe0, 0xe2, 0x28, 0x1a, 0xf6, 0xa3, 0x98, 0x99, 0x47, 0x50, 0x53, 0x84, 0xf0, 0xb6, 0x12, 0x47,
+	0x23, 0xd4, 0x04, 0xe4, 0xb8, 0x2e, 0x99, 0x30, 0x3b, 0x24, 0x6f, 0x45, 0x1e, 0x05, 0x84, 0x0a,
+	0x66, 0x4a, 0x87, 0x79, 0x16, 0x4f, 0x09, 0x36, 0xe4, 0x7e, 0x8f, 0xbc, 0xed, 0xc8, 0x5d, 0xf3,
+	0x6f, 0x1a, 0x54, 0xe7, 0x6a, 0x2e, 0x82, 0x21, 0x6a, 0xc2, 0xc6, 0x38, 0x08, 0x83, 0xf1, 0x74,
+	0x3c, 0x73, 0x89, 0xb3, 0x2a, 0x15, 0x15, 0xf0, 0xba, 0xda, 0x54, 0x0e, 0xf0, 0x60, 0x50, 0xf4,
+	0x04, 0x1a, 0x8b, 0x67, 0x2e, 0x62, 0xc7, 0x65, 0xbc, 0x34, 0x38, 0xe7, 0x1a, 0x7e, 0x2f, 0x7b,
+	0xec, 0xb9, 0xda, 0x45, 0x67, 0xf0, 0x21, 0x61, 0xae, 0x67, 0xc7, 0xe4, 0x35, 0x71, 0x99, 0xed,
+	0x78, 0x9e, 0x3d, 0x21, 0xa1, 0xc7, 0x35, 0x30, 0x3f, 0x26, 0xd4, 0x8f, 0x46, 0x9e, 0x08, 0x85,
+	0x76, 0x58, 0xf8, 0x74, 0x77, 0x77, 0x77, 0x17, 0xdf, 0xe7, 0x78, 0x2c, 0xe0, 0x2d, 0xcf, 0xeb,
+	0x4b, 0xf0, 0x20, 0xc1, 0x9a, 0x7f, 0xd0, 0xa0, 0x6e, 0xa9, 0xde, 0x7e, 0xea, 0x4c, 0x26, 0x41,
+	0x38, 0x44, 0xfb, 0x50, 0x1c, 0xcb, 0xa5, 0x78, 0xec, 0x25, 0x29, 0x9e, 0x45, 0xed, 0xa8, 0xbf,
+	0x38, 0xc1, 0x6e, 0xfe, 0x14, 0x8a, 0x89, 0x86, 0xfb, 0xc9, 0xbb, 0x2a, 0xf5, 0x49, 0x28, 0x5f,
+	0x51, 0xc9, 0xe7, 0xe0, 0xe2, 0x98, 0xd1, 0x97, 0x8d, 0x99, 0x47, 0x53, 0xa8, 0xa4, 0x5f, 0x62,
+	0xa8, 0x06, 0xc0, 0x3f, 0x6d, 0xe4, 0x97, 0x90, 0x71, 0x07, 0xad, 0x41, 0xb5, 0x8f, 0x3b, 0xed,
+	0x0e, 0x1e, 0x28, 0x91, 0x86, 0xee, 0x82, 0x91, 0x11, 0xd9, 0xe7, 0x4d, 0x43, 0x47, 0x08, 0x2a,
+	0x2f, 0x6c, 0xfe, 0x8d, 0xa2, 0x70, 0xd7, 0xd7, 0x3a, 0x6a, 0xc0, 0x92, 0x2f, 0x2b, 0xe3, 0xfa,
+	0x3a, 0xff, 0xe8, 0x29, 0x54, 0x67, 0xa3, 0x57, 0xdc, 0x7b, 0x0f, 0x36, 0xb8, 0xc6, 0xee, 0xf3,
+	0x6e, 0xbb, 0x35, 0xe8, 0xd8, 0x83, 0xee, 0x69, 0xc7, 0x1a, 0xb4, 0x4e, 0xfb, 0xc6, 0x1d, 0xfe,
+	0xcf, 0x82, 0x01, 0xee, 0x74, 0xec, 0xe3, 0x4e, 0xeb, 0xc8, 0xd0, 0x1e, 0x7d, 0x02, 0x45, 0xd5,
+	0xc2, 0xd1, 0x0a, 0xe8, 0xe7, 0x7b, 0xc6, 0x1d, 0xf1, 0xb7, 0x29, 0x2c, 0x5b, 0x6c, 0xea, 0xc6,
+	0xb5, 0xfe, 0xe8, 0x47, 0xc9, 0x2b, 0x2e, 0x79, 0x6b, 0xa1, 0x0d, 0x58, 0x9b, 0x5d, 0xd0, 0x39,
+	0x9a, 0xf9, 0xfa, 0x1e, 0xdc, 0x7c, 0x84, 0x19, 0xd7, 0xfa, 0xbf, 0x07, 0x00, 0x49, 0xb0, 0xb7,
+	0x3e, 0x0a, 0x12, 0x00, 0x00,
+}
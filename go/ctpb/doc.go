@@ -0,0 +1,6 @@
+// Package ctpb holds the Go bindings for the protocol buffers in
+// proto/ct.proto, which are used by the C++ log server and its databases, and
+// functions to convert between them and the types in the ct package.
+package ctpb
+
+//go:generate protoc -I ../../proto --go_out=import_path=ctpb:. ../../proto/ct.proto
@@ -0,0 +1,90 @@
+package ct
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/google/certificate-transparency/go/x509"
+)
+
+// EmbeddedSCTResult holds the outcome of verifying a single SCT embedded in a
+// final certificate.
+type EmbeddedSCTResult struct {
+	SCT SignedCertificateTimestamp
+	// Err is nil if the SCT's signature verified, an UnknownLogError if the
+	// SCT was issued by a log without a verifier, and the verification
+	// failure otherwise.
+	Err error
+}
+
+// EmbeddedSCTs returns the SCTs embedded in |cert|'s SCT list extension.
+// SCTs with a version this package doesn't understand are skipped.
+// Returns an error if |cert| doesn't carry an SCT list, or it can't be parsed.
+func EmbeddedSCTs(cert *x509.Certificate) ([]SignedCertificateTimestamp, error) {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(x509.OIDExtensionCTSCTList) {
+			scts, _, err := DeserializeSCTList(ext.Value)
+			return scts, err
+		}
+	}
+	return nil, errors.New("certificate has no embedded SCT list")
+}
+
+// PrecertLogEntryFromFinalCert returns the LogEntry for the Precertificate
+// from which the final certificate |cert| was issued by |issuer|, suitable
+// for passing to VerifySCTSignature along with |cert|'s embedded SCTs.
+// |issuer| must be the CA which issued |cert|, even if the Precertificate was
+// issued by a Precertificate Signing Certificate: the issuer key hash in the
+// SCT signature input is always that of the real CA.
+// The entry's timestamp and extensions are left empty, since they come from
+// each SCT in turn.
+func PrecertLogEntryFromFinalCert(cert, issuer *x509.Certificate) (*LogEntry, error) {
+	if issuer.IsPrecertificateSigningCert() {
+		return nil, errors.New("issuer is a Precertificate Signing Certificate, not the CA which issued the final certificate")
+	}
+	if err := cert.CheckSignatureFrom(issuer); err != nil {
+		return nil, fmt.Errorf("certificate was not issued by issuer: %v", err)
+	}
+	tbs, err := x509.RemoveSCTList(cert.RawTBSCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove SCT list from TBSCertificate: %v", err)
+	}
+	return &LogEntry{
+		Leaf: MerkleTreeLeaf{
+			Version:  V1,
+			LeafType: TimestampedEntryLeafType,
+			TimestampedEntry: TimestampedEntry{
+				EntryType: PrecertLogEntryType,
+				PrecertEntry: PreCert{
+					IssuerKeyHash:  sha256.Sum256(issuer.RawSubjectPublicKeyInfo),
+					TBSCertificate: tbs,
+				},
+			},
+		},
+	}, nil
+}
+
+// VerifyEmbeddedSCTs verifies each of the SCTs embedded in the final
+// certificate |cert|, issued by |issuer|, using |verifiers|.
+// Returns one result per embedded SCT, in the order they appear in the
+// certificate, or an error if the SCTs couldn't be extracted from |cert| at
+// all.
+func VerifyEmbeddedSCTs(cert, issuer *x509.Certificate, verifiers LogVerifiers) ([]EmbeddedSCTResult, error) {
+	scts, err := EmbeddedSCTs(cert)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := PrecertLogEntryFromFinalCert(cert, issuer)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]EmbeddedSCTResult, len(scts))
+	for i, sct := range scts {
+		e := *entry
+		e.Leaf.TimestampedEntry.Timestamp = sct.Timestamp
+		e.Leaf.TimestampedEntry.Extensions = sct.Extensions
+		results[i] = EmbeddedSCTResult{SCT: sct, Err: verifiers.VerifySCT(sct, e)}
+	}
+	return results, nil
+}
@@ -0,0 +1,94 @@
+package ct
+
+import (
+	"testing"
+
+	"github.com/google/certificate-transparency/go/testonly"
+	"github.com/google/certificate-transparency/go/x509"
+)
+
+func readTestCert(t *testing.T, name string) *x509.Certificate {
+	cert, err := x509.ParseCertificate(testonly.ReadPEM(t, name))
+	if x509.IsFatal(err) {
+		t.Fatalf("Failed to parse %s: %v", name, err)
+	}
+	return cert
+}
+
+func testLogVerifiers(t *testing.T) LogVerifiers {
+	v := make(LogVerifiers)
+	if err := v.AddPEMFile(testonly.Path("ct-server-key-public.pem")); err != nil {
+		t.Fatalf("AddPEMFile()=%v", err)
+	}
+	return v
+}
+
+func TestVerifyEmbeddedSCTs(t *testing.T) {
+	verifiers := testLogVerifiers(t)
+	for _, test := range []struct {
+		cert, issuer string
+	}{
+		{"test-embedded-cert.pem", "ca-cert.pem"},
+		{"test-embedded-with-preca-cert.pem", "ca-cert.pem"},
+		{"test-embedded-with-intermediate-cert.pem", "intermediate-cert.pem"},
+		{"test-embedded-with-intermediate-preca-cert.pem", "intermediate-cert.pem"},
+	} {
+		cert := readTestCert(t, test.cert)
+		issuer := readTestCert(t, test.issuer)
+		results, err := VerifyEmbeddedSCTs(cert, issuer, verifiers)
+		if err != nil {
+			t.Errorf("%s: VerifyEmbeddedSCTs()=%v", test.cert, err)
+			continue
+		}
+		if len(results) != 1 {
+			t.Errorf("%s: VerifyEmbeddedSCTs() returned %d results, want 1", test.cert, len(results))
+			continue
+		}
+		if results[0].Err != nil {
+			t.Errorf("%s: embedded SCT failed to verify: %v", test.cert, results[0].Err)
+		}
+
+		// The same SCT from a log we don't know about.
+		results, err = VerifyEmbeddedSCTs(cert, issuer, map[SHA256Hash]SignatureVerifier{})
+		if err != nil {
+			t.Errorf("%s: VerifyEmbeddedSCTs() with no verifiers=%v", test.cert, err)
+			continue
+		}
+		if e, ok := results[0].Err.(UnknownLogError); !ok || e.LogID != results[0].SCT.LogID {
+			t.Errorf("%s: VerifyEmbeddedSCTs() with no verifiers gave %v, want UnknownLogError", test.cert, results[0].Err)
+		}
+	}
+}
+
+func TestVerifyEmbeddedSCTsInvalidSignature(t *testing.T) {
+	cert := readTestCert(t, "test-invalid-embedded-cert.pem")
+	results, err := VerifyEmbeddedSCTs(cert, readTestCert(t, "ca-cert.pem"), testLogVerifiers(t))
+	if err != nil {
+		t.Fatalf("VerifyEmbeddedSCTs()=%v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("VerifyEmbeddedSCTs() returned no results")
+	}
+	for i, r := range results {
+		if r.Err == nil {
+			t.Errorf("SCT %d verified, want error", i)
+		} else if _, ok := r.Err.(UnknownLogError); ok {
+			t.Errorf("SCT %d gave %v, want signature failure", i, r.Err)
+		}
+	}
+}
+
+func TestVerifyEmbeddedSCTsErrors(t *testing.T) {
+	verifiers := testLogVerifiers(t)
+	for _, test := range []struct {
+		desc, cert, issuer string
+	}{
+		{"no SCT list", "test-cert.pem", "ca-cert.pem"},
+		{"wrong issuer", "test-embedded-cert.pem", "intermediate-cert.pem"},
+		{"issuer is Precertificate Signing Certificate", "test-embedded-with-preca-cert.pem", "ca-pre-cert.pem"},
+	} {
+		if _, err := VerifyEmbeddedSCTs(readTestCert(t, test.cert), readTestCert(t, test.issuer), verifiers); err == nil {
+			t.Errorf("%s: VerifyEmbeddedSCTs() succeeded, want error", test.desc)
+		}
+	}
+}
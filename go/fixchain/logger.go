@@ -1,16 +1,16 @@
 package fixchain
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/google/certificate-transparency/go/client"
 	"github.com/google/certificate-transparency/go/x509"
+	"golang.org/x/net/context"
 )
 
 // Limiter is an interface to allow different rate limiters to be used with the
@@ -106,35 +106,15 @@ func (l *Logger) RootCerts() *x509.CertPool {
 }
 
 func (l *Logger) getRoots() (*x509.CertPool, error) {
-	rootsJSON, err := l.client.Get(l.url + "/ct/v1/get-roots")
+	roots, err := client.New(l.url, l.client).GetAcceptedRoots(context.Background())
 	if err != nil {
+		if rspErr, ok := err.(client.RspError); ok {
+			return nil, fmt.Errorf("can't get roots from %s: %s: %s", l.url, rspErr, rspErr.Body)
+		}
 		return nil, fmt.Errorf("can't get roots from %s: %s", l.url, err)
 	}
-	defer rootsJSON.Body.Close()
-	j, err := ioutil.ReadAll(rootsJSON.Body)
-	if err != nil {
-		return nil, fmt.Errorf("can't read body from %s: %s", l.url, err)
-	}
-	if rootsJSON.StatusCode != 200 {
-		return nil, fmt.Errorf("can't deal with status other than 200 from %s: %d\nbody: %s", l.url, rootsJSON.StatusCode, string(j))
-	}
-	type Certificates struct {
-		Certificates [][]byte
-	}
-	var certs Certificates
-	err = json.Unmarshal(j, &certs)
-	if err != nil {
-		return nil, fmt.Errorf("can't parse json (%s) from %s: %s", err, l.url, j)
-	}
 	ret := x509.NewCertPool()
-	for i := 0; i < len(certs.Certificates); i++ {
-		r, err := x509.ParseCertificate(certs.Certificates[i])
-		switch err.(type) {
-		case nil, x509.NonFatalErrors:
-			// ignore
-		default:
-			return nil, fmt.Errorf("can't parse certificate from %s: %s %#v", l.url, err, certs.Certificates[i])
-		}
+	for _, r := range roots {
 		ret.AddCert(r)
 	}
 	return ret, nil
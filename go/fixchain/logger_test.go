@@ -4,8 +4,25 @@ import (
 	"net/http"
 	"sync"
 	"testing"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/ctfake"
 )
 
+// newTestLog returns a fake CT log which accepts chains to the PEM encoded
+// |roots|.
+func newTestLog(t *testing.T, roots []string) *ctfake.Log {
+	var ders []ct.ASN1Cert
+	for _, root := range roots {
+		ders = append(ders, GetTestCertificateFromPEM(t, root).Raw)
+	}
+	l, err := ctfake.NewLog(ctfake.Config{Roots: ders})
+	if err != nil {
+		t.Fatalf("NewLog()=%v", err)
+	}
+	return l
+}
+
 // NewLogger() test
 func TestNewLogger(t *testing.T) {
 	// Test single chain posts.
@@ -39,11 +56,11 @@ func TestNewLogger(t *testing.T) {
 func TestNewLoggerCaching(t *testing.T) {
 	// Test logging multiple chains by looking at caching.
 	newLoggerTest := struct {
-		url          string
+		roots        []string
 		chains       [][]string
 		expectedErrs []errorType
 	}{
-		"https://ct.googleapis.com/pilot",
+		[]string{verisignRoot, testRoot},
 		[][]string{
 			{googleLeaf, thawteIntermediate, verisignRoot},
 			{googleLeaf, thawteIntermediate, verisignRoot},
@@ -58,7 +75,9 @@ func TestNewLoggerCaching(t *testing.T) {
 	wg.Add(1)
 	go testErrors(t, 0, newLoggerTest.expectedErrs, errors, &wg)
 
-	l := NewLogger(5, newLoggerTest.url, errors, &http.Client{Transport: &newLoggerTestRoundTripper{}}, newNilLimiter(), false)
+	fake := newTestLog(t, newLoggerTest.roots)
+	defer fake.Close()
+	l := NewLogger(5, fake.URL(), errors, &http.Client{}, newNilLimiter(), false)
 
 	for _, chain := range newLoggerTest.chains {
 		l.QueueChain(extractTestChain(t, 0, chain))
@@ -186,21 +205,20 @@ func TestLoggerQueueChain(t *testing.T) {
 // Logger.RootCerts() test
 func TestRootCerts(t *testing.T) {
 	rootCertsTests := []struct {
-		url           string
-		expectedRoots []string
+		roots []string
 	}{
-		{
-			"https://ct.googleapis.com/pilot",
-			[]string{verisignRoot, comodoRoot}, // These are not the actual roots for the pilot CT log, this is just for testing purposes.
-		},
+		{[]string{verisignRoot}},
+		{[]string{verisignRoot, comodoRoot}},
 	}
 
 	for i, test := range rootCertsTests {
+		fake := newTestLog(t, test.roots)
 		l := &Logger{
-			url:    test.url,
-			client: &http.Client{Transport: &rootCertsTestRoundTripper{}},
+			url:    fake.URL(),
+			client: &http.Client{},
 		}
 		roots := l.RootCerts()
-		matchTestRoots(t, i, test.expectedRoots, roots)
+		fake.Close()
+		matchTestRoots(t, i, test.roots, roots)
 	}
 }
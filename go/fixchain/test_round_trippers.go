@@ -229,40 +229,3 @@ func (rt postTestRoundTripper) RoundTrip(request *http.Request) (*http.Response,
 		Request:       request,
 	}, nil
 }
-
-type newLoggerTestRoundTripper struct{}
-
-func (rt newLoggerTestRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
-	// Return a response
-	b := stringRootsToJSON([]string{verisignRoot})
-	return &http.Response{
-		Status:        "200 OK",
-		StatusCode:    200,
-		Proto:         request.Proto,
-		ProtoMajor:    request.ProtoMajor,
-		ProtoMinor:    request.ProtoMinor,
-		Body:          &bytesReadCloser{bytes.NewReader(b)},
-		ContentLength: int64(len(b)),
-		Request:       request,
-	}, nil
-}
-
-type rootCertsTestRoundTripper struct{}
-
-func (rt rootCertsTestRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
-	url := fmt.Sprintf("%s://%s%s", request.URL.Scheme, request.URL.Host, request.URL.Path)
-	if url == "https://ct.googleapis.com/pilot/ct/v1/get-roots" {
-		b := stringRootsToJSON([]string{verisignRoot, comodoRoot})
-		return &http.Response{
-			Status:        "200 OK",
-			StatusCode:    200,
-			Proto:         request.Proto,
-			ProtoMajor:    request.ProtoMajor,
-			ProtoMinor:    request.ProtoMinor,
-			Body:          &bytesReadCloser{bytes.NewReader(b)},
-			ContentLength: int64(len(b)),
-			Request:       request,
-		}, nil
-	}
-	return nil, errors.New("")
-}
@@ -25,12 +25,15 @@ func (realClock) Now() time.Time {
 }
 
 // SignatureVerifierMap is a map of SignatureVerifier by LogID
+//
+// Deprecated: use ct.LogVerifiers, to which a SignatureVerifierMap can be
+// converted.
 type SignatureVerifierMap map[ct.SHA256Hash]ct.SignatureVerifier
 
 // Handler for the gossip HTTP requests.
 type Handler struct {
 	storage   *Storage
-	verifiers SignatureVerifierMap
+	verifiers ct.LogVerifiers
 	clock     clock
 }
 
@@ -87,13 +90,12 @@ func (h *Handler) HandleSTHPollination(rw http.ResponseWriter, req *http.Request
 
 	sthToKeep := make([]ct.SignedTreeHead, 0, len(p.STHs))
 	for _, sth := range p.STHs {
-		v, found := h.verifiers[sth.LogID]
-		if !found {
-			log.Printf("Pollination entry for unknown logID: %s", sth.LogID.Base64String())
-			continue
-		}
-		if err := v.VerifySTHSignature(sth); err != nil {
-			log.Printf("Failed to verify STH, dropping: %v", err)
+		if err := h.verifiers.VerifySTH(sth); err != nil {
+			if _, ok := err.(ct.UnknownLogError); ok {
+				log.Printf("Pollination entry for unknown logID: %s", sth.LogID.Base64String())
+			} else {
+				log.Printf("Failed to verify STH, dropping: %v", err)
+			}
 			continue
 		}
 		sthToKeep = append(sthToKeep, sth)
@@ -123,7 +125,16 @@ func (h *Handler) HandleSTHPollination(rw http.ResponseWriter, req *http.Request
 // NewHandler creates a new Handler object, taking a pointer a Storage object to
 // use for storing and retrieving feedback and pollination data, and a
 // SignatureVerifierMap for verifying signatures from known logs.
+//
+// Deprecated: use NewHandlerWithVerifiers.
 func NewHandler(s *Storage, v SignatureVerifierMap) Handler {
+	return NewHandlerWithVerifiers(s, ct.LogVerifiers(v))
+}
+
+// NewHandlerWithVerifiers creates a new Handler object, taking a pointer a
+// Storage object to use for storing and retrieving feedback and pollination
+// data, and a LogVerifiers for verifying signatures from known logs.
+func NewHandlerWithVerifiers(s *Storage, v ct.LogVerifiers) Handler {
 	return Handler{
 		storage:   s,
 		verifiers: v,
@@ -133,8 +144,8 @@ func NewHandler(s *Storage, v SignatureVerifierMap) Handler {
 
 // NewHandler creates a new Handler object, taking a pointer a Storage object to
 // use for storing and retrieving feedback and pollination data, and a
-// SignatureVerifierMap for verifying signatures from known logs.
-func newHandlerWithClock(s *Storage, v SignatureVerifierMap, c clock) Handler {
+// LogVerifiers for verifying signatures from known logs.
+func newHandlerWithClock(s *Storage, v ct.LogVerifiers, c clock) Handler {
 	return Handler{
 		storage:   s,
 		verifiers: v,
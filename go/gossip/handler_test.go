@@ -13,17 +13,12 @@ import (
 	"time"
 
 	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/ctfake"
+	"github.com/google/certificate-transparency/go/testonly"
 	"github.com/stretchr/testify/assert"
 )
 
 const (
-	logIDB64 = `aPaY+B9kgr46jO65KB1M/HFRXWeT1ETRCmesu09P+8Q=`
-
-	pubKey = "-----BEGIN PUBLIC KEY-----\n" +
-		"MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE1/TMabLkDpCjiupacAlP7xNi0I1J\n" +
-		"YP8bQFAHDG1xhtolSY1l4QgNRzRrvSe8liE+NPWHdjGxfx3JhTsN9x8/6Q==\n" +
-		"-----END PUBLIC KEY-----\n"
-
 	addSCTFeedbackJSON = `
       {
         "sct_feedback": [
@@ -52,62 +47,6 @@ const (
 
 	stuckClockTimeMillis       = 1441360035224 // Fri Sep  4 10:47:15 BST 2015
 	stuckClockTimeFutureMillis = 1450000000000 // Sun Dec 13 09:46:40 GMT 2015
-
-	addSTHPollinationJSON = `
-      {
-        "sths": [
-          {
-            "sth_version": 0,
-            "tree_size": 8285192,
-            "timestamp": 1441360035224,
-            "sha256_root_hash": "5g2CdT06dF6YcEDPYO50jQWqRvnGwi5BcgGYY10e3+I=",
-            "tree_head_signature": "BAMASDBGAiEAnGFvHwZJsSMkj7nd+Hshd9lOcWQvi1HIA2t1D47I1W4CIQCGu7+aVm0y/hxWGk+HcFIqoA9DptQkdxUdgIrdq5LRQw==",
-            "log_id": "aPaY+B9kgr46jO65KB1M/HFRXWeT1ETRCmesu09P+8Q="
-          }, {
-            "sth_version": 0,
-            "tree_size": 8285157,
-            "timestamp": 1441356438793,
-            "sha256_root_hash": "A9YRqKNRutdXq3ADPeRxJrqAZv24w4bACrM9IBKK/io=",
-            "tree_head_signature": "BAMARzBFAiEAxFSIDea57BRpB+RQxwd2/gzEieOXZx2Hvu7/0L0Oo7wCICH4hte0sPI6G5IGYJbL0lDTMjnGC7NmUOIQRBrm07vM",
-            "log_id": "aPaY+B9kgr46jO65KB1M/HFRXWeT1ETRCmesu09P+8Q="
-          }, {
-            "sth_version": 0,
-            "tree_size": 8285124,
-            "timestamp": 1441352904860,
-            "sha256_root_hash": "gIvD8vwCqzvI/cCM3vT5l5VBXbyeGXOgU1eymOHy2S0=",
-            "tree_head_signature": "BAMARjBEAiAwV+gwZQVJxPDrVLc32QiWnu44Mw4wT3oK8AkyuihU/AIgb/qc3UPgZDSx+C9nadKCCsgrQ285Hm5HnngtfY7ph18=",
-            "log_id": "aPaY+B9kgr46jO65KB1M/HFRXWeT1ETRCmesu09P+8Q="
-          }
-        ]
-      }`
-
-	addSTHPollinationUnknownLogIDJSON = `
-      {
-        "sths": [
-          {
-            "sth_version": 0,
-            "tree_size": 8285192,
-            "timestamp": 1441360035224,
-            "sha256_root_hash": "5g2CdT06dF6YcEDPYO50jQWqRvnGwi5BcgGYY10e3+I=",
-            "tree_head_signature": "BAMASDBGAiEAnGFvHwZJsSMkj7nd+Hshd9lOcWQvi1HIA2t1D47I1W4CIQCGu7+aVm0y/hxWGk+HcFIqoA9DptQkdxUdgIrdq5LRQw==",
-            "log_id": "BLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLA="
-          }
-        ]
-      }`
-
-	addSTHPollinationInvalidSignatureJSON = `
-      {
-        "sths": [
-          {
-            "sth_version": 0,
-            "tree_size": 8285192,
-            "timestamp": 1441360035224,
-            "sha256_root_hash": "5g2CdT06dF6YcEDPYO50jQWqRvnGwi5BcgGYY10e3+I=",
-            "tree_head_signature": "BAMARLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAHBLAH",
-            "log_id": "aPaY+B9kgr46jO65KB1M/HFRXWeT1ETRCmesu09P+8Q="
-          }
-        ]
-      }`
 )
 
 type stuckClock struct {
@@ -147,18 +86,52 @@ func closeAndDeleteStorage(s *Storage) {
 	}
 }
 
-func mustCreateSignatureVerifiers(t *testing.T) SignatureVerifierMap {
-	m := make(SignatureVerifierMap)
-	key, id, _, err := ct.PublicKeyFromPEM([]byte(pubKey))
+func mustCreateSignatureVerifiers(t *testing.T, logs ...*ctfake.Log) ct.LogVerifiers {
+	m := make(ct.LogVerifiers)
+	for _, l := range logs {
+		if err := m.AddPEM([]byte(l.PublicKeyPEM())); err != nil {
+			t.Fatalf("Failed to load pubkey: %v", err)
+		}
+	}
+	return m
+}
+
+// mustCreateLog returns a fake CT log whose clock is stuck at
+// stuckClockTimeMillis.
+func mustCreateLog(t *testing.T) *ctfake.Log {
+	l, err := ctfake.NewLog(ctfake.Config{
+		Roots: []ct.ASN1Cert{testonly.ReadPEM(t, "ca-cert.pem")},
+		Now:   testStuckClock(stuckClockTimeMillis).Now,
+	})
 	if err != nil {
-		t.Fatalf("Failed to parse pubkey: %v", err)
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	return l
+}
+
+// mustGetSTHPollination returns pollination holding the STHs signed by |l|
+// as each of |n| new entries is added to it.
+func mustGetSTHPollination(t *testing.T, l *ctfake.Log, n int) STHPollination {
+	var p STHPollination
+	for i := 0; i < n; i++ {
+		if _, err := l.AddJSON(i); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+		sth, err := l.STH()
+		if err != nil {
+			t.Fatalf("Failed to get STH: %v", err)
+		}
+		p.STHs = append(p.STHs, *sth)
 	}
-	sv, err := ct.NewSignatureVerifier(key)
+	return p
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	b, err := json.Marshal(v)
 	if err != nil {
-		t.Fatalf("Failed to create new SignatureVerifier: %v", err)
+		t.Fatalf("Failed to marshal JSON: %v", err)
 	}
-	m[id] = *sv
-	return m
+	return b
 }
 
 func sctFeedbackFromString(t *testing.T, s string) SCTFeedback {
@@ -280,11 +253,14 @@ func TestRejectsInvalidSCTFeedback(t *testing.T) {
 func TestHandlesValidSTHPollination(t *testing.T) {
 	s := createAndOpenStorage()
 	defer closeAndDeleteStorage(s)
-	v := mustCreateSignatureVerifiers(t)
+	l := mustCreateLog(t)
+	defer l.Close()
+	v := mustCreateSignatureVerifiers(t, l)
 	h := newHandlerWithClock(s, v, testStuckClock(stuckClockTimeMillis))
+	f := mustGetSTHPollination(t, l, 3)
 
 	rr := httptest.NewRecorder()
-	req, err := http.NewRequest("POST", "/.well-known/ct/v1/sth-pollination", strings.NewReader(addSTHPollinationJSON))
+	req, err := http.NewRequest("POST", "/.well-known/ct/v1/sth-pollination", bytes.NewReader(mustMarshal(t, f)))
 	if err != nil {
 		t.Fatalf("Failed to create request: %v", err)
 	}
@@ -294,21 +270,41 @@ func TestHandlesValidSTHPollination(t *testing.T) {
 		t.Fatal(rr.Body.String())
 	}
 
-	f := sthPollinationFromString(t, addSTHPollinationJSON)
-
 	assert.EqualValues(t, len(f.STHs), mustGet(t, s.getNumSTHs))
 	for _, sth := range f.STHs {
 		assert.True(t, s.hasSTH(sth))
 	}
 }
 
+func TestNewHandlerAcceptsSignatureVerifierMap(t *testing.T) {
+	s := createAndOpenStorage()
+	defer closeAndDeleteStorage(s)
+	l := mustCreateLog(t)
+	defer l.Close()
+	h := NewHandler(s, SignatureVerifierMap(mustCreateSignatureVerifiers(t, l)))
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/.well-known/ct/v1/sth-pollination", bytes.NewReader(mustMarshal(t, mustGetSTHPollination(t, l, 1))))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	h.HandleSTHPollination(rr, req)
+	if !assert.Equal(t, http.StatusOK, rr.Code) {
+		t.Fatal(rr.Body.String())
+	}
+	assert.EqualValues(t, 1, mustGet(t, s.getNumSTHs))
+}
+
 func TestHandlesDuplicateSTHPollination(t *testing.T) {
 	s := createAndOpenStorage()
 	defer closeAndDeleteStorage(s)
-	v := mustCreateSignatureVerifiers(t)
+	l := mustCreateLog(t)
+	defer l.Close()
+	v := mustCreateSignatureVerifiers(t, l)
 	h := newHandlerWithClock(s, v, testStuckClock(stuckClockTimeMillis))
 
-	pollen := sthPollinationFromString(t, addSTHPollinationJSON)
+	pollen := mustGetSTHPollination(t, l, 3)
 	pollenJSON, err := json.Marshal(pollen)
 	if err != nil {
 		t.Fatalf("Failed to marshal pollen JSON: %v", err)
@@ -351,11 +347,14 @@ func TestHandlesInvalidSTHPollination(t *testing.T) {
 func TestRejectsSTHFromUnknownLog(t *testing.T) {
 	s := createAndOpenStorage()
 	defer closeAndDeleteStorage(s)
-	v := mustCreateSignatureVerifiers(t)
+	known, unknown := mustCreateLog(t), mustCreateLog(t)
+	defer known.Close()
+	defer unknown.Close()
+	v := mustCreateSignatureVerifiers(t, known)
 	h := newHandlerWithClock(s, v, testStuckClock(stuckClockTimeMillis))
 
 	rr := httptest.NewRecorder()
-	req, err := http.NewRequest("POST", "/.well-known/ct/v1/sth-pollination", strings.NewReader(addSTHPollinationUnknownLogIDJSON))
+	req, err := http.NewRequest("POST", "/.well-known/ct/v1/sth-pollination", bytes.NewReader(mustMarshal(t, mustGetSTHPollination(t, unknown, 1))))
 	if err != nil {
 		t.Fatalf("Failed to create request: %v", err)
 	}
@@ -371,11 +370,14 @@ func TestRejectsSTHFromUnknownLog(t *testing.T) {
 func TestRejectsSTHWithInvalidSignature(t *testing.T) {
 	s := createAndOpenStorage()
 	defer closeAndDeleteStorage(s)
-	v := mustCreateSignatureVerifiers(t)
+	l := mustCreateLog(t)
+	defer l.Close()
+	l.SetMisbehaviour(ctfake.Misbehaviour{BadSignatures: true})
+	v := mustCreateSignatureVerifiers(t, l)
 	h := newHandlerWithClock(s, v, testStuckClock(stuckClockTimeMillis))
 
 	rr := httptest.NewRecorder()
-	req, err := http.NewRequest("POST", "/.well-known/ct/v1/sth-pollination", strings.NewReader(addSTHPollinationInvalidSignatureJSON))
+	req, err := http.NewRequest("POST", "/.well-known/ct/v1/sth-pollination", bytes.NewReader(mustMarshal(t, mustGetSTHPollination(t, l, 1))))
 	if err != nil {
 		t.Fatalf("Failed to create request: %v", err)
 	}
@@ -391,10 +393,12 @@ func TestRejectsSTHWithInvalidSignature(t *testing.T) {
 func TestReturnsSTHPollination(t *testing.T) {
 	s := createAndOpenStorage()
 	defer closeAndDeleteStorage(s)
-	v := mustCreateSignatureVerifiers(t)
+	l := mustCreateLog(t)
+	defer l.Close()
+	v := mustCreateSignatureVerifiers(t, l)
 	h := newHandlerWithClock(s, v, testStuckClock(stuckClockTimeMillis))
 
-	sentPollen := sthPollinationFromString(t, addSTHPollinationJSON)
+	sentPollen := mustGetSTHPollination(t, l, 3)
 	sentPollenJSON, err := json.Marshal(sentPollen)
 	if err != nil {
 		t.Fatalf("Failed to marshal pollen JSON: %v", err)
@@ -430,10 +434,12 @@ func TestReturnsSTHPollination(t *testing.T) {
 func TestDoesNotReturnStalePollen(t *testing.T) {
 	s := createAndOpenStorage()
 	defer closeAndDeleteStorage(s)
-	v := mustCreateSignatureVerifiers(t)
+	l := mustCreateLog(t)
+	defer l.Close()
+	v := mustCreateSignatureVerifiers(t, l)
 	h := newHandlerWithClock(s, v, testStuckClock(stuckClockTimeFutureMillis))
 
-	sentPollen := sthPollinationFromString(t, addSTHPollinationJSON)
+	sentPollen := mustGetSTHPollination(t, l, 3)
 	sentPollenJSON, err := json.Marshal(sentPollen)
 	if err != nil {
 		t.Fatalf("Failed to marshal pollen JSON: %v", err)
@@ -458,10 +464,12 @@ func TestLimitsSTHPollinationReturned(t *testing.T) {
 	defer closeAndDeleteStorage(s)
 
 	*defaultNumPollinationsToReturn = 1
-	v := mustCreateSignatureVerifiers(t)
+	l := mustCreateLog(t)
+	defer l.Close()
+	v := mustCreateSignatureVerifiers(t, l)
 	h := newHandlerWithClock(s, v, testStuckClock(stuckClockTimeMillis))
 
-	sentPollen := sthPollinationFromString(t, addSTHPollinationJSON)
+	sentPollen := mustGetSTHPollination(t, l, 3)
 	sentPollenJSON, err := json.Marshal(sentPollen)
 	if err != nil {
 		t.Fatalf("Failed to marshal pollen JSON: %v", err)
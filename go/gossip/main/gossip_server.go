@@ -3,51 +3,51 @@ package main
 import (
 	"errors"
 	"flag"
-	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"strings"
 
 	ct "github.com/google/certificate-transparency/go"
 	"github.com/google/certificate-transparency/go/gossip"
+	"github.com/google/certificate-transparency/go/loglist"
 )
 
 var dbPath = flag.String("database", "/tmp/gossip.sq3", "Path to database.")
 var listenAddress = flag.String("listen", ":8080", "Listen address:port for HTTP server.")
 var logKeys = flag.String("log_public_keys", "", "Comma separated list of files containing trusted Logs' public keys in PEM format")
+var logList = flag.String("log_list", "", "File containing the JSON list of trusted Logs, used instead of --log_public_keys")
 
-func createVerifiers() (*gossip.SignatureVerifierMap, error) {
-	m := make(gossip.SignatureVerifierMap)
-	if len(*logKeys) == 0 {
-		return nil, errors.New("--log_public_keys is empty")
-	}
-	keys := strings.Split(*logKeys, ",")
-	for _, k := range keys {
-		pem, err := ioutil.ReadFile(k)
+func createVerifiers() (ct.LogVerifiers, error) {
+	if len(*logList) > 0 {
+		ll, err := loglist.NewFromFile(*logList)
+		if err != nil {
+			return nil, err
+		}
+		verifiers, err := ll.SignatureVerifiers()
 		if err != nil {
-			return nil, fmt.Errorf("failed to read specified PEM file %s: %v", k, err)
+			return nil, err
 		}
-		for len(pem) > 0 {
-			key, id, rest, err := ct.PublicKeyFromPEM(pem)
-			pem = rest
-			if err != nil {
-				return nil, fmt.Errorf("failed to read public key from PEM in file %s: %v", k, err)
-			}
-			sv, err := ct.NewSignatureVerifier(key)
-			if err != nil {
-				return nil, fmt.Errorf("Failed to create new SignatureVerifier: %v", err)
-			}
-			m[id] = *sv
-			log.Printf("Loaded key for LogID %v", id)
+		log.Printf("Loaded keys for %d Logs from %s", len(verifiers), *logList)
+		return verifiers, nil
+	}
+	if len(*logKeys) == 0 {
+		return nil, errors.New("one of --log_list or --log_public_keys is required")
+	}
+	verifiers := make(ct.LogVerifiers)
+	for _, k := range strings.Split(*logKeys, ",") {
+		if err := verifiers.AddPEMFile(k); err != nil {
+			return nil, err
 		}
 	}
-	return &m, nil
+	for id := range verifiers {
+		log.Printf("Loaded key for LogID %v", id.Base64String())
+	}
+	return verifiers, nil
 }
 
 func main() {
 	flag.Parse()
-	verifierMap, err := createVerifiers()
+	verifiers, err := createVerifiers()
 	if err != nil {
 		log.Fatalf("Failed to load log public keys: %v", err)
 	}
@@ -59,7 +59,7 @@ func main() {
 	}
 	defer storage.Close()
 
-	handler := gossip.NewHandler(&storage, *verifierMap)
+	handler := gossip.NewHandlerWithVerifiers(&storage, verifiers)
 	serveMux := http.NewServeMux()
 	serveMux.HandleFunc("/.well-known/ct/v1/sct-feedback", handler.HandleSCTFeedback)
 	serveMux.HandleFunc("/.well-known/ct/v1/sth-pollination", handler.HandleSTHPollination)
@@ -0,0 +1,244 @@
+// Package loglist parses the JSON list of known CT logs (as published at
+// https://www.gstatic.com/ct/log_list/log_list.json and described by
+// python/ct/client/tools/data/log_list_schema.json), and builds clients and
+// signature verifiers for the logs it describes.
+package loglist
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+)
+
+// LogList holds the contents of a log list file.
+type LogList struct {
+	Operators []Operator `json:"operators"`
+	Logs      []Log      `json:"logs"`
+
+	byID map[ct.SHA256Hash]*Log
+}
+
+// Operator describes an organisation which runs CT logs.
+type Operator struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Log describes a single CT log.
+type Log struct {
+	Description       string `json:"description"`
+	Key               []byte `json:"key"`                 // DER encoded SubjectPublicKeyInfo
+	URL               string `json:"url"`                 // normally without a scheme
+	MaximumMergeDelay int    `json:"maximum_merge_delay"` // in seconds
+	OperatedBy        []int  `json:"operated_by"`         // Operator IDs
+	DNSAPIEndpoint    string `json:"dns_api_endpoint,omitempty"`
+	// DisqualifiedAt is the time (in seconds since the epoch) at which the
+	// log was disqualified, or zero if it hasn't been.
+	DisqualifiedAt int64 `json:"disqualified_at,omitempty"`
+	// FinalSTH is the last STH which the log is trusted to have produced, for
+	// logs which have been frozen or disqualified.
+	FinalSTH *FinalSTH `json:"final_sth,omitempty"`
+}
+
+// FinalSTH is the STH recorded for a log which has stopped operating.
+type FinalSTH struct {
+	TreeSize          uint64 `json:"tree_size"`
+	Timestamp         uint64 `json:"timestamp"`
+	SHA256RootHash    []byte `json:"sha256_root_hash"`
+	TreeHeadSignature []byte `json:"tree_head_signature"`
+}
+
+// NewFromJSON parses the log list in |llData|, checking that every log has a
+// key and a URL, is operated by a known operator, and has a unique log ID.
+func NewFromJSON(llData []byte) (*LogList, error) {
+	var ll LogList
+	if err := json.Unmarshal(llData, &ll); err != nil {
+		return nil, fmt.Errorf("failed to parse log list: %v", err)
+	}
+	operators := make(map[int]bool)
+	for _, op := range ll.Operators {
+		operators[op.ID] = true
+	}
+	ll.byID = make(map[ct.SHA256Hash]*Log)
+	for i := range ll.Logs {
+		l := &ll.Logs[i]
+		if len(l.Key) == 0 {
+			return nil, fmt.Errorf("log %q has no key", l.Description)
+		}
+		if l.URL == "" {
+			return nil, fmt.Errorf("log %q has no URL", l.Description)
+		}
+		for _, id := range l.OperatedBy {
+			if !operators[id] {
+				return nil, fmt.Errorf("log %q is operated by unknown operator %d", l.Description, id)
+			}
+		}
+		id := l.LogID()
+		if other, ok := ll.byID[id]; ok {
+			return nil, fmt.Errorf("logs %q and %q have the same log ID %s", other.Description, l.Description, id.Base64String())
+		}
+		ll.byID[id] = l
+	}
+	return &ll, nil
+}
+
+// NewFromFile reads and parses the log list in the file |filename|.
+func NewFromFile(filename string) (*LogList, error) {
+	llData, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log list: %v", err)
+	}
+	return NewFromJSON(llData)
+}
+
+// FindLogByID returns the log with the given log ID, or nil if there is no
+// such log in the list.
+func (ll *LogList) FindLogByID(id ct.SHA256Hash) *Log {
+	return ll.byID[id]
+}
+
+// FindLogByURL returns the log with the given URL, ignoring any scheme or
+// trailing slash, or nil if there is no such log in the list.
+func (ll *LogList) FindLogByURL(url string) *Log {
+	url = normaliseURL(url)
+	for i := range ll.Logs {
+		if normaliseURL(ll.Logs[i].URL) == url {
+			return &ll.Logs[i]
+		}
+	}
+	return nil
+}
+
+// OperatorNames returns the names of the operators of |l|.
+func (ll *LogList) OperatorNames(l *Log) []string {
+	var names []string
+	for _, id := range l.OperatedBy {
+		for _, op := range ll.Operators {
+			if op.ID == id {
+				names = append(names, op.Name)
+			}
+		}
+	}
+	return names
+}
+
+// QualifiedLogs returns the logs which have not been disqualified.
+func (ll *LogList) QualifiedLogs() []*Log {
+	var logs []*Log
+	for i := range ll.Logs {
+		if !ll.Logs[i].Disqualified() {
+			logs = append(logs, &ll.Logs[i])
+		}
+	}
+	return logs
+}
+
+// SignatureVerifiers returns a SignatureVerifier for each log in the list,
+// keyed by log ID. |opts| are passed to ct.NewSignatureVerifier. Logs whose
+// keys can't be used are skipped, so that one log with an unsupported key
+// doesn't make the rest of the list unusable; an error is only returned if no
+// log in the list is usable.
+func (ll *LogList) SignatureVerifiers(opts ...ct.VerifierOption) (ct.LogVerifiers, error) {
+	verifiers := make(ct.LogVerifiers)
+	var lastErr error
+	for i := range ll.Logs {
+		l := &ll.Logs[i]
+		sv, err := l.SignatureVerifier(opts...)
+		if err != nil {
+			log.Printf("Skipping log: %v", err)
+			lastErr = err
+			continue
+		}
+		verifiers[l.LogID()] = *sv
+	}
+	if len(verifiers) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return verifiers, nil
+}
+
+// LogID returns the log's ID, the SHA-256 hash of its key.
+func (l *Log) LogID() ct.SHA256Hash {
+	return sha256.Sum256(l.Key)
+}
+
+// Disqualified returns true if the log has been disqualified.
+func (l *Log) Disqualified() bool {
+	return l.DisqualifiedAt != 0
+}
+
+// DisqualifiedTime returns the time at which the log was disqualified, or
+// the zero Time if it hasn't been.
+func (l *Log) DisqualifiedTime() time.Time {
+	if !l.Disqualified() {
+		return time.Time{}
+	}
+	return time.Unix(l.DisqualifiedAt, 0)
+}
+
+// MMD returns the log's Maximum Merge Delay.
+func (l *Log) MMD() time.Duration {
+	return time.Duration(l.MaximumMergeDelay) * time.Second
+}
+
+// PublicKey parses and returns the log's public key.
+func (l *Log) PublicKey() (crypto.PublicKey, error) {
+	key, err := ct.ParsePKIXPublicKey(l.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key for log %q: %v", l.Description, err)
+	}
+	return key, nil
+}
+
+// SignatureVerifier returns a SignatureVerifier for the log's key. |opts| are
+// passed to ct.NewSignatureVerifier.
+func (l *Log) SignatureVerifier(opts ...ct.VerifierOption) (*ct.SignatureVerifier, error) {
+	key, err := l.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	sv, err := ct.NewSignatureVerifier(key, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SignatureVerifier for log %q: %v", l.Description, err)
+	}
+	return sv, nil
+}
+
+// BaseURI returns the log's URL with a scheme and without a trailing slash,
+// suitable for use with client.New. Logs are assumed to be served over HTTPS
+// unless the URL says otherwise.
+func (l *Log) BaseURI() string {
+	uri := strings.TrimRight(l.URL, "/")
+	if strings.Contains(uri, "://") {
+		return uri
+	}
+	return "https://" + uri
+}
+
+// Client returns a LogClient for the log, which verifies the signatures on
+// the STHs and SCTs it returns using the log's key.
+func (l *Log) Client(hc *http.Client, opts ...client.Option) (*client.LogClient, error) {
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: l.Key})
+	c, err := client.NewWithPubKey(l.BaseURI(), hc, string(keyPEM), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for log %q: %v", l.Description, err)
+	}
+	return c, nil
+}
+
+func normaliseURL(url string) string {
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+	}
+	return strings.TrimRight(url, "/")
+}
@@ -0,0 +1,232 @@
+package loglist
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/ctfake"
+	"github.com/google/certificate-transparency/go/testonly"
+)
+
+// validSTHResponse is an STH signed by the key in
+// google-ct-pilot-server-key-public.pem.
+const validSTHResponse = `{"tree_size":3721782,"timestamp":1396609800587,
+        "sha256_root_hash":"SxKOxksguvHPyUaKYKXoZHzXl91Q257+JQ0AUMlFfeo=",
+        "tree_head_signature":"BAMARjBEAiBUYO2tODlUUw4oWGiVPUHqZadRRyXs9T2rSXchA79VsQIgLASkQv3cu4XdPFCZbgFkIUefniNPCpO3LzzHX53l+wg="}`
+
+// readKey returns the base64 encoding of the DER key in the PEM file |name|.
+func readKey(t *testing.T, name string) string {
+	return base64.StdEncoding.EncodeToString(testonly.ReadPEM(t, name))
+}
+
+func testLogList(t *testing.T) string {
+	return fmt.Sprintf(`{
+  "operators": [{"id": 0, "name": "Google"}, {"id": 1, "name": "Test Operator"}],
+  "logs": [
+    {"description": "Pilot", "key": "%s", "url": "ct.example.com/pilot/",
+     "maximum_merge_delay": 86400, "operated_by": [0]},
+    {"description": "Test", "key": "%s", "url": "ct.example.com/test/",
+     "maximum_merge_delay": 3600, "operated_by": [0, 1],
+     "disqualified_at": 1475637842,
+     "final_sth": {"tree_size": 1, "timestamp": 2, "sha256_root_hash": "AAAA", "tree_head_signature": "AAAA"}}
+  ]
+}`, readKey(t, "google-ct-pilot-server-key-public.pem"), readKey(t, "ct-server-key-public.pem"))
+}
+
+func TestNewFromJSON(t *testing.T) {
+	ll, err := NewFromJSON([]byte(testLogList(t)))
+	if err != nil {
+		t.Fatalf("NewFromJSON()=%v", err)
+	}
+	if len(ll.Logs) != 2 || len(ll.Operators) != 2 {
+		t.Fatalf("got %d logs and %d operators, want 2 of each", len(ll.Logs), len(ll.Operators))
+	}
+
+	pilot, test := &ll.Logs[0], &ll.Logs[1]
+	if pilot.Disqualified() || !pilot.DisqualifiedTime().IsZero() {
+		t.Errorf("pilot log is disqualified")
+	}
+	if !test.Disqualified() || !test.DisqualifiedTime().Equal(time.Unix(1475637842, 0)) {
+		t.Errorf("test log DisqualifiedTime()=%v", test.DisqualifiedTime())
+	}
+	if got, want := pilot.MMD(), 24*time.Hour; got != want {
+		t.Errorf("MMD()=%v, want %v", got, want)
+	}
+	if test.FinalSTH == nil || test.FinalSTH.TreeSize != 1 {
+		t.Errorf("FinalSTH=%+v", test.FinalSTH)
+	}
+	if got, want := ll.OperatorNames(test), []string{"Google", "Test Operator"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OperatorNames()=%v, want %v", got, want)
+	}
+	if got := ll.QualifiedLogs(); len(got) != 1 || got[0] != pilot {
+		t.Errorf("QualifiedLogs()=%v, want just the pilot log", got)
+	}
+}
+
+func TestNewFromJSONErrors(t *testing.T) {
+	key := readKey(t, "ct-server-key-public.pem")
+	logJSON := func(key, url string, operator int) string {
+		return fmt.Sprintf(`{"description":"log","key":"%s","url":"%s","maximum_merge_delay":1,"operated_by":[%d]}`, key, url, operator)
+	}
+	tests := []struct {
+		desc string
+		logs []string
+		want string
+	}{
+		{"no key", []string{logJSON("", "a", 0)}, "no key"},
+		{"no URL", []string{logJSON(key, "", 0)}, "no URL"},
+		{"unknown operator", []string{logJSON(key, "a", 7)}, "unknown operator"},
+		{"duplicate ID", []string{logJSON(key, "a", 0), logJSON(key, "b", 0)}, "same log ID"},
+	}
+	for _, test := range tests {
+		data := fmt.Sprintf(`{"operators":[{"id":0,"name":"op"}],"logs":[%s]}`, strings.Join(test.logs, ","))
+		if _, err := NewFromJSON([]byte(data)); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: NewFromJSON()=%v, want error containing %q", test.desc, err, test.want)
+		}
+	}
+	if _, err := NewFromJSON([]byte("not json")); err == nil {
+		t.Error("NewFromJSON(not json) succeeded")
+	}
+}
+
+func TestFindLog(t *testing.T) {
+	ll, err := NewFromJSON([]byte(testLogList(t)))
+	if err != nil {
+		t.Fatalf("NewFromJSON()=%v", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(readKey(t, "ct-server-key-public.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ll.FindLogByID(sha256.Sum256(key)); got != &ll.Logs[1] {
+		t.Errorf("FindLogByID()=%v, want test log", got)
+	}
+	if got := ll.FindLogByID(ct.SHA256Hash{}); got != nil {
+		t.Errorf("FindLogByID(unknown)=%v, want nil", got)
+	}
+	for _, url := range []string{"ct.example.com/pilot", "https://ct.example.com/pilot/"} {
+		if got := ll.FindLogByURL(url); got != &ll.Logs[0] {
+			t.Errorf("FindLogByURL(%q)=%v, want pilot log", url, got)
+		}
+	}
+	if got := ll.FindLogByURL("ct.example.com"); got != nil {
+		t.Errorf("FindLogByURL(unknown)=%v, want nil", got)
+	}
+}
+
+func TestSignatureVerifiers(t *testing.T) {
+	ll, err := NewFromJSON([]byte(testLogList(t)))
+	if err != nil {
+		t.Fatalf("NewFromJSON()=%v", err)
+	}
+	verifiers, err := ll.SignatureVerifiers()
+	if err != nil {
+		t.Fatalf("SignatureVerifiers()=%v", err)
+	}
+	if len(verifiers) != 2 {
+		t.Fatalf("got %d verifiers, want 2", len(verifiers))
+	}
+	for _, l := range ll.Logs {
+		if _, ok := verifiers[l.LogID()]; !ok {
+			t.Errorf("no verifier for log %q", l.Description)
+		}
+	}
+}
+
+// ed25519Key is the Ed25519 public key from RFC8410 section 10.1.
+const ed25519Key = "MCowBQYDK2VwAyEAGb9ECWmEzf6FQbrBZ9w7lshQhqowtrbLDFw4rXAxZuE="
+
+func TestSignatureVerifiersWithExtendedAlgorithms(t *testing.T) {
+	logJSON := `{"description":"%s","key":"%s","url":"%s","maximum_merge_delay":1,"operated_by":[0]}`
+	pilot := fmt.Sprintf(logJSON, "Pilot", readKey(t, "google-ct-pilot-server-key-public.pem"), "a")
+	ed := fmt.Sprintf(logJSON, "Ed25519", ed25519Key, "b")
+	newList := func(logs ...string) *LogList {
+		ll, err := NewFromJSON([]byte(fmt.Sprintf(`{"operators":[{"id":0,"name":"op"}],"logs":[%s]}`, strings.Join(logs, ","))))
+		if err != nil {
+			t.Fatalf("NewFromJSON()=%v", err)
+		}
+		return ll
+	}
+
+	// The Ed25519 log is skipped unless extended algorithms are allowed.
+	ll := newList(pilot, ed)
+	if verifiers, err := ll.SignatureVerifiers(); err != nil || len(verifiers) != 1 {
+		t.Errorf("SignatureVerifiers()=%d verifiers, %v; want 1", len(verifiers), err)
+	}
+	if verifiers, err := ll.SignatureVerifiers(ct.WithExtendedAlgorithms()); err != nil || len(verifiers) != 2 {
+		t.Errorf("SignatureVerifiers(WithExtendedAlgorithms())=%d verifiers, %v; want 2", len(verifiers), err)
+	}
+	if _, err := newList(ed).SignatureVerifiers(); err == nil {
+		t.Error("SignatureVerifiers() with no usable logs succeeded")
+	}
+}
+
+func TestClient(t *testing.T) {
+	ll, err := NewFromJSON([]byte(testLogList(t)))
+	if err != nil {
+		t.Fatalf("NewFromJSON()=%v", err)
+	}
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(validSTHResponse))
+	}))
+	defer hs.Close()
+
+	pilot, test := ll.Logs[0], ll.Logs[1]
+	if got, want := pilot.BaseURI(), "https://ct.example.com/pilot"; got != want {
+		t.Errorf("BaseURI()=%q, want %q", got, want)
+	}
+
+	// The pilot log's client should accept the STH signed with its key...
+	pilot.URL = hs.URL
+	c, err := pilot.Client(&http.Client{})
+	if err != nil {
+		t.Fatalf("Client()=%v", err)
+	}
+	if _, err := c.GetSTH(); err != nil {
+		t.Errorf("GetSTH()=%v", err)
+	}
+
+	// ... and the test log's client should reject it.
+	test.URL = hs.URL
+	c, err = test.Client(&http.Client{})
+	if err != nil {
+		t.Fatalf("Client()=%v", err)
+	}
+	if _, err := c.GetSTH(); err == nil {
+		t.Error("GetSTH() verified STH signed with another log's key")
+	}
+}
+
+func TestClientSubmission(t *testing.T) {
+	fake, err := ctfake.NewLog(ctfake.Config{Roots: []ct.ASN1Cert{testonly.ReadPEM(t, "ca-cert.pem")}})
+	if err != nil {
+		t.Fatalf("NewLog()=%v", err)
+	}
+	defer fake.Close()
+	key, err := x509.MarshalPKIXPublicKey(fake.PublicKey())
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey()=%v", err)
+	}
+	// Log list URLs end in a slash, which must not end up doubled in the
+	// submission paths.
+	l := Log{Description: "fake", Key: key, URL: fake.URL() + "/"}
+	c, err := l.Client(&http.Client{})
+	if err != nil {
+		t.Fatalf("Client()=%v", err)
+	}
+	if _, err := c.AddJSON("data"); err != nil {
+		t.Errorf("AddJSON()=%v", err)
+	}
+	if got := fake.TreeSize(); got != 1 {
+		t.Errorf("TreeSize()=%d, want 1", got)
+	}
+}
@@ -0,0 +1,172 @@
+package merkletree
+
+import (
+	"fmt"
+)
+
+// CompactRange represents the leaf range [Begin, End) of a Merkle tree using
+// only the O(log n) hashes of the largest perfect subtrees which exactly cover
+// it (sometimes called the "frontier" of the range).
+//
+// A CompactRange which begins at 0 can be used to compute the root hash of a
+// tree of any size without having to store all of its leaf hashes, and two
+// adjacent ranges can be merged together, which allows several workers to
+// hash disjoint parts of a log in parallel.
+type CompactRange struct {
+	treeHasher *TreeHasher
+
+	begin uint64
+	end   uint64
+
+	// hashes holds the roots of the perfect subtrees covering [begin, end),
+	// ordered from left to right.
+	hashes [][]byte
+}
+
+// CompactRangeState holds the serialisable state of a CompactRange, so that
+// a process can save its progress and later resume from where it left off.
+type CompactRangeState struct {
+	Begin  uint64   `json:"begin"`
+	End    uint64   `json:"end"`
+	Hashes [][]byte `json:"hashes"`
+}
+
+// NewCompactRange returns a new, empty, CompactRange which starts (and ends)
+// at leaf index |begin| and uses the passed in hasher.
+func NewCompactRange(h HasherFunc, begin uint64) *CompactRange {
+	return &CompactRange{
+		treeHasher: NewTreeHasher(h),
+		begin:      begin,
+		end:        begin,
+	}
+}
+
+// NewCompactRangeFromState recreates a CompactRange from a previously saved
+// CompactRangeState.
+// Returns an error if the state is not internally consistent.
+func NewCompactRangeFromState(h HasherFunc, s CompactRangeState) (*CompactRange, error) {
+	if s.Begin > s.End {
+		return nil, fmt.Errorf("invalid range: begin %d > end %d", s.Begin, s.End)
+	}
+	if got, want := len(s.Hashes), len(rangeLevels(s.Begin, s.End)); got != want {
+		return nil, fmt.Errorf("range [%d, %d) requires %d hashes, but state has %d", s.Begin, s.End, want, got)
+	}
+	r := NewCompactRange(h, s.Begin)
+	r.end = s.End
+	r.hashes = make([][]byte, len(s.Hashes))
+	copy(r.hashes, s.Hashes)
+	return r, nil
+}
+
+// Begin returns the index of the first leaf covered by the range.
+func (r *CompactRange) Begin() uint64 {
+	return r.begin
+}
+
+// End returns the index one past the last leaf covered by the range.
+func (r *CompactRange) End() uint64 {
+	return r.end
+}
+
+// Hashes returns the roots of the perfect subtrees which cover the range,
+// ordered from left to right.
+func (r *CompactRange) Hashes() [][]byte {
+	ret := make([][]byte, len(r.hashes))
+	copy(ret, r.hashes)
+	return ret
+}
+
+// State returns the current state of the range, suitable for serialising.
+func (r *CompactRange) State() CompactRangeState {
+	return CompactRangeState{
+		Begin:  r.begin,
+		End:    r.end,
+		Hashes: r.Hashes(),
+	}
+}
+
+// AppendLeaf hashes |leaf| and appends it to the end of the range.
+func (r *CompactRange) AppendLeaf(leaf []byte) {
+	r.AppendLeafHash(r.treeHasher.HashLeaf(leaf))
+}
+
+// AppendLeafHash appends the leaf hash |hash| to the end of the range.
+func (r *CompactRange) AppendLeafHash(hash []byte) {
+	r.appendNode(0, hash)
+}
+
+// Merge appends the range |other| to the end of this range. The two ranges
+// must be adjacent, i.e. other.Begin() must equal r.End().
+// |other| is not modified.
+func (r *CompactRange) Merge(other *CompactRange) error {
+	if other.begin != r.end {
+		return fmt.Errorf("ranges are not adjacent: [%d, %d) and [%d, %d)", r.begin, r.end, other.begin, other.end)
+	}
+	for i, level := range rangeLevels(other.begin, other.end) {
+		r.appendNode(level, other.hashes[i])
+	}
+	return nil
+}
+
+// Root returns the root hash of the tree formed by the leaves [0, End).
+// The range must begin at 0.
+func (r *CompactRange) Root() ([]byte, error) {
+	if r.begin != 0 {
+		return nil, fmt.Errorf("can't calculate root of range beginning at %d", r.begin)
+	}
+	if len(r.hashes) == 0 {
+		return r.treeHasher.HashEmpty(), nil
+	}
+	// Lone right hand nodes are pulled up the tree, so the root is formed by
+	// folding the subtree hashes together from the right.
+	root := r.hashes[len(r.hashes)-1]
+	for i := len(r.hashes) - 2; i >= 0; i-- {
+		root = r.treeHasher.HashChildren(r.hashes[i], root)
+	}
+	return root, nil
+}
+
+// appendNode appends the root |hash| of a perfect subtree of height |level| to
+// the end of the range, merging it with its left siblings where possible.
+// r.end must be a multiple of 2^level.
+func (r *CompactRange) appendNode(level uint, hash []byte) {
+	size := uint64(1) << level
+	index := r.end >> level
+	// While the new node is a right child whose left sibling lies entirely
+	// within the range, the sibling must be the last hash we hold, so combine
+	// the two into their parent.
+	for isRightChildU(index) && (index-1)<<level >= r.begin {
+		last := len(r.hashes) - 1
+		hash = r.treeHasher.HashChildren(r.hashes[last], hash)
+		r.hashes = r.hashes[:last]
+		index >>= 1
+		level++
+	}
+	r.hashes = append(r.hashes, hash)
+	r.end += size
+}
+
+// rangeLevels returns the heights of the perfect subtrees which make up the
+// minimal cover of [begin, end), ordered from left to right.
+func rangeLevels(begin, end uint64) []uint {
+	var left, right []uint
+	for level := uint(0); begin < end; level++ {
+		size := uint64(1) << level
+		// Take a node off the left hand side if begin isn't aligned to the
+		// next level up, and the node fits.
+		if begin&size != 0 && begin+size <= end {
+			left = append(left, level)
+			begin += size
+		}
+		// Likewise for the right hand side.
+		if end&size != 0 && end-size >= begin {
+			right = append(right, level)
+			end -= size
+		}
+	}
+	// The right hand nodes were collected from the bottom up, so reverse them.
+	for i := len(right) - 1; i >= 0; i-- {
+		left = append(left, right[i])
+	}
+	return left
+}
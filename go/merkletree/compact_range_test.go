@@ -0,0 +1,190 @@
+package merkletree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCompactRangeRootMatchesTestVectors(t *testing.T) {
+	r := NewCompactRange(sha256Hasher, 0)
+	root, err := r.Root()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := dh(sha256EmptyTreeHash); !bytes.Equal(root, want) {
+		t.Fatalf("Root() of empty range=%x, want %x", root, want)
+	}
+	for i, l := range testLeaves() {
+		r.AppendLeaf(l)
+		root, err := r.Root()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := rootForTestLeaves(i); !bytes.Equal(root, want) {
+			t.Fatalf("Root() after %d leaves=%x, want %x", i+1, root, want)
+		}
+	}
+}
+
+func TestCompactRangeRootMatchesInMemoryTree(t *testing.T) {
+	m := NewInMemoryMerkleTree(sha256Hasher)
+	r := NewCompactRange(sha256Hasher, 0)
+	for i := 0; i < 300; i++ {
+		leaf := []byte(fmt.Sprintf("leaf %d", i))
+		m.AddLeaf(leaf)
+		r.AppendLeaf(leaf)
+		if got, want := r.End(), m.LeafCount(); got != want {
+			t.Fatalf("End()=%d, want %d", got, want)
+		}
+		got, err := r.Root()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := m.CurrentRoot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("Root() at size %d=%x, want %x", i+1, got, want)
+		}
+		if got, want := len(r.Hashes()), len(rangeLevels(0, r.End())); got != want {
+			t.Fatalf("size %d: got %d hashes, want %d", i+1, got, want)
+		}
+	}
+}
+
+func TestCompactRangeMerge(t *testing.T) {
+	const size = 70
+	leaves := make([][]byte, size)
+	for i := range leaves {
+		leaves[i] = []byte(fmt.Sprintf("leaf %d", i))
+	}
+	m := newTestInMemoryTree(leaves)
+	want, err := m.CurrentRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRange := func(begin, end int) *CompactRange {
+		r := NewCompactRange(sha256Hasher, uint64(begin))
+		for _, l := range leaves[begin:end] {
+			r.AppendLeaf(l)
+		}
+		return r
+	}
+
+	for split1 := 0; split1 <= size; split1++ {
+		for split2 := split1; split2 <= size; split2++ {
+			left := newRange(0, split1)
+			middle := newRange(split1, split2)
+			right := newRange(split2, size)
+			if got, want := len(middle.Hashes()), len(rangeLevels(uint64(split1), uint64(split2))); got != want {
+				t.Fatalf("[%d, %d): got %d hashes, want %d", split1, split2, got, want)
+			}
+			// Merge the right-hand pair first, to exercise merging ranges which
+			// don't start at zero.
+			if err := middle.Merge(right); err != nil {
+				t.Fatal(err)
+			}
+			if err := left.Merge(middle); err != nil {
+				t.Fatal(err)
+			}
+			got, err := left.Root()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("splits %d, %d: Root()=%x, want %x", split1, split2, got, want)
+			}
+		}
+	}
+}
+
+func TestCompactRangeMergeNotAdjacent(t *testing.T) {
+	left := NewCompactRange(sha256Hasher, 0)
+	left.AppendLeaf([]byte("a"))
+	right := NewCompactRange(sha256Hasher, 2)
+	right.AppendLeaf([]byte("c"))
+	if err := left.Merge(right); err == nil {
+		t.Fatal("Merge() of non-adjacent ranges succeeded, want error")
+	}
+}
+
+func TestCompactRangeRootRequiresZeroBegin(t *testing.T) {
+	r := NewCompactRange(sha256Hasher, 1)
+	r.AppendLeaf([]byte("b"))
+	if _, err := r.Root(); err == nil {
+		t.Fatal("Root() of range starting at 1 succeeded, want error")
+	}
+}
+
+func TestCompactRangeState(t *testing.T) {
+	r := NewCompactRange(sha256Hasher, 3)
+	for i := 0; i < 17; i++ {
+		r.AppendLeaf([]byte(fmt.Sprintf("leaf %d", i)))
+	}
+	b, err := json.Marshal(r.State())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s CompactRangeState
+	if err := json.Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
+	}
+	r2, err := NewCompactRangeFromState(sha256Hasher, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.State(), r2.State()) {
+		t.Fatalf("restored state %+v, want %+v", r2.State(), r.State())
+	}
+
+	// Both ranges should carry on identically.
+	r.AppendLeaf([]byte("more"))
+	r2.AppendLeaf([]byte("more"))
+	if !reflect.DeepEqual(r.State(), r2.State()) {
+		t.Fatalf("restored range diverged: %+v, want %+v", r2.State(), r.State())
+	}
+
+	s.Hashes = s.Hashes[1:]
+	if _, err := NewCompactRangeFromState(sha256Hasher, s); err == nil {
+		t.Fatal("NewCompactRangeFromState() with missing hashes succeeded, want error")
+	}
+	if _, err := NewCompactRangeFromState(sha256Hasher, CompactRangeState{Begin: 2, End: 1}); err == nil {
+		t.Fatal("NewCompactRangeFromState() with begin > end succeeded, want error")
+	}
+}
+
+// Checks that roots computed from compact ranges can be tied together with
+// consistency proofs from a full tree.
+func TestCompactRangeConsistency(t *testing.T) {
+	const size = 50
+	m := NewInMemoryMerkleTree(sha256Hasher)
+	r := NewCompactRange(sha256Hasher, 0)
+	v := getVerifier()
+	roots := [][]byte{nil}
+	for i := 0; i < size; i++ {
+		leaf := []byte(fmt.Sprintf("leaf %d", i))
+		m.AddLeaf(leaf)
+		r.AppendLeaf(leaf)
+		root, err := r.Root()
+		if err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, root)
+	}
+	for s1 := 1; s1 < size; s1++ {
+		for s2 := s1 + 1; s2 <= size; s2++ {
+			proof, err := m.SnapshotConsistency(uint64(s1), uint64(s2))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := v.VerifyConsistencyProof(int64(s1), int64(s2), roots[s1], roots[s2], proof); err != nil {
+				t.Fatalf("VerifyConsistencyProof(%d, %d): %v", s1, s2, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package merkletree
+
+import (
+	"errors"
+	"fmt"
+)
+
+// InMemoryMerkleTree is a pure Go implementation of FullMerkleTreeInterface
+// which keeps every node of the tree in memory.
+// It is a port of the C++ MerkleTree class, and uses the same 1-based leaf
+// indexing as CPPMerkleTree so that the two can be used interchangeably.
+// Nodes are evaluated lazily; the tree is only brought up to date when a root
+// or a proof is requested.
+type InMemoryMerkleTree struct {
+	treeHasher *TreeHasher
+
+	// tree holds the nodes of the tree, organised by level and sorted
+	// left-to-right within each level. tree[0] is the leaf level.
+	// The hash of nodes tree[i][j] and tree[i][j+1] (j even) is stored at
+	// tree[i+1][j/2]. When tree[i][j] is the last node of a level and has no
+	// right sibling, a dummy copy is stored instead: tree[i+1][j/2] = tree[i][j].
+	tree [][][]byte
+
+	// leavesProcessed is the number of leaves which have been incorporated
+	// into the upper levels of the tree.
+	leavesProcessed uint64
+
+	// levelCount is the number of levels the fully evaluated tree would have.
+	levelCount uint64
+}
+
+// NewInMemoryMerkleTree returns a new, empty, InMemoryMerkleTree which uses the
+// passed in hasher.
+func NewInMemoryMerkleTree(h HasherFunc) *InMemoryMerkleTree {
+	return &InMemoryMerkleTree{
+		treeHasher: NewTreeHasher(h),
+	}
+}
+
+// LeafCount is the number of leaves in the tree.
+func (m *InMemoryMerkleTree) LeafCount() uint64 {
+	if len(m.tree) == 0 {
+		return 0
+	}
+	return uint64(len(m.tree[0]))
+}
+
+// LevelCount is the number of levels in the tree.
+// An empty tree has 0 levels, a tree with 1 leaf has 1 level, and a tree with
+// n leaves has ceil(log2(n)) + 1 levels.
+func (m *InMemoryMerkleTree) LevelCount() uint64 {
+	return m.levelCount
+}
+
+// AddLeaf adds a new leaf to the hash tree. Stores the hash of the leaf data in
+// the tree structure, does not store the data itself.
+// Returns the position of the leaf in the tree.
+func (m *InMemoryMerkleTree) AddLeaf(leaf []byte) uint64 {
+	return m.AddLeafHash(m.treeHasher.HashLeaf(leaf))
+}
+
+// AddLeafHash adds a leaf hash directly to the tree. The tree keeps its own
+// copy of |hash|. Returns the position of the leaf in the tree.
+func (m *InMemoryMerkleTree) AddLeafHash(hash []byte) uint64 {
+	if len(m.tree) == 0 {
+		m.addLevel()
+		// The first leaf hash is also the first root.
+		m.leavesProcessed = 1
+	}
+	m.pushBack(0, append([]byte(nil), hash...))
+	leafCount := m.LeafCount()
+	// A k-level tree can hold 2^(k-1) leaves, so increment the level count
+	// every time we overflow a power of two.
+	if isPowerOfTwoPlusOne(leafCount) {
+		m.levelCount++
+	}
+	return leafCount
+}
+
+// LeafHash returns the leaf hash for the leaf at the requested index.
+// Indexing starts at 1.
+func (m *InMemoryMerkleTree) LeafHash(leaf uint64) ([]byte, error) {
+	if leaf == 0 || leaf > m.LeafCount() {
+		return nil, fmt.Errorf("failed to get leafhash of leaf %d", leaf)
+	}
+	return m.node(0, leaf-1), nil
+}
+
+// CurrentRoot returns the current root of the tree.
+func (m *InMemoryMerkleTree) CurrentRoot() ([]byte, error) {
+	return m.RootAtSnapshot(m.LeafCount())
+}
+
+// RootAtSnapshot returns the root of the tree as it was when it contained
+// |snapshot| leaves.
+func (m *InMemoryMerkleTree) RootAtSnapshot(snapshot uint64) ([]byte, error) {
+	if snapshot == 0 {
+		return m.treeHasher.HashEmpty(), nil
+	}
+	if snapshot > m.LeafCount() {
+		return nil, fmt.Errorf("failed to get root at snapshot %d", snapshot)
+	}
+	if snapshot >= m.leavesProcessed {
+		return m.updateToSnapshot(snapshot), nil
+	}
+	// snapshot < leavesProcessed: recompute the snapshot root.
+	root, _ := m.recomputePastSnapshot(snapshot, 0, false)
+	return root, nil
+}
+
+// PathToCurrentRoot returns an audit path to the current root for a given leaf.
+func (m *InMemoryMerkleTree) PathToCurrentRoot(leaf uint64) ([][]byte, error) {
+	return m.PathToRootAtSnapshot(leaf, m.LeafCount())
+}
+
+// PathToRootAtSnapshot returns an audit path to a given root for a given leaf.
+func (m *InMemoryMerkleTree) PathToRootAtSnapshot(leaf, snapshot uint64) ([][]byte, error) {
+	if leaf == 0 || leaf > snapshot || snapshot > m.LeafCount() {
+		return nil, fmt.Errorf("failed to get path to root at snapshot %d from leaf %d", snapshot, leaf)
+	}
+	return m.pathFromNodeToRootAtSnapshot(leaf-1, 0, snapshot), nil
+}
+
+// SnapshotConsistency returns a consistency proof between two given snapshots.
+func (m *InMemoryMerkleTree) SnapshotConsistency(snapshot1, snapshot2 uint64) ([][]byte, error) {
+	if snapshot1 == 0 || snapshot1 >= snapshot2 || snapshot2 > m.LeafCount() {
+		return nil, fmt.Errorf("failed to get path to snapshot consistency from %d to %d", snapshot1, snapshot2)
+	}
+
+	var proof [][]byte
+	level := uint64(0)
+	// Rightmost node in snapshot1.
+	node := snapshot1 - 1
+	// Everything left of node is equal in both trees; no need to record it.
+	for isRightChildU(node) {
+		node >>= 1
+		level++
+	}
+
+	if snapshot2 > m.leavesProcessed {
+		// Bring the tree sufficiently up to date.
+		m.updateToSnapshot(snapshot2)
+	}
+
+	// Record the node, unless we already reached the root of snapshot1.
+	if node != 0 {
+		proof = append(proof, m.node(level, node))
+	}
+
+	// Now record the path from this node to the root of snapshot2.
+	return append(proof, m.pathFromNodeToRootAtSnapshot(node, level, snapshot2)...), nil
+}
+
+// updateToSnapshot brings the upper levels of the tree up to date with the
+// first |snapshot| leaves, and returns the root at that size.
+// |snapshot| must be >= leavesProcessed.
+func (m *InMemoryMerkleTree) updateToSnapshot(snapshot uint64) []byte {
+	if snapshot == 0 {
+		return m.treeHasher.HashEmpty()
+	}
+	if snapshot == 1 {
+		return m.node(0, 0)
+	}
+	if snapshot == m.leavesProcessed {
+		return m.root()
+	}
+
+	level := uint64(0)
+	// Index of the first node to be processed at the current level.
+	firstNode := m.leavesProcessed
+	// Index of the last node.
+	lastNode := snapshot - 1
+
+	// Process level-by-level until we converge to a single node.
+	for lastNode != 0 {
+		if uint64(len(m.tree)) <= level+1 {
+			m.addLevel()
+		} else if m.nodeCount(level+1) == (firstNode>>1)+1 {
+			// The leftmost parent at level+1 may already exist, so we need to
+			// update it. Nuke the old parent.
+			m.popBack(level + 1)
+		}
+
+		// Compute the parents of new nodes at the current level.
+		// Start with a left sibling and parse an even number of nodes.
+		for j := firstNode &^ 1; j < lastNode; j += 2 {
+			m.pushBack(level+1, m.treeHasher.HashChildren(m.node(level, j), m.node(level, j+1)))
+		}
+		// If the last node at the current level is a left sibling,
+		// dummy-propagate it one level up.
+		if !isRightChildU(lastNode) {
+			m.pushBack(level+1, m.node(level, lastNode))
+		}
+
+		firstNode >>= 1
+		lastNode >>= 1
+		level++
+	}
+
+	m.leavesProcessed = snapshot
+	return m.root()
+}
+
+// recomputePastSnapshot returns the root of the tree at |snapshot|, which must
+// be <= leavesProcessed.
+// If |wantNode| is true, the value of the last node at level |nodeLevel| in the
+// snapshot tree is also returned.
+func (m *InMemoryMerkleTree) recomputePastSnapshot(snapshot, nodeLevel uint64, wantNode bool) ([]byte, []byte) {
+	var node []byte
+	level := uint64(0)
+	// Index of the rightmost node at the current level for this snapshot.
+	lastNode := snapshot - 1
+
+	if snapshot == m.leavesProcessed {
+		// Nothing to recompute.
+		if wantNode && uint64(len(m.tree)) > nodeLevel {
+			if nodeLevel > 0 {
+				node = m.lastNode(nodeLevel)
+			} else {
+				// Leaf level: grab the last processed leaf.
+				node = m.node(nodeLevel, lastNode)
+			}
+		}
+		return m.root(), node
+	}
+
+	// Recompute nodes on the path of the last leaf.
+	for isRightChildU(lastNode) {
+		if wantNode && nodeLevel == level {
+			node = m.node(level, lastNode)
+		}
+		// Left sibling and parent exist in the snapshot, and are equal to
+		// those in the tree; no need to rehash, move one level up.
+		lastNode >>= 1
+		level++
+	}
+
+	// Now lastNode is the index of a left sibling with no right sibling.
+	subtreeRoot := m.node(level, lastNode)
+	if wantNode && nodeLevel == level {
+		node = subtreeRoot
+	}
+
+	for lastNode != 0 {
+		if isRightChildU(lastNode) {
+			subtreeRoot = m.treeHasher.HashChildren(m.node(level, lastNode-1), subtreeRoot)
+		}
+		// Else the parent is a dummy copy of the current node; do nothing.
+
+		lastNode >>= 1
+		level++
+		if wantNode && nodeLevel == level {
+			node = subtreeRoot
+		}
+	}
+
+	return subtreeRoot, node
+}
+
+// pathFromNodeToRootAtSnapshot returns the audit path from the node at
+// |level|/|node| to the root of the tree at |snapshot|.
+func (m *InMemoryMerkleTree) pathFromNodeToRootAtSnapshot(node, level, snapshot uint64) [][]byte {
+	var path [][]byte
+	if snapshot == 0 {
+		return path
+	}
+	// Index of the last node.
+	lastNode := (snapshot - 1) >> level
+	if level >= m.levelCount || node > lastNode || snapshot > m.LeafCount() {
+		return path
+	}
+
+	if snapshot > m.leavesProcessed {
+		// Bring the tree sufficiently up to date.
+		m.updateToSnapshot(snapshot)
+	}
+
+	// Move up, recording the sibling of the current node at each level.
+	for lastNode != 0 {
+		sibling := siblingU(node)
+		if sibling < lastNode {
+			// The sibling is not the last node of the level in the snapshot
+			// tree, so its value is correct in the tree.
+			path = append(path, m.node(level, sibling))
+		} else if sibling == lastNode {
+			// The sibling is the last node of the level in the snapshot tree,
+			// so we get its value for the snapshot.
+			_, n := m.recomputePastSnapshot(snapshot, level, true)
+			path = append(path, n)
+		}
+		// Else sibling > lastNode so the sibling does not exist. Do nothing.
+		// Continue moving up in the tree, ignoring dummy copies.
+
+		node >>= 1
+		lastNode >>= 1
+		level++
+	}
+	return path
+}
+
+func (m *InMemoryMerkleTree) node(level, index uint64) []byte {
+	return m.tree[level][index]
+}
+
+func (m *InMemoryMerkleTree) root() []byte {
+	top := m.tree[len(m.tree)-1]
+	if len(top) != 1 {
+		panic(errors.New("root level of tree does not contain exactly one node"))
+	}
+	return top[0]
+}
+
+func (m *InMemoryMerkleTree) nodeCount(level uint64) uint64 {
+	return uint64(len(m.tree[level]))
+}
+
+func (m *InMemoryMerkleTree) lastNode(level uint64) []byte {
+	return m.tree[level][len(m.tree[level])-1]
+}
+
+func (m *InMemoryMerkleTree) popBack(level uint64) {
+	m.tree[level] = m.tree[level][:len(m.tree[level])-1]
+}
+
+func (m *InMemoryMerkleTree) pushBack(level uint64, node []byte) {
+	m.tree[level] = append(m.tree[level], node)
+}
+
+func (m *InMemoryMerkleTree) addLevel() {
+	m.tree = append(m.tree, nil)
+}
+
+func isPowerOfTwoPlusOne(leafCount uint64) bool {
+	if leafCount == 0 {
+		return false
+	}
+	if leafCount == 1 {
+		return true
+	}
+	// leafCount is a power of two plus one if and only if
+	// ((leafCount - 1) & (leafCount - 2)) has no bits set.
+	return ((leafCount - 1) & (leafCount - 2)) == 0
+}
+
+func isRightChildU(index uint64) bool {
+	return index&1 == 1
+}
+
+func siblingU(index uint64) uint64 {
+	if isRightChildU(index) {
+		return index - 1
+	}
+	return index + 1
+}
@@ -0,0 +1,260 @@
+package merkletree
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// Ensure that both tree implementations satisfy the full interface.
+var _ FullMerkleTreeInterface = &InMemoryMerkleTree{}
+var _ FullMerkleTreeInterface = &CPPMerkleTree{}
+
+func sha256Hasher(b []byte) []byte {
+	h := sha256.Sum256(b)
+	return h[:]
+}
+
+func newTestInMemoryTree(leaves [][]byte) *InMemoryMerkleTree {
+	m := NewInMemoryMerkleTree(sha256Hasher)
+	for _, l := range leaves {
+		m.AddLeaf(l)
+	}
+	return m
+}
+
+func TestInMemoryEmptyTree(t *testing.T) {
+	m := NewInMemoryMerkleTree(sha256Hasher)
+	if got := m.LeafCount(); got != 0 {
+		t.Fatalf("LeafCount()=%d, want 0", got)
+	}
+	if got := m.LevelCount(); got != 0 {
+		t.Fatalf("LevelCount()=%d, want 0", got)
+	}
+	r, err := m.CurrentRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := dh(sha256EmptyTreeHash); !bytes.Equal(r, want) {
+		t.Fatalf("CurrentRoot()=%x, want %x", r, want)
+	}
+	if _, err := m.LeafHash(1); err == nil {
+		t.Fatal("LeafHash(1) on empty tree succeeded, want error")
+	}
+	if _, err := m.PathToCurrentRoot(1); err == nil {
+		t.Fatal("PathToCurrentRoot(1) on empty tree succeeded, want error")
+	}
+}
+
+func TestInMemoryAddLeaf(t *testing.T) {
+	m := NewInMemoryMerkleTree(sha256Hasher)
+	for index, a := range testLeaves() {
+		i := m.AddLeaf(a)
+		if i != uint64(index+1) {
+			t.Fatalf("Got index %d, expected %d", i, index+1)
+		}
+		if m.LeafCount() != uint64(index+1) {
+			t.Fatalf("LeafCount() %d, didn't match index+1 %d", m.LeafCount(), index+1)
+		}
+		r, err := m.CurrentRoot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(r, rootForTestLeaves(index)) {
+			t.Fatalf("CurrentRoot:\n%v\ndid not equal expected root:\n%v\n", hex.Dump(r), hex.Dump(rootForTestLeaves(index)))
+		}
+	}
+}
+
+func TestInMemoryRootAtSnapshot(t *testing.T) {
+	m := newTestInMemoryTree(testLeaves())
+	// Walk backwards so that every snapshot root has to be recomputed.
+	for i := len(testLeaves()); i > 0; i-- {
+		r, err := m.RootAtSnapshot(uint64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := rootForTestLeaves(i - 1); !bytes.Equal(r, want) {
+			t.Fatalf("RootAtSnapshot(%d)=%x, want %x", i, r, want)
+		}
+	}
+	if _, err := m.RootAtSnapshot(uint64(len(testLeaves()) + 1)); err == nil {
+		t.Fatal("RootAtSnapshot() beyond the end of the tree succeeded, want error")
+	}
+}
+
+func TestInMemoryPathToCurrentRoot(t *testing.T) {
+	m := newTestInMemoryTree(testLeaves())
+	v := getVerifier()
+	inputs := getInputs()
+	roots := getRoots()
+	for i, tv := range getInclusionTestVector() {
+		if tv.leaf == 0 {
+			// Invalid path.
+			if _, err := m.PathToRootAtSnapshot(uint64(tv.leaf), uint64(tv.snapshot)); err == nil {
+				t.Fatalf("i=%d: PathToRootAtSnapshot(%d, %d) succeeded, want error", i, tv.leaf, tv.snapshot)
+			}
+			continue
+		}
+		path, err := m.PathToRootAtSnapshot(uint64(tv.leaf), uint64(tv.snapshot))
+		if err != nil {
+			t.Fatalf("i=%d: %v", i, err)
+		}
+		var want [][]byte
+		for j := int64(0); j < tv.proofLength; j++ {
+			want = append(want, tv.proof[j].h)
+		}
+		if !reflect.DeepEqual(path, want) {
+			t.Fatalf("i=%d: PathToRootAtSnapshot(%d, %d)=%x, want %x", i, tv.leaf, tv.snapshot, path, want)
+		}
+		if err := v.VerifyInclusionProof(tv.leaf-1, tv.snapshot, path, roots[tv.snapshot-1].h, inputs[tv.leaf-1].h); err != nil {
+			t.Fatalf("i=%d: failed to verify path: %v", i, err)
+		}
+	}
+}
+
+func TestInMemorySnapshotConsistency(t *testing.T) {
+	m := newTestInMemoryTree(testLeaves())
+	v := getVerifier()
+	roots := getRoots()
+	for i, tv := range getConsistencyProofs() {
+		if tv.snapshot1 == tv.snapshot2 {
+			if _, err := m.SnapshotConsistency(uint64(tv.snapshot1), uint64(tv.snapshot2)); err == nil {
+				t.Fatalf("i=%d: SnapshotConsistency(%d, %d) succeeded, want error", i, tv.snapshot1, tv.snapshot2)
+			}
+			continue
+		}
+		proof, err := m.SnapshotConsistency(uint64(tv.snapshot1), uint64(tv.snapshot2))
+		if err != nil {
+			t.Fatalf("i=%d: %v", i, err)
+		}
+		var want [][]byte
+		for j := int64(0); j < tv.proofLen; j++ {
+			want = append(want, tv.proof[j].h)
+		}
+		if !reflect.DeepEqual(proof, want) {
+			t.Fatalf("i=%d: SnapshotConsistency(%d, %d)=%x, want %x", i, tv.snapshot1, tv.snapshot2, proof, want)
+		}
+		if err := v.VerifyConsistencyProof(tv.snapshot1, tv.snapshot2, roots[tv.snapshot1-1].h, roots[tv.snapshot2-1].h, proof); err != nil {
+			t.Fatalf("i=%d: failed to verify proof: %v", i, err)
+		}
+	}
+}
+
+func TestInMemoryAddLeafHash(t *testing.T) {
+	hashValue := []byte("0123456789abcdef0123456789abcdef")
+	m := NewInMemoryMerkleTree(sha256Hasher)
+	index := m.AddLeafHash(hashValue)
+	if index != 1 {
+		t.Fatalf("Expected index of 1, got %d", index)
+	}
+	gotHash, err := m.LeafHash(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(hashValue, gotHash) {
+		t.Fatalf("Added leafhash:\n%v\nGot:\n%v", hex.Dump(hashValue), hex.Dump(gotHash))
+	}
+
+	// Changing the caller's buffer mustn't change the tree.
+	root, err := m.CurrentRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte(nil), root...)
+	hashValue[0] ^= 0xff
+	if gotHash, err = m.LeafHash(index); err != nil || bytes.Equal(hashValue, gotHash) {
+		t.Fatalf("LeafHash()=%x, %v after caller changed its buffer", gotHash, err)
+	}
+	if root, err = m.CurrentRoot(); err != nil || !bytes.Equal(root, want) {
+		t.Fatalf("CurrentRoot()=%x, %v after caller changed its buffer, want %x", root, err, want)
+	}
+}
+
+// Builds random trees with both the C++ and the pure Go implementations,
+// interleaving additions with queries, and checks that they agree on
+// everything.
+func TestInMemoryMatchesCPPMerkleTree(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for _, numLeaves := range []int{1, 2, 3, 7, 8, 9, 31, 64, 100, 257} {
+		c := NewCPPMerkleTree()
+		m := NewInMemoryMerkleTree(sha256Hasher)
+		for i := 0; i < numLeaves; i++ {
+			leaf := []byte(fmt.Sprintf("leaf-%d-%d", numLeaves, r.Int()))
+			if got, want := m.AddLeaf(leaf), c.AddLeaf(leaf); got != want {
+				t.Fatalf("AddLeaf()=%d, want %d", got, want)
+			}
+			if got, want := m.LevelCount(), c.LevelCount(); got != want {
+				t.Fatalf("LevelCount()=%d, want %d", got, want)
+			}
+			// Occasionally query the tree part way through, so that the lazy
+			// update paths get exercised.
+			if r.Intn(4) == 0 {
+				size := uint64(i + 1)
+				compareTrees(t, c, m, size, uint64(r.Int63n(int64(size)))+1)
+			}
+		}
+		size := uint64(numLeaves)
+		for s := uint64(1); s <= size; s++ {
+			compareTrees(t, c, m, size, s)
+		}
+		c.DeletePeer()
+	}
+}
+
+func compareTrees(t *testing.T, c *CPPMerkleTree, m *InMemoryMerkleTree, size, snapshot uint64) {
+	got, err := m.RootAtSnapshot(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := c.RootAtSnapshot(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("size %d: RootAtSnapshot(%d)=%x, want %x", size, snapshot, got, want)
+	}
+	for leaf := uint64(1); leaf <= snapshot; leaf++ {
+		gotPath, err := m.PathToRootAtSnapshot(leaf, snapshot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantPath, err := c.PathToRootAtSnapshot(leaf, snapshot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equalProofs(gotPath, wantPath) {
+			t.Fatalf("size %d: PathToRootAtSnapshot(%d, %d)=%x, want %x", size, leaf, snapshot, gotPath, wantPath)
+		}
+	}
+	if snapshot < size {
+		gotProof, err := m.SnapshotConsistency(snapshot, size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantProof, err := c.SnapshotConsistency(snapshot, size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equalProofs(gotProof, wantProof) {
+			t.Fatalf("SnapshotConsistency(%d, %d)=%x, want %x", snapshot, size, gotProof, wantProof)
+		}
+	}
+}
+
+// equalProofs compares two proofs, treating nil and empty proofs as equal.
+func equalProofs(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
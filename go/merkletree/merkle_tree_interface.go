@@ -12,6 +12,10 @@ type MerkleTreeInterface interface {
 	// leaf index
 	AddLeaf(leaf []byte) uint64
 
+	// AddLeafHash adds |hash| directly to the tree as a leaf hash and returns
+	// the newly added leaf index
+	AddLeafHash(hash []byte) uint64
+
 	// LeafHash returns the hash of the leaf at index |leaf| or a non-nil error.
 	LeafHash(leaf uint64) ([]byte, error)
 
@@ -30,9 +34,13 @@ type FullMerkleTreeInterface interface {
 
 	// PathToCurrentRoot returns the Merkle path (or inclusion proof) from the
 	// leaf hash at index |leaf| to the current root.
-	PathToCurrentRoot(leaf uint64) ([]byte, error)
+	PathToCurrentRoot(leaf uint64) ([][]byte, error)
+
+	// PathToRootAtSnapshot returns the Merkle path (or inclusion proof) from
+	// the leaf hash at index |leaf| to the root at tree size |snapshot|.
+	PathToRootAtSnapshot(leaf, snapshot uint64) ([][]byte, error)
 
 	// SnapshotConsistency returns a consistency proof between the two tree
 	// sizes specified in |snapshot1| and |snapshot2|.
-	SnapshotConsistency(snapshot1, snapshot2 uint64) ([]byte, error)
+	SnapshotConsistency(snapshot1, snapshot2 uint64) ([][]byte, error)
 }
@@ -0,0 +1,170 @@
+// Package objecthash implements ObjectHash (https://github.com/benlaurie/objecthash)
+// for JSON values.  ObjectHash is a structural hash: two JSON documents that
+// differ only in formatting, whitespace or object key order hash to the same
+// value.
+//
+// Values are hashed following the "CommonJSON" profile, so all numbers are
+// treated as floating point.
+package objecthash
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Type tags prefixed to the data of each hashed value.
+const (
+	boolTag    = "b"
+	dictTag    = "d"
+	floatTag   = "f"
+	listTag    = "l"
+	nullTag    = "n"
+	unicodeTag = "u"
+)
+
+// maxFloatLength bounds the size of a normalized float.
+const maxFloatLength = 1000
+
+// Hash holds an ObjectHash value.
+type Hash [sha256.Size]byte
+
+func hash(tag string, data []byte) Hash {
+	h := sha256.New()
+	h.Write([]byte(tag))
+	h.Write(data)
+	var ret Hash
+	copy(ret[:], h.Sum(nil))
+	return ret
+}
+
+// CommonJSON returns the ObjectHash of the JSON document in |data|.
+func CommonJSON(data []byte) (Hash, error) {
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return Hash{}, err
+	}
+	return ObjectHash(obj)
+}
+
+// ObjectHash returns the ObjectHash of |v|, which must be a value as produced
+// by unmarshalling JSON into an interface{}: nil, bool, float64, json.Number,
+// string, []interface{} or map[string]interface{}.
+func ObjectHash(v interface{}) (Hash, error) {
+	switch v := v.(type) {
+	case nil:
+		return hash(nullTag, nil), nil
+	case bool:
+		if v {
+			return hash(boolTag, []byte("1")), nil
+		}
+		return hash(boolTag, []byte("0")), nil
+	case float64:
+		return hashFloat(v)
+	case json.Number:
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return Hash{}, err
+		}
+		return hashFloat(f)
+	case string:
+		return hash(unicodeTag, []byte(v)), nil
+	case []interface{}:
+		var buf bytes.Buffer
+		for _, e := range v {
+			h, err := ObjectHash(e)
+			if err != nil {
+				return Hash{}, err
+			}
+			buf.Write(h[:])
+		}
+		return hash(listTag, buf.Bytes()), nil
+	case map[string]interface{}:
+		return hashDict(v)
+	default:
+		return Hash{}, fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+type dictEntry struct {
+	key, value Hash
+}
+
+// byKeyHash sorts dictEntries by the hash of their keys.
+type byKeyHash []dictEntry
+
+func (e byKeyHash) Len() int           { return len(e) }
+func (e byKeyHash) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e byKeyHash) Less(i, j int) bool { return bytes.Compare(e[i].key[:], e[j].key[:]) < 0 }
+
+// hashDict hashes the concatenation of the key and value hashes of |d|,
+// ordered by key hash.
+func hashDict(d map[string]interface{}) (Hash, error) {
+	entries := make([]dictEntry, 0, len(d))
+	for k, v := range d {
+		vh, err := ObjectHash(v)
+		if err != nil {
+			return Hash{}, err
+		}
+		entries = append(entries, dictEntry{key: hash(unicodeTag, []byte(k)), value: vh})
+	}
+	sort.Sort(byKeyHash(entries))
+	var buf bytes.Buffer
+	for _, e := range entries {
+		buf.Write(e.key[:])
+		buf.Write(e.value[:])
+	}
+	return hash(dictTag, buf.Bytes()), nil
+}
+
+func hashFloat(f float64) (Hash, error) {
+	n, err := normalizeFloat(f)
+	if err != nil {
+		return Hash{}, err
+	}
+	return hash(floatTag, []byte(n)), nil
+}
+
+// normalizeFloat renders |f| as sign, binary exponent and binary mantissa,
+// e.g. "+1:011" for 1.5, with the mantissa in the range (0.5, 1].
+func normalizeFloat(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", errors.New("cannot hash NaN or infinite float")
+	}
+	if f == 0 {
+		return "+0:", nil
+	}
+	s := "+"
+	if f < 0 {
+		s = "-"
+		f = -f
+	}
+	e := 0
+	for f > 1 {
+		f /= 2
+		e++
+	}
+	for f <= 0.5 {
+		f *= 2
+		e--
+	}
+	s += strconv.Itoa(e) + ":"
+	for f != 0 {
+		if f >= 1 {
+			s += "1"
+			f--
+		} else {
+			s += "0"
+		}
+		if len(s) >= maxFloatLength {
+			return "", errors.New("normalized float too long")
+		}
+		f *= 2
+	}
+	return s, nil
+}
@@ -0,0 +1,92 @@
+package objecthash
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestCommonJSON(t *testing.T) {
+	var tests = []struct {
+		in   string
+		want string
+	}{
+		{`[]`, "acac86c0e609ca906f632b0e2dacccb2b77d22b0621f20ebece1a4835b93f6f0"},
+		{`["foo"]`, "268bc27d4974d9d576222e4cdbb8f7c6bd6791894098645a19eeca9c102d0964"},
+		{`["foo", "bar"]`, "32ae896c413cfdc79eec68be9139c86ded8b279238467c216cf2bec4d5f1e4a2"},
+		{`{}`, "18ac3e7343f016890c510e93f935261169d9e3f565436429830faf0934f4f8e4"},
+		{`{"foo": "bar"}`, "7ef5237c3027d6c58100afadf37796b3d351025cf28038280147d42fdc53b960"},
+		{`{"foo": ["bar", "baz"], "qux": ["norf"]}`, "f1a9389f27558538a064f3cc250f8686a0cebb85f1cab7f4d4dcc416ceda3c92"},
+		{`[null]`, "5fb858ed3ef4275e64c2d5c44b77534181f7722b7765288e76924ce2f9f7f7db"},
+		{`["foo", {"bar": ["baz", null, 1.0, 1.5, 0.0001, 1000.0, 2.0, -23.1234, 2.0]}]`, "783a423b094307bcb28d005bc2f026ff44204442ef3513585e7e73b66e3c2213"},
+	}
+	for _, test := range tests {
+		got, err := CommonJSON([]byte(test.in))
+		if err != nil {
+			t.Errorf("CommonJSON(%q)=nil,%v; want %s,nil", test.in, err, test.want)
+			continue
+		}
+		if hex.EncodeToString(got[:]) != test.want {
+			t.Errorf("CommonJSON(%q)=%x; want %s", test.in, got, test.want)
+		}
+	}
+}
+
+func TestCommonJSONIgnoresFormatting(t *testing.T) {
+	a, err := CommonJSON([]byte(`{"b":[1,2],"a":"x:y, z"}`))
+	if err != nil {
+		t.Fatalf("CommonJSON()=nil,%v", err)
+	}
+	b, err := CommonJSON([]byte(`{ "a": "x:y, z", "b": [ 1.0, 2e0 ] }`))
+	if err != nil {
+		t.Fatalf("CommonJSON()=nil,%v", err)
+	}
+	if a != b {
+		t.Errorf("CommonJSON() differs for equivalent documents: %x vs %x", a, b)
+	}
+}
+
+func TestObjectHash(t *testing.T) {
+	f, err := ObjectHash(1.5)
+	if err != nil {
+		t.Fatalf("ObjectHash(1.5)=nil,%v", err)
+	}
+	n, err := ObjectHash(json.Number("1.5"))
+	if err != nil {
+		t.Fatalf("ObjectHash(json.Number)=nil,%v", err)
+	}
+	if f != n {
+		t.Errorf("ObjectHash(1.5)=%x, ObjectHash(json.Number(1.5))=%x; want equal", f, n)
+	}
+	for _, v := range []interface{}{math.NaN(), math.Inf(1), 1, []string{"a"}} {
+		if got, err := ObjectHash(v); err == nil {
+			t.Errorf("ObjectHash(%v)=%x,nil; want error", v, got)
+		}
+	}
+}
+
+func TestNormalizeFloat(t *testing.T) {
+	var tests = []struct {
+		in   float64
+		want string
+	}{
+		{0, "+0:"},
+		{1, "+0:1"},
+		{-1, "-0:1"},
+		{1.5, "+1:011"},
+		{2, "+1:1"},
+		{0.5, "+-1:1"},
+		{1000, "+10:01111101"},
+	}
+	for _, test := range tests {
+		got, err := normalizeFloat(test.in)
+		if err != nil {
+			t.Errorf("normalizeFloat(%v)=nil,%v; want %q", test.in, err, test.want)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("normalizeFloat(%v)=%q; want %q", test.in, got, test.want)
+		}
+	}
+}
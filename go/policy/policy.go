@@ -0,0 +1,424 @@
+// Package policy evaluates whether a certificate and the SCTs delivered with
+// it satisfy a Certificate Transparency policy, such as those enforced by
+// browsers.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/loglist"
+	"github.com/google/certificate-transparency/go/x509"
+)
+
+// LogState is the state of a log in a log list, which determines whether
+// SCTs it issues count towards compliance.
+type LogState int
+
+// LogState values, following the life cycle of a log.
+const (
+	PendingLogState LogState = iota
+	QualifiedLogState
+	UsableLogState
+	ReadOnlyLogState
+	RetiredLogState
+	RejectedLogState
+)
+
+func (s LogState) String() string {
+	switch s {
+	case PendingLogState:
+		return "pending"
+	case QualifiedLogState:
+		return "qualified"
+	case UsableLogState:
+		return "usable"
+	case ReadOnlyLogState:
+		return "read-only"
+	case RetiredLogState:
+		return "retired"
+	case RejectedLogState:
+		return "rejected"
+	default:
+		return fmt.Sprintf("LogState(%d)", int(s))
+	}
+}
+
+// LogStateChange records that a log entered State at time Since.
+type LogStateChange struct {
+	State LogState
+	Since time.Time
+}
+
+// Log describes a CT log as far as the policy is concerned.
+type Log struct {
+	Description string
+	ID          ct.SHA256Hash
+	// Operators identifies the organisations which run the log. Two logs
+	// only count as independent if they have no operator in common; logs
+	// with no Operators are treated as sharing a single unknown operator.
+	Operators []string
+	Verifier  ct.SignatureVerifier
+	// States is the log's state history, in chronological order. The log is
+	// pending before its first state change.
+	States []LogStateChange
+}
+
+// StateAt returns the state the log was in at time |t|.
+func (l *Log) StateAt(t time.Time) LogState {
+	state := PendingLogState
+	for _, c := range l.States {
+		if c.Since.After(t) {
+			break
+		}
+		state = c.State
+	}
+	return state
+}
+
+// LogsFromLogList returns a Log for each log in |ll|. Logs which have been
+// disqualified are treated as having been retired at the time of their
+// disqualification; all others as having always been usable. |opts| are used
+// to create the logs' SignatureVerifiers. Logs whose keys can't be used are
+// skipped; an error is only returned if no log in |ll| is usable.
+func LogsFromLogList(ll *loglist.LogList, opts ...ct.VerifierOption) ([]*Log, error) {
+	var logs []*Log
+	var lastErr error
+	for i := range ll.Logs {
+		l := &ll.Logs[i]
+		v, err := l.SignatureVerifier(opts...)
+		if err != nil {
+			log.Printf("Skipping log: %v", err)
+			lastErr = err
+			continue
+		}
+		pl := &Log{
+			Description: l.Description,
+			ID:          l.LogID(),
+			Operators:   ll.OperatorNames(l),
+			Verifier:    *v,
+			States:      []LogStateChange{{State: UsableLogState}},
+		}
+		if l.Disqualified() {
+			pl.States = append(pl.States, LogStateChange{State: RetiredLogState, Since: l.DisqualifiedTime()})
+		}
+		logs = append(logs, pl)
+	}
+	if len(logs) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return logs, nil
+}
+
+// LifetimeRequirement gives the number of SCTs needed for certificates whose
+// lifetime is at most MaxLifetime; a zero MaxLifetime means no limit.
+type LifetimeRequirement struct {
+	MaxLifetime time.Duration
+	SCTs        int
+}
+
+// Policy defines a CT compliance policy.
+// A certificate complies if either its embedded SCTs, or the SCTs delivered
+// with it in the TLS extension or OCSP response, satisfy the policy.
+type Policy struct {
+	Name string
+	// EmbeddedSCTs gives the number of embedded SCTs required by certificate
+	// lifetime, sorted by increasing MaxLifetime.
+	EmbeddedSCTs []LifetimeRequirement
+	// NonEmbeddedSCTs is the number of SCTs required from the TLS extension
+	// and OCSP response together.
+	NonEmbeddedSCTs int
+	// DistinctOperators is the number of logs, with no operator in common,
+	// which the counted SCTs must come from.
+	DistinctOperators int
+	// AcceptedStates are the states a log must have been in at the time of
+	// an SCT for that SCT to count.
+	AcceptedStates []LogState
+}
+
+// ChromePolicy returns a policy modelled on Chrome's CT policy.
+func ChromePolicy() Policy {
+	return Policy{
+		Name: "Chrome",
+		EmbeddedSCTs: []LifetimeRequirement{
+			{MaxLifetime: 180 * 24 * time.Hour, SCTs: 2},
+			{SCTs: 3},
+		},
+		NonEmbeddedSCTs:   2,
+		DistinctOperators: 2,
+		AcceptedStates:    []LogState{QualifiedLogState, UsableLogState, ReadOnlyLogState},
+	}
+}
+
+// requiredEmbeddedSCTs returns the number of embedded SCTs needed for a
+// certificate with the given |lifetime|.
+func (p *Policy) requiredEmbeddedSCTs(lifetime time.Duration) int {
+	for _, r := range p.EmbeddedSCTs {
+		if r.MaxLifetime == 0 || lifetime <= r.MaxLifetime {
+			return r.SCTs
+		}
+	}
+	return -1
+}
+
+func (p *Policy) accepts(state LogState) bool {
+	for _, s := range p.AcceptedStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// SCTSource identifies the path by which an SCT was delivered.
+type SCTSource int
+
+// SCTSource values.
+const (
+	EmbeddedSCTSource SCTSource = iota
+	TLSExtensionSCTSource
+	OCSPResponseSCTSource
+)
+
+func (s SCTSource) String() string {
+	switch s {
+	case EmbeddedSCTSource:
+		return "embedded"
+	case TLSExtensionSCTSource:
+		return "TLS extension"
+	case OCSPResponseSCTSource:
+		return "OCSP response"
+	default:
+		return fmt.Sprintf("SCTSource(%d)", int(s))
+	}
+}
+
+// Input holds a certificate and the SCTs delivered with it.
+type Input struct {
+	Cert *x509.Certificate
+	// Issuer is the certificate which issued Cert. It is needed to verify
+	// embedded SCTs, and may be nil if Cert has none.
+	Issuer *x509.Certificate
+	// TLSSCTs and OCSPSCTs are the SCTs from the TLS extension and stapled
+	// OCSP response; see ct.DeserializeTLSSCTList and ct.DeserializeSCTList.
+	TLSSCTs  []ct.SignedCertificateTimestamp
+	OCSPSCTs []ct.SignedCertificateTimestamp
+}
+
+// LogStateError is the reason an SCT doesn't count when the log which issued
+// it was in a state the policy doesn't accept at the time of the SCT.
+type LogStateError struct {
+	LogID ct.SHA256Hash
+	State LogState
+}
+
+func (e LogStateError) Error() string {
+	return fmt.Sprintf("log %s was %v at SCT time", e.LogID.Base64String(), e.State)
+}
+
+// SCTResult holds the outcome of evaluating a single SCT.
+type SCTResult struct {
+	SCT    ct.SignedCertificateTimestamp
+	Source SCTSource
+	// Log is the log which issued the SCT, or nil if it isn't known.
+	Log *Log
+	// Err is nil if the SCT counts towards compliance, and the reason it
+	// doesn't otherwise: a ct.UnknownLogError, a LogStateError, or a
+	// signature or timestamp problem.
+	Err error
+}
+
+// Verdict is the outcome of evaluating a certificate against a policy.
+type Verdict struct {
+	Compliant bool
+	// Reasons explains the verdict, one entry per delivery path considered.
+	Reasons []string
+	SCTs    []SCTResult
+}
+
+// Evaluator checks certificates against a Policy using a set of known logs.
+type Evaluator struct {
+	policy Policy
+	logs   map[ct.SHA256Hash]*Log
+}
+
+// NewEvaluator returns an Evaluator which checks certificates against |policy|
+// using |logs|.
+func NewEvaluator(policy Policy, logs []*Log) (*Evaluator, error) {
+	if len(policy.EmbeddedSCTs) == 0 {
+		return nil, errors.New("policy has no embedded SCT requirements")
+	}
+	for i := 1; i < len(policy.EmbeddedSCTs); i++ {
+		if !lifetimeLess(policy.EmbeddedSCTs[i-1].MaxLifetime, policy.EmbeddedSCTs[i].MaxLifetime) {
+			return nil, errors.New("policy's embedded SCT requirements are not sorted by lifetime")
+		}
+	}
+	e := &Evaluator{policy: policy, logs: make(map[ct.SHA256Hash]*Log)}
+	for _, l := range logs {
+		if _, ok := e.logs[l.ID]; ok {
+			return nil, fmt.Errorf("duplicate log ID %s", l.ID.Base64String())
+		}
+		e.logs[l.ID] = l
+	}
+	return e, nil
+}
+
+// lifetimeLess orders MaxLifetimes, with zero (no limit) last.
+func lifetimeLess(a, b time.Duration) bool {
+	return a != 0 && (b == 0 || a < b)
+}
+
+// Evaluate checks whether the certificate and SCTs in |in| comply with the
+// Evaluator's policy at time |now|.
+func (e *Evaluator) Evaluate(in Input, now time.Time) (*Verdict, error) {
+	if in.Cert == nil {
+		return nil, errors.New("no certificate")
+	}
+	var v Verdict
+
+	embedded, embeddedErr := ct.EmbeddedSCTs(in.Cert)
+	if embeddedErr == nil {
+		var err error
+		var entry *ct.LogEntry
+		if in.Issuer == nil {
+			err = errors.New("no issuer to verify embedded SCTs against")
+		} else {
+			entry, err = ct.PrecertLogEntryFromFinalCert(in.Cert, in.Issuer)
+		}
+		for _, sct := range embedded {
+			r := SCTResult{SCT: sct, Source: EmbeddedSCTSource, Err: err}
+			if err == nil {
+				e.check(&r, *entry, now)
+			}
+			v.SCTs = append(v.SCTs, r)
+		}
+	}
+	x509Entry := ct.LogEntry{Leaf: *ct.CreateX509MerkleTreeLeaf(in.Cert.Raw, 0)}
+	for _, s := range []struct {
+		source SCTSource
+		scts   []ct.SignedCertificateTimestamp
+	}{
+		{TLSExtensionSCTSource, in.TLSSCTs},
+		{OCSPResponseSCTSource, in.OCSPSCTs},
+	} {
+		for _, sct := range s.scts {
+			r := SCTResult{SCT: sct, Source: s.source}
+			e.check(&r, x509Entry, now)
+			v.SCTs = append(v.SCTs, r)
+		}
+	}
+
+	lifetime := in.Cert.NotAfter.Sub(in.Cert.NotBefore)
+	embeddedOK, reason := false, fmt.Sprintf("embedded SCTs: %v", embeddedErr)
+	if embeddedErr == nil {
+		embeddedOK, reason = e.satisfied(v.SCTs, "embedded SCTs", e.policy.requiredEmbeddedSCTs(lifetime), func(s SCTSource) bool {
+			return s == EmbeddedSCTSource
+		})
+	}
+	v.Reasons = append(v.Reasons, reason)
+	nonEmbeddedOK, reason := e.satisfied(v.SCTs, "TLS extension and OCSP SCTs", e.policy.NonEmbeddedSCTs, func(s SCTSource) bool {
+		return s != EmbeddedSCTSource
+	})
+	v.Reasons = append(v.Reasons, reason)
+	v.Compliant = embeddedOK || nonEmbeddedOK
+	return &v, nil
+}
+
+// check sets r.Log, and r.Err if the SCT in |r| doesn't count, verifying its
+// signature over |entry|.
+func (e *Evaluator) check(r *SCTResult, entry ct.LogEntry, now time.Time) {
+	sct := r.SCT
+	r.Log = e.logs[sct.LogID]
+	if r.Log == nil {
+		r.Err = ct.UnknownLogError{LogID: sct.LogID}
+		return
+	}
+	ts := timeFromMS(sct.Timestamp)
+	if ts.After(now) {
+		r.Err = fmt.Errorf("SCT timestamp %v is in the future", ts)
+		return
+	}
+	if state := r.Log.StateAt(ts); !e.policy.accepts(state) {
+		r.Err = LogStateError{LogID: sct.LogID, State: state}
+		return
+	}
+	entry.Leaf.TimestampedEntry.Timestamp = sct.Timestamp
+	entry.Leaf.TimestampedEntry.Extensions = sct.Extensions
+	if err := r.Log.Verifier.VerifySCTSignature(sct, entry); err != nil {
+		r.Err = err
+	}
+}
+
+// satisfied reports whether the SCTs in |results| from the sources selected
+// by |include| meet the policy's requirements, given that |required| SCTs are
+// needed, along with a reason describing the result.
+func (e *Evaluator) satisfied(results []SCTResult, what string, required int, include func(SCTSource) bool) (bool, string) {
+	switch {
+	case required < 0:
+		return false, fmt.Sprintf("%s: certificate lifetime exceeds policy maximum", what)
+	case required == 0:
+		return true, fmt.Sprintf("%s: none required, policy satisfied", what)
+	}
+	seen := make(map[ct.SHA256Hash]bool)
+	var logs []*Log
+	for _, r := range results {
+		if include(r.Source) && r.Err == nil && !seen[r.SCT.LogID] {
+			seen[r.SCT.LogID] = true
+			logs = append(logs, r.Log)
+		}
+	}
+	operators := independentLogs(logs)
+	switch {
+	case len(logs) == 0:
+		return false, fmt.Sprintf("%s: none valid", what)
+	case len(logs) < required:
+		return false, fmt.Sprintf("%s: %d valid from distinct logs, %d required", what, len(logs), required)
+	case operators < e.policy.DistinctOperators:
+		return false, fmt.Sprintf("%s: %d distinct log operators, %d required", what, operators, e.policy.DistinctOperators)
+	}
+	return true, fmt.Sprintf("%s: %d valid from %d distinct log operators, policy satisfied", what, len(logs), operators)
+}
+
+// independentLogs returns the size of the largest subset of |logs| in which
+// no two logs have an operator in common.
+func independentLogs(logs []*Log) int {
+	best := 0
+	used := make(map[string]bool)
+	var search func(i, n int)
+	search = func(i, n int) {
+		if n+len(logs)-i <= best {
+			return
+		}
+		if i == len(logs) {
+			best = n
+			return
+		}
+		ops := logs[i].Operators
+		if len(ops) == 0 {
+			ops = []string{""}
+		}
+		free := true
+		for _, op := range ops {
+			free = free && !used[op]
+		}
+		if free {
+			for _, op := range ops {
+				used[op] = true
+			}
+			search(i+1, n+1)
+			for _, op := range ops {
+				delete(used, op)
+			}
+		}
+		search(i+1, n)
+	}
+	search(0, 0)
+	return best
+}
+
+func timeFromMS(ms uint64) time.Time {
+	return time.Unix(int64(ms/1000), int64(ms%1000)*int64(time.Millisecond))
+}
@@ -0,0 +1,401 @@
+package policy
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	stdx509 "crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/loglist"
+	"github.com/google/certificate-transparency/go/testonly"
+	"github.com/google/certificate-transparency/go/x509"
+)
+
+func readTestCert(t *testing.T, name string) *x509.Certificate {
+	cert, err := x509.ParseCertificate(testonly.ReadPEM(t, name))
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", name, err)
+	}
+	return cert
+}
+
+// testLog returns a Log for the test log whose public key is in
+// ct-server-key-public.pem, which issued the SCTs in the test data.
+func testLog(t *testing.T, operators ...string) *Log {
+	key, err := stdx509.ParsePKIXPublicKey(testonly.ReadPEM(t, "ct-server-key-public.pem"))
+	if err != nil {
+		t.Fatalf("failed to parse log key: %v", err)
+	}
+	return newLog(t, "test log", key, operators...)
+}
+
+func newLog(t *testing.T, description string, key interface{}, operators ...string) *Log {
+	der, err := stdx509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey()=%v", err)
+	}
+	v, err := ct.NewSignatureVerifier(key)
+	if err != nil {
+		t.Fatalf("NewSignatureVerifier()=%v", err)
+	}
+	return &Log{
+		Description: description,
+		ID:          sha256.Sum256(der),
+		Operators:   operators,
+		Verifier:    *v,
+		States:      []LogStateChange{{State: UsableLogState}},
+	}
+}
+
+// fakeLog is a log with a freshly generated key, which issues SCTs for
+// certificates on demand.
+type fakeLog struct {
+	*Log
+	signer *ct.Signer
+}
+
+func newFakeLog(t *testing.T, description string, operators ...string) fakeLog {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey()=%v", err)
+	}
+	signer, err := ct.NewSigner(key)
+	if err != nil {
+		t.Fatalf("NewSigner()=%v", err)
+	}
+	return fakeLog{Log: newLog(t, description, key.Public(), operators...), signer: signer}
+}
+
+// sct returns an SCT from the log for |cert| delivered by TLS or OCSP, issued
+// at |timestamp|.
+func (l fakeLog) sct(t *testing.T, cert *x509.Certificate, timestamp time.Time) ct.SignedCertificateTimestamp {
+	ms := uint64(timestamp.UnixNano() / int64(time.Millisecond))
+	sct, err := l.signer.CreateSCT(ct.LogEntry{Leaf: *ct.CreateX509MerkleTreeLeaf(cert.Raw, ms)})
+	if err != nil {
+		t.Fatalf("CreateSCT()=%v", err)
+	}
+	return *sct
+}
+
+func readTestSCT(t *testing.T, name string) ct.SignedCertificateTimestamp {
+	sct, err := ct.DeserializeSCT(bytes.NewReader(testonly.ReadFile(t, name)))
+	if err != nil {
+		t.Fatalf("DeserializeSCT()=%v", err)
+	}
+	return *sct
+}
+
+func mustEvaluator(t *testing.T, p Policy, logs ...*Log) *Evaluator {
+	e, err := NewEvaluator(p, logs)
+	if err != nil {
+		t.Fatalf("NewEvaluator()=%v", err)
+	}
+	return e
+}
+
+func TestEvaluateNonEmbedded(t *testing.T) {
+	cert := readTestCert(t, "test-cert.pem")
+	realSCT := readTestSCT(t, "test-cert.proof")
+	now := time.Now()
+	a := testLog(t, "Operator A")
+	b := newFakeLog(t, "log B", "Operator B")
+	c := newFakeLog(t, "log C", "Operator A")
+	d := newFakeLog(t, "log D", "Operator C", "Operator A")
+	hourAgo := now.Add(-time.Hour)
+
+	for _, test := range []struct {
+		desc      string
+		tls, ocsp []ct.SignedCertificateTimestamp
+		compliant bool
+		reason    string
+	}{
+		{
+			desc:      "TLS from two operators",
+			tls:       []ct.SignedCertificateTimestamp{realSCT, b.sct(t, cert, hourAgo)},
+			compliant: true,
+			reason:    "2 valid from 2 distinct log operators",
+		},
+		{
+			desc:      "TLS and OCSP from two operators",
+			tls:       []ct.SignedCertificateTimestamp{realSCT},
+			ocsp:      []ct.SignedCertificateTimestamp{b.sct(t, cert, hourAgo)},
+			compliant: true,
+		},
+		{
+			desc:   "one operator",
+			tls:    []ct.SignedCertificateTimestamp{realSCT, c.sct(t, cert, hourAgo)},
+			reason: "1 distinct log operators, 2 required",
+		},
+		{
+			desc:   "operators in common",
+			tls:    []ct.SignedCertificateTimestamp{realSCT, d.sct(t, cert, hourAgo)},
+			reason: "1 distinct log operators, 2 required",
+		},
+		{
+			desc:      "no operators in common",
+			tls:       []ct.SignedCertificateTimestamp{b.sct(t, cert, hourAgo), d.sct(t, cert, hourAgo)},
+			compliant: true,
+			reason:    "2 valid from 2 distinct log operators",
+		},
+		{
+			desc:   "same log twice",
+			tls:    []ct.SignedCertificateTimestamp{realSCT},
+			ocsp:   []ct.SignedCertificateTimestamp{realSCT},
+			reason: "1 valid from distinct logs, 2 required",
+		},
+		{
+			desc:   "none",
+			reason: "TLS extension and OCSP SCTs: none valid",
+		},
+	} {
+		e := mustEvaluator(t, ChromePolicy(), a, b.Log, c.Log, d.Log)
+		v, err := e.Evaluate(Input{Cert: cert, TLSSCTs: test.tls, OCSPSCTs: test.ocsp}, now)
+		if err != nil {
+			t.Errorf("%s: Evaluate()=%v", test.desc, err)
+			continue
+		}
+		if v.Compliant != test.compliant {
+			t.Errorf("%s: Evaluate().Compliant=%v, want %v (reasons: %q)", test.desc, v.Compliant, test.compliant, v.Reasons)
+		}
+		if got := strings.Join(v.Reasons, "; "); !strings.Contains(got, test.reason) {
+			t.Errorf("%s: Evaluate().Reasons=%q, want to contain %q", test.desc, got, test.reason)
+		}
+		if len(v.SCTs) != len(test.tls)+len(test.ocsp) {
+			t.Errorf("%s: Evaluate() has %d SCT results, want %d", test.desc, len(v.SCTs), len(test.tls)+len(test.ocsp))
+		}
+		for _, r := range v.SCTs {
+			if r.Err != nil {
+				t.Errorf("%s: SCT from %v has error %v", test.desc, r.Source, r.Err)
+			}
+		}
+	}
+}
+
+func TestEvaluateNothingRequired(t *testing.T) {
+	p := ChromePolicy()
+	p.NonEmbeddedSCTs = 0
+	v, err := mustEvaluator(t, p).Evaluate(Input{Cert: readTestCert(t, "test-cert.pem")}, time.Now())
+	if err != nil {
+		t.Fatalf("Evaluate()=%v", err)
+	}
+	if want := "TLS extension and OCSP SCTs: none required"; !v.Compliant || !strings.Contains(strings.Join(v.Reasons, "; "), want) {
+		t.Errorf("Evaluate()=%+v, want compliant with reason %q", v, want)
+	}
+}
+
+func TestIndependentLogs(t *testing.T) {
+	for _, test := range []struct {
+		operators [][]string
+		want      int
+	}{
+		{nil, 0},
+		{[][]string{{"A"}, {"B"}, {"C"}}, 3},
+		{[][]string{{"A"}, {"A"}, {"B"}}, 2},
+		{[][]string{{"A", "B"}, {"B"}, {"A"}}, 2},
+		{[][]string{{"A", "B"}, {"B", "C"}, {"C", "A"}}, 1},
+		{[][]string{{"A", "B"}, {"C"}, {"B", "C"}}, 2},
+		{[][]string{{}, {}, {"A"}}, 2},
+	} {
+		var logs []*Log
+		for _, ops := range test.operators {
+			logs = append(logs, &Log{Operators: ops})
+		}
+		if got := independentLogs(logs); got != test.want {
+			t.Errorf("independentLogs(%v)=%d, want %d", test.operators, got, test.want)
+		}
+	}
+}
+
+func TestEvaluateSCTErrors(t *testing.T) {
+	cert := readTestCert(t, "test-cert.pem")
+	now := time.Now()
+	sctTime := now.Add(-time.Hour)
+	good := newFakeLog(t, "good log", "Operator A")
+
+	unknown := newFakeLog(t, "unknown log", "Operator B")
+	if _, err := evaluateOne(t, good.Log, unknown.sct(t, cert, sctTime), now); err == nil {
+		t.Error("SCT from unknown log counted")
+	} else if _, ok := err.(ct.UnknownLogError); !ok {
+		t.Errorf("SCT from unknown log has error %T (%v), want UnknownLogError", err, err)
+	}
+
+	for _, test := range []struct {
+		desc   string
+		states []LogStateChange
+		want   LogState
+	}{
+		{"retired before SCT", []LogStateChange{{UsableLogState, time.Time{}}, {RetiredLogState, sctTime.Add(-time.Minute)}}, RetiredLogState},
+		{"pending at SCT time", []LogStateChange{{QualifiedLogState, sctTime.Add(time.Minute)}}, PendingLogState},
+		{"rejected", []LogStateChange{{RejectedLogState, time.Time{}}}, RejectedLogState},
+	} {
+		l := newFakeLog(t, test.desc, "Operator B")
+		l.States = test.states
+		_, err := evaluateOne(t, l.Log, l.sct(t, cert, sctTime), now)
+		if lse, ok := err.(LogStateError); !ok || lse.State != test.want || lse.LogID != l.ID {
+			t.Errorf("%s: SCT has error %v, want LogStateError for state %v", test.desc, err, test.want)
+		}
+	}
+
+	retiredLater := newFakeLog(t, "retired later", "Operator B")
+	retiredLater.States = append(retiredLater.States, LogStateChange{RetiredLogState, sctTime.Add(time.Minute)})
+	if _, err := evaluateOne(t, retiredLater.Log, retiredLater.sct(t, cert, sctTime), now); err != nil {
+		t.Errorf("SCT issued before log was retired has error %v, want nil", err)
+	}
+
+	if _, err := evaluateOne(t, good.Log, good.sct(t, cert, now.Add(time.Hour)), now); err == nil {
+		t.Error("SCT from the future counted")
+	}
+
+	sct := good.sct(t, cert, sctTime)
+	sct.Signature.Signature[len(sct.Signature.Signature)-1] ^= 1
+	if _, err := evaluateOne(t, good.Log, sct, now); err == nil {
+		t.Error("SCT with bad signature counted")
+	}
+
+	other := readTestCert(t, "ca-cert.pem")
+	if _, err := evaluateOne(t, good.Log, good.sct(t, other, sctTime), now); err == nil {
+		t.Error("SCT for another certificate counted")
+	}
+}
+
+// evaluateOne evaluates test-cert.pem with |sct| delivered in the TLS
+// extension, using a policy needing only that SCT, and returns the SCT's
+// result.
+func evaluateOne(t *testing.T, l *Log, sct ct.SignedCertificateTimestamp, now time.Time) (*Verdict, error) {
+	p := ChromePolicy()
+	p.NonEmbeddedSCTs = 1
+	p.DistinctOperators = 1
+	v, err := mustEvaluator(t, p, l).Evaluate(Input{Cert: readTestCert(t, "test-cert.pem"), TLSSCTs: []ct.SignedCertificateTimestamp{sct}}, now)
+	if err != nil {
+		t.Fatalf("Evaluate()=%v", err)
+	}
+	if len(v.SCTs) != 1 {
+		t.Fatalf("Evaluate() has %d SCT results, want 1", len(v.SCTs))
+	}
+	if got, want := v.Compliant, v.SCTs[0].Err == nil; got != want {
+		t.Errorf("Evaluate().Compliant=%v, want %v", got, want)
+	}
+	return v, v.SCTs[0].Err
+}
+
+func TestEvaluateEmbedded(t *testing.T) {
+	cert := readTestCert(t, "test-embedded-cert.pem")
+	issuer := readTestCert(t, "ca-cert.pem")
+	lenient := ChromePolicy()
+	lenient.EmbeddedSCTs = []LifetimeRequirement{{SCTs: 1}}
+	lenient.DistinctOperators = 1
+	now := time.Now()
+
+	for _, test := range []struct {
+		desc      string
+		policy    Policy
+		issuer    *x509.Certificate
+		compliant bool
+		reason    string
+	}{
+		{"lenient", lenient, issuer, true, "embedded SCTs: 1 valid from 1 distinct log operators"},
+		{"chrome", ChromePolicy(), issuer, false, "embedded SCTs: 1 valid from distinct logs, 3 required"},
+		{"no issuer", lenient, nil, false, "embedded SCTs: none valid"},
+		{"wrong issuer", lenient, readTestCert(t, "intermediate-cert.pem"), false, "embedded SCTs: none valid"},
+	} {
+		e := mustEvaluator(t, test.policy, testLog(t, "Operator A"))
+		v, err := e.Evaluate(Input{Cert: cert, Issuer: test.issuer}, now)
+		if err != nil {
+			t.Errorf("%s: Evaluate()=%v", test.desc, err)
+			continue
+		}
+		if v.Compliant != test.compliant {
+			t.Errorf("%s: Evaluate().Compliant=%v, want %v (reasons: %q)", test.desc, v.Compliant, test.compliant, v.Reasons)
+		}
+		if got := strings.Join(v.Reasons, "; "); !strings.Contains(got, test.reason) {
+			t.Errorf("%s: Evaluate().Reasons=%q, want to contain %q", test.desc, got, test.reason)
+		}
+		if len(v.SCTs) != 1 || v.SCTs[0].Source != EmbeddedSCTSource {
+			t.Errorf("%s: Evaluate() has SCT results %+v, want one embedded", test.desc, v.SCTs)
+		}
+	}
+
+	v, err := mustEvaluator(t, lenient).Evaluate(Input{Cert: readTestCert(t, "test-cert.pem")}, now)
+	if err != nil {
+		t.Fatalf("Evaluate()=%v", err)
+	}
+	if want := "embedded SCTs: certificate has no embedded SCT list"; v.Reasons[0] != want {
+		t.Errorf("Evaluate() of certificate without SCTs has reason %q, want %q", v.Reasons[0], want)
+	}
+}
+
+func TestRequiredEmbeddedSCTs(t *testing.T) {
+	p := Policy{EmbeddedSCTs: []LifetimeRequirement{
+		{MaxLifetime: 15 * 30 * 24 * time.Hour, SCTs: 2},
+		{MaxLifetime: 27 * 30 * 24 * time.Hour, SCTs: 3},
+		{MaxLifetime: 39 * 30 * 24 * time.Hour, SCTs: 4},
+	}}
+	for _, test := range []struct {
+		months int
+		want   int
+	}{
+		{1, 2}, {15, 2}, {16, 3}, {27, 3}, {39, 4}, {40, -1},
+	} {
+		if got := p.requiredEmbeddedSCTs(time.Duration(test.months) * 30 * 24 * time.Hour); got != test.want {
+			t.Errorf("requiredEmbeddedSCTs(%d months)=%d, want %d", test.months, got, test.want)
+		}
+	}
+}
+
+func TestNewEvaluatorErrors(t *testing.T) {
+	l := testLog(t, "Operator A")
+	for _, test := range []struct {
+		desc   string
+		policy Policy
+		logs   []*Log
+	}{
+		{"no embedded requirements", Policy{}, nil},
+		{"unsorted", Policy{EmbeddedSCTs: []LifetimeRequirement{{SCTs: 3}, {MaxLifetime: time.Hour, SCTs: 2}}}, nil},
+		{"duplicate log", ChromePolicy(), []*Log{l, l}},
+	} {
+		if _, err := NewEvaluator(test.policy, test.logs); err == nil {
+			t.Errorf("%s: NewEvaluator() succeeded, want error", test.desc)
+		}
+	}
+	if _, err := NewEvaluator(ChromePolicy(), []*Log{l}); err != nil {
+		t.Errorf("NewEvaluator(ChromePolicy())=%v", err)
+	}
+}
+
+func TestLogsFromLogList(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(testonly.ReadPEM(t, "ct-server-key-public.pem"))
+	ll, err := loglist.NewFromJSON([]byte(fmt.Sprintf(`{
+		"operators": [{"id": 0, "name": "Operator A"}, {"id": 1, "name": "Operator B"}],
+		"logs": [{"description": "Test log", "key": %q, "url": "ct.example.com", "maximum_merge_delay": 86400, "operated_by": [1], "disqualified_at": 1500000000}]
+	}`, key)))
+	if err != nil {
+		t.Fatalf("NewFromJSON()=%v", err)
+	}
+	logs, err := LogsFromLogList(ll)
+	if err != nil {
+		t.Fatalf("LogsFromLogList()=%v", err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("LogsFromLogList() returned %d logs, want 1", len(logs))
+	}
+	l := logs[0]
+	if l.ID != ll.Logs[0].LogID() || !reflect.DeepEqual(l.Operators, []string{"Operator B"}) || l.Description != "Test log" {
+		t.Errorf("LogsFromLogList() returned %+v", l)
+	}
+	disqualified := time.Unix(1500000000, 0)
+	if got := l.StateAt(disqualified.Add(-time.Second)); got != UsableLogState {
+		t.Errorf("StateAt(before disqualification)=%v, want %v", got, UsableLogState)
+	}
+	if got := l.StateAt(disqualified); got != RetiredLogState {
+		t.Errorf("StateAt(disqualification)=%v, want %v", got, RetiredLogState)
+	}
+}
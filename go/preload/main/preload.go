@@ -4,12 +4,15 @@ import (
 	"compress/zlib"
 	"encoding/gob"
 	"flag"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
 	"regexp"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,7 +20,9 @@ import (
 	"github.com/google/certificate-transparency/go/client"
 	"github.com/google/certificate-transparency/go/preload"
 	"github.com/google/certificate-transparency/go/scanner"
+	"github.com/google/certificate-transparency/go/submission"
 	httpclient "github.com/mreiferson/go-httpclient"
+	"golang.org/x/net/context"
 )
 
 const (
@@ -26,7 +31,9 @@ const (
 )
 
 var sourceLogUri = flag.String("source_log_uri", "http://ct.googleapis.com/aviator", "CT log base URI to fetch entries from")
-var targetLogUri = flag.String("target_log_uri", "http://example.com/ct", "CT log base URI to add entries to")
+var targetLogUri = flag.String("target_log_uri", "http://example.com/ct", "Comma separated list of CT log base URIs to add entries to")
+var minTargetSCTs = flag.Int("min_target_scts", 1, "Number of target logs which must issue an SCT for an entry to be considered added")
+var submitTimeout = flag.Duration("submit_timeout", 0, "Maximum time to spend submitting an entry to each target log, or 0 for no limit")
 var batchSize = flag.Int("batch_size", 1000, "Max number of entries to request at per call to get-entries")
 var numWorkers = flag.Int("num_workers", 2, "Number of concurrent matchers")
 var parallelFetch = flag.Int("parallel_fetch", 2, "Number of concurrent GetEntries fetches")
@@ -59,11 +66,32 @@ func recordSct(addedCerts chan<- *preload.AddedCert, certDer ct.ASN1Cert, sct *c
 	addedCerts <- &addedCert
 }
 
-func recordFailure(addedCerts chan<- *preload.AddedCert, certDer ct.ASN1Cert, addError error) {
+func errorMessage(err error) string {
+	if rspErr, ok := err.(client.RspError); ok && rspErr.StatusCode != http.StatusOK {
+		// The log rejected the chain, so record the reason it gave.
+		return fmt.Sprintf("%v: %s", rspErr, rspErr.Body)
+	}
+	return err.Error()
+}
+
+func recordFailure(addedCerts chan<- *preload.AddedCert, certDer ct.ASN1Cert, addError error, logErrors map[string]error) {
+	msg := errorMessage(addError)
+	if len(logErrors) > 0 {
+		var names []string
+		for name := range logErrors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var msgs []string
+		for _, name := range names {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", name, errorMessage(logErrors[name])))
+		}
+		msg = fmt.Sprintf("%s (%s)", msg, strings.Join(msgs, "; "))
+	}
 	addedCert := preload.AddedCert{
 		CertDER:      certDer,
 		AddedOk:      false,
-		ErrorMessage: addError.Error(),
+		ErrorMessage: msg,
 	}
 	addedCerts <- &addedCert
 }
@@ -91,39 +119,43 @@ func sctWriterJob(addedCerts <-chan *preload.AddedCert, sctWriter io.Writer, wg
 	wg.Done()
 }
 
-func certSubmitterJob(addedCerts chan<- *preload.AddedCert, log_client *client.LogClient, certs <-chan *ct.LogEntry,
+func certSubmitterJob(addedCerts chan<- *preload.AddedCert, submitter *submission.Submitter, certs <-chan *ct.LogEntry,
 	wg *sync.WaitGroup) {
 	for c := range certs {
 		chain := make([]ct.ASN1Cert, len(c.Chain)+1)
 		chain[0] = c.X509Cert.Raw
 		copy(chain[1:], c.Chain)
-		sct, err := log_client.AddChain(chain)
+		res, err := submitter.SubmitChain(context.Background(), chain)
+		// Record the SCTs which were issued even if there weren't enough of
+		// them, since the chain is in those logs regardless.
+		for _, s := range res.SCTs {
+			recordSct(addedCerts, chain[0], s.SCT)
+			if !*quiet {
+				log.Printf("Added chain for CN '%s' to %s, SCT: %s\n", c.X509Cert.Subject.CommonName, s.Log.Name, s.SCT)
+			}
+		}
 		if err != nil {
 			log.Printf("failed to add chain with CN %s: %v\n", c.X509Cert.Subject.CommonName, err)
-			recordFailure(addedCerts, chain[0], err)
-			continue
-		}
-		recordSct(addedCerts, chain[0], sct)
-		if !*quiet {
-			log.Printf("Added chain for CN '%s', SCT: %s\n", c.X509Cert.Subject.CommonName, sct)
+			recordFailure(addedCerts, chain[0], err, res.Errors)
 		}
 	}
 	wg.Done()
 }
 
-func precertSubmitterJob(addedCerts chan<- *preload.AddedCert, log_client *client.LogClient,
+func precertSubmitterJob(addedCerts chan<- *preload.AddedCert, submitter *submission.Submitter,
 	precerts <-chan *ct.LogEntry,
 	wg *sync.WaitGroup) {
 	for c := range precerts {
-		sct, err := log_client.AddPreChain(c.Chain)
+		res, err := submitter.SubmitPreChain(context.Background(), c.Chain)
+		for _, s := range res.SCTs {
+			recordSct(addedCerts, c.Chain[0], s.SCT)
+			if !*quiet {
+				log.Printf("Added precert chain for CN '%s' to %s, SCT: %s\n", c.Precert.TBSCertificate.Subject.CommonName, s.Log.Name, s.SCT)
+			}
+		}
 		if err != nil {
 			log.Printf("failed to add pre-chain with CN %s: %v", c.Precert.TBSCertificate.Subject.CommonName, err)
-			recordFailure(addedCerts, c.Chain[0], err)
-			continue
-		}
-		recordSct(addedCerts, c.Chain[0], sct)
-		if !*quiet {
-			log.Printf("Added precert chain for CN '%s', SCT: %s\n", c.Precert.TBSCertificate.Subject.CommonName, sct)
+			recordFailure(addedCerts, c.Chain[0], err, res.Errors)
 		}
 	}
 	wg.Done()
@@ -185,15 +217,28 @@ func main() {
 	sctWriterWG.Add(1)
 	go sctWriterJob(addedCerts, sctWriter, &sctWriterWG)
 
-	submitLogClient := client.New(*targetLogUri, &http.Client{
-		Transport: transport,
-	})
+	var targetLogs []submission.Log
+	for _, uri := range strings.Split(*targetLogUri, ",") {
+		targetLogs = append(targetLogs, submission.Log{
+			Name: uri,
+			// Treat each target log as independently operated.
+			Operator: uri,
+			Client: client.New(uri, &http.Client{
+				Transport: transport,
+			}),
+			Timeout: *submitTimeout,
+		})
+	}
+	submitter, err := submission.NewSubmitter(targetLogs, submission.OperatorPolicy{MinSCTs: *minTargetSCTs})
+	if err != nil {
+		log.Fatalf("Invalid --target_log_uri: %v", err)
+	}
 
 	var submitterWG sync.WaitGroup
 	for w := 0; w < *parallelSubmit; w++ {
 		submitterWG.Add(2)
-		go certSubmitterJob(addedCerts, submitLogClient, certs, &submitterWG)
-		go precertSubmitterJob(addedCerts, submitLogClient, precerts, &submitterWG)
+		go certSubmitterJob(addedCerts, submitter, certs, &submitterWG)
+		go precertSubmitterJob(addedCerts, submitter, precerts, &submitterWG)
 	}
 
 	addChainFunc := func(entry *ct.LogEntry) {
@@ -13,6 +13,7 @@ import (
 	ct "github.com/google/certificate-transparency/go"
 	"github.com/google/certificate-transparency/go/client"
 	"github.com/google/certificate-transparency/go/x509"
+	"golang.org/x/net/context"
 )
 
 // Clients wishing to implement their own Matchers should implement this interface:
@@ -179,8 +180,8 @@ type fetchRange struct {
 	end   int64
 }
 
-// Takes the error returned by either x509.ParseCertificate() or
-// x509.ParseTBSCertificate() and determines if it's non-fatal or otherwise.
+// Takes the error returned by ct.LogEntry.ParseCertificate() and determines if
+// it's non-fatal or otherwise.
 // In the case of non-fatal errors, the error will be logged,
 // entriesWithNonFatalErrors will be incremented, and the return value will be
 // nil.
@@ -214,27 +215,19 @@ func (s *Scanner) processEntry(entry ct.LogEntry, foundCert func(*ct.LogEntry),
 			// Only interested in precerts and this is an X.509 cert, early-out.
 			return
 		}
-		cert, err := x509.ParseCertificate(entry.Leaf.TimestampedEntry.X509Entry)
-		if err = s.handleParseEntryError(err, entry.Leaf.TimestampedEntry.EntryType, entry.Index); err != nil {
+		if err := s.handleParseEntryError(entry.ParseCertificate(), entry.Leaf.TimestampedEntry.EntryType, entry.Index); err != nil {
 			// We hit an unparseable entry, already logged inside handleParseEntryError()
 			return
 		}
-		if s.opts.Matcher.CertificateMatches(cert) {
-			entry.X509Cert = cert
+		if s.opts.Matcher.CertificateMatches(entry.X509Cert) {
 			foundCert(&entry)
 		}
 	case ct.PrecertLogEntryType:
-		c, err := x509.ParseTBSCertificate(entry.Leaf.TimestampedEntry.PrecertEntry.TBSCertificate)
-		if err = s.handleParseEntryError(err, entry.Leaf.TimestampedEntry.EntryType, entry.Index); err != nil {
+		if err := s.handleParseEntryError(entry.ParseCertificate(), entry.Leaf.TimestampedEntry.EntryType, entry.Index); err != nil {
 			// We hit an unparseable entry, already logged inside handleParseEntryError()
 			return
 		}
-		precert := &ct.Precertificate{
-			Raw:            entry.Chain[0],
-			TBSCertificate: *c,
-			IssuerKeyHash:  entry.Leaf.TimestampedEntry.PrecertEntry.IssuerKeyHash}
-		if s.opts.Matcher.PrecertificateMatches(precert) {
-			entry.Precert = precert
+		if s.opts.Matcher.PrecertificateMatches(entry.Precert) {
 			foundPrecert(&entry)
 		}
 		s.precertsSeen++
@@ -260,24 +253,23 @@ func (s *Scanner) matcherJob(id int, entries <-chan matcherJob, foundCert func(*
 // Sends true over the |done| channel when the |ranges| channel is closed.
 func (s *Scanner) fetcherJob(id int, ranges <-chan fetchRange, entries chan<- matcherJob, wg *sync.WaitGroup) {
 	for r := range ranges {
-		success := false
 		// TODO(alcutter): give up after a while:
-		for !success {
-			logEntries, err := s.logClient.GetEntries(r.start, r.end)
-			if err != nil {
-				s.Log(fmt.Sprintf("Problem fetching from log: %s", err.Error()))
-				continue
+		for r.start <= r.end {
+			// The iterator makes further requests if the log returns fewer
+			// entries than were asked for, so an error means the log failed
+			// and we need to resume from wherever it got to.
+			it := s.logClient.NewEntryIterator(context.TODO(), r.start, r.end, 0)
+			for it.Next() {
+				logEntry := it.Entry()
+				entries <- matcherJob{*logEntry, logEntry.Index}
+				r.start = logEntry.Index + 1
 			}
-			for _, logEntry := range logEntries {
-				logEntry.Index = r.start
-				entries <- matcherJob{logEntry, r.start}
-				r.start++
-			}
-			if r.start > r.end {
-				// Only complete if we actually got all the leaves we were
-				// expecting -- Logs MAY return fewer than the number of
-				// leaves requested.
-				success = true
+			if err := it.Err(); err != nil {
+				if rspErr, ok := err.(client.RspError); ok {
+					s.Log(fmt.Sprintf("Log returned bad response: %v: %s", rspErr, rspErr.Body))
+				} else {
+					s.Log(fmt.Sprintf("Problem fetching from log: %s", err.Error()))
+				}
 			}
 		}
 	}
@@ -1,15 +1,17 @@
 package scanner
 
 import (
+	"bytes"
 	"container/list"
-	"log"
+	"math/big"
 	"net/http"
-	"net/http/httptest"
 	"regexp"
 	"testing"
 
 	ct "github.com/google/certificate-transparency/go"
 	"github.com/google/certificate-transparency/go/client"
+	"github.com/google/certificate-transparency/go/ctfake"
+	"github.com/google/certificate-transparency/go/testonly"
 	"github.com/google/certificate-transparency/go/x509"
 )
 
@@ -153,27 +155,26 @@ func TestScannerMatchSubjectRegexMatchesPrecertificateSAN(t *testing.T) {
 }
 
 func TestScannerEndToEnd(t *testing.T) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.URL.Path {
-		case "/ct/v1/get-sth":
-			log.Printf("GetSTH")
-			if _, err := w.Write([]byte(FourEntrySTH)); err != nil {
-				t.Fatal("Failed to write get-sth response")
-			}
-		case "/ct/v1/get-entries":
-			log.Printf("GetEntries %s", r.URL.RawQuery)
-			if _, err := w.Write([]byte(FourEntries)); err != nil {
-				t.Fatal("Failed to write get-sth response")
-			}
-		default:
-			t.Fatal("Unexpected request")
+	ca := testonly.ReadPEM(t, "ca-cert.pem")
+	l, err := ctfake.NewLog(ctfake.Config{Roots: []ct.ASN1Cert{ca}})
+	if err != nil {
+		t.Fatalf("NewLog()=%v", err)
+	}
+	defer l.Close()
+	// test-embedded-cert.pem and test-embedded-pre-cert.pem both have serial
+	// number 7; the other certificates don't.
+	for _, name := range []string{"test-cert.pem", "test-embedded-cert.pem", "intermediate-cert.pem"} {
+		if _, err := l.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, name), ca}); err != nil {
+			t.Fatalf("AddChain(%s)=%v", name, err)
 		}
-	}))
-	defer ts.Close()
+	}
+	if _, err := l.AddPreChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-embedded-pre-cert.pem"), ca}); err != nil {
+		t.Fatalf("AddPreChain()=%v", err)
+	}
 
-	logClient := client.New(ts.URL, &http.Client{})
+	logClient := client.New(l.URL(), &http.Client{})
 	opts := ScannerOptions{
-		Matcher:       &MatchSubjectRegex{regexp.MustCompile(".*\\.google\\.com"), nil},
+		Matcher:       &MatchSerialNumber{*big.NewInt(7)},
 		BatchSize:     10,
 		NumWorkers:    1,
 		ParallelFetch: 1,
@@ -184,7 +185,7 @@ func TestScannerEndToEnd(t *testing.T) {
 	var matchedCerts list.List
 	var matchedPrecerts list.List
 
-	err := scanner.Scan(func(e *ct.LogEntry) {
+	err = scanner.Scan(func(e *ct.LogEntry) {
 		// Annoyingly we can't t.Fatal() in here, as this is run in another go
 		// routine
 		matchedCerts.PushBack(*e.X509Cert)
@@ -196,15 +197,18 @@ func TestScannerEndToEnd(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if matchedPrecerts.Len() != 0 {
-		t.Fatal("Found unexpected Precert")
+	if matchedPrecerts.Len() != 1 {
+		t.Fatalf("Found %d Precerts, want 1", matchedPrecerts.Len())
+	}
+	if !bytes.Equal(matchedPrecerts.Front().Value.(ct.Precertificate).Raw, testonly.ReadPEM(t, "test-embedded-pre-cert.pem")) {
+		t.Fatal("Matched unexpected Precert")
 	}
 
 	switch matchedCerts.Len() {
 	case 0:
-		t.Fatal("Failed to find mail.google.com cert")
+		t.Fatal("Failed to find test-embedded-cert.pem")
 	case 1:
-		if matchedCerts.Front().Value.(x509.Certificate).Subject.CommonName != "mail.google.com" {
+		if !bytes.Equal(matchedCerts.Front().Value.(x509.Certificate).Raw, testonly.ReadPEM(t, "test-embedded-cert.pem")) {
 			t.Fatal("Matched unexpected cert")
 		}
 	default:
@@ -2,15 +2,19 @@ package ct
 
 import (
 	"bytes"
-	"container/list"
 	"crypto"
+	"crypto/sha256"
 	"encoding/asn1"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"strings"
+
+	"github.com/google/certificate-transparency/go/canonicaljson"
+	"github.com/google/certificate-transparency/go/objecthash"
+	"github.com/google/certificate-transparency/go/tls"
+	"github.com/google/certificate-transparency/go/x509"
 )
 
 // Variable size structure prefix-header byte lengths
@@ -95,102 +99,18 @@ func readVarBytes(r io.Reader, numLenBytes int) ([]byte, error) {
 	return data, nil
 }
 
-// Reads a list of ASN1Cert types from |r|
-func readASN1CertList(r io.Reader, totalLenBytes int, elementLenBytes int) ([]ASN1Cert, error) {
-	listBytes, err := readVarBytes(r, totalLenBytes)
-	if err != nil {
-		return []ASN1Cert{}, err
-	}
-	list := list.New()
-	listReader := bytes.NewReader(listBytes)
-	var entry []byte
-	for err == nil {
-		entry, err = readVarBytes(listReader, elementLenBytes)
-		if err != nil {
-			if err != io.EOF {
-				return []ASN1Cert{}, err
-			}
-		} else {
-			list.PushBack(entry)
-		}
-	}
-	ret := make([]ASN1Cert, list.Len())
-	i := 0
-	for e := list.Front(); e != nil; e = e.Next() {
-		ret[i] = e.Value.([]byte)
-		i++
-	}
-	return ret, nil
-}
-
 // ReadTimestampedEntryInto parses the byte-stream representation of a
 // TimestampedEntry from |r| and populates the struct |t| with the data.  See
 // RFC section 3.4 for details on the format.
 // Returns a non-nil error if there was a problem.
 func ReadTimestampedEntryInto(r io.Reader, t *TimestampedEntry) error {
-	var err error
-	if err = binary.Read(r, binary.BigEndian, &t.Timestamp); err != nil {
-		return err
-	}
-	if err = binary.Read(r, binary.BigEndian, &t.EntryType); err != nil {
-		return err
-	}
-	switch t.EntryType {
-	case X509LogEntryType:
-		if t.X509Entry, err = readVarBytes(r, CertificateLengthBytes); err != nil {
-			return err
-		}
-	case PrecertLogEntryType:
-		if err := binary.Read(r, binary.BigEndian, &t.PrecertEntry.IssuerKeyHash); err != nil {
-			return err
-		}
-		if t.PrecertEntry.TBSCertificate, err = readVarBytes(r, PreCertificateLengthBytes); err != nil {
-			return err
-		}
-	case XJSONLogEntryType:
-		if t.JSONData, err = readVarBytes(r, JSONLengthBytes); err != nil {
-			return err
-		}
-	default:
-		return fmt.Errorf("unknown EntryType: %d", t.EntryType)
-	}
-	t.Extensions, err = readVarBytes(r, ExtensionsLengthBytes)
-	return nil
+	return tls.Read(r, t)
 }
 
 // SerializeTimestampedEntry writes timestamped entry to Writer.
 // In case of error, w may contain garbage.
 func SerializeTimestampedEntry(w io.Writer, t *TimestampedEntry) error {
-	if err := binary.Write(w, binary.BigEndian, t.Timestamp); err != nil {
-		return err
-	}
-	if err := binary.Write(w, binary.BigEndian, t.EntryType); err != nil {
-		return err
-	}
-	switch t.EntryType {
-	case X509LogEntryType:
-		if err := writeVarBytes(w, t.X509Entry, CertificateLengthBytes); err != nil {
-			return err
-		}
-	case PrecertLogEntryType:
-		if err := binary.Write(w, binary.BigEndian, t.PrecertEntry.IssuerKeyHash); err != nil {
-			return err
-		}
-		if err := writeVarBytes(w, t.PrecertEntry.TBSCertificate, PreCertificateLengthBytes); err != nil {
-			return err
-		}
-	case XJSONLogEntryType:
-		// TODO: Pending google/certificate-transparency#1243, replace
-		// with ObjectHash once supported by CT server.
-		//jsonhash := objecthash.CommonJSONHash(string(t.JSONData))
-		if err := writeVarBytes(w, []byte(t.JSONData), JSONLengthBytes); err != nil {
-			return err
-		}
-	default:
-		return fmt.Errorf("unknown EntryType: %d", t.EntryType)
-	}
-	writeVarBytes(w, t.Extensions, ExtensionsLengthBytes)
-	return nil
+	return tls.Write(w, t)
 }
 
 // ReadMerkleTreeLeaf parses the byte-stream representation of a MerkleTreeLeaf
@@ -201,19 +121,7 @@ func SerializeTimestampedEntry(w io.Writer, t *TimestampedEntry) error {
 // problem
 func ReadMerkleTreeLeaf(r io.Reader) (*MerkleTreeLeaf, error) {
 	var m MerkleTreeLeaf
-	if err := binary.Read(r, binary.BigEndian, &m.Version); err != nil {
-		return nil, err
-	}
-	if m.Version != V1 {
-		return nil, fmt.Errorf("unknown Version %d", m.Version)
-	}
-	if err := binary.Read(r, binary.BigEndian, &m.LeafType); err != nil {
-		return nil, err
-	}
-	if m.LeafType != TimestampedEntryLeafType {
-		return nil, fmt.Errorf("unknown LeafType %d", m.LeafType)
-	}
-	if err := ReadTimestampedEntryInto(r, &m.TimestampedEntry); err != nil {
+	if err := tls.Read(r, &m); err != nil {
 		return nil, err
 	}
 	return &m, nil
@@ -222,72 +130,155 @@ func ReadMerkleTreeLeaf(r io.Reader) (*MerkleTreeLeaf, error) {
 // UnmarshalX509ChainArray unmarshalls the contents of the "chain:" entry in a
 // GetEntries response in the case where the entry refers to an X509 leaf.
 func UnmarshalX509ChainArray(b []byte) ([]ASN1Cert, error) {
-	return readASN1CertList(bytes.NewReader(b), CertificateChainLengthBytes, CertificateLengthBytes)
+	var chain CertificateChain
+	if rest, err := tls.Unmarshal(b, &chain); err != nil {
+		return nil, err
+	} else if len(rest) > 0 {
+		return nil, errors.New("trailing data after certificate chain")
+	}
+	return chain.Entries, nil
 }
 
 // UnmarshalPrecertChainArray unmarshalls the contents of the "chain:" entry in
 // a GetEntries response in the case where the entry refers to a Precertificate
-// leaf.
+// leaf. The Precertificate is returned first, followed by its chain.
 func UnmarshalPrecertChainArray(b []byte) ([]ASN1Cert, error) {
-	var chain []ASN1Cert
+	var entry PrecertChainEntry
+	if rest, err := tls.Unmarshal(b, &entry); err != nil {
+		return nil, err
+	} else if len(rest) > 0 {
+		return nil, errors.New("trailing data after precertificate chain")
+	}
+	return append([]ASN1Cert{entry.PreCertificate}, entry.CertificateChain...), nil
+}
 
-	reader := bytes.NewReader(b)
-	// read the pre-cert entry:
-	precert, err := readVarBytes(reader, CertificateLengthBytes)
-	if err != nil {
-		return chain, err
+// MarshalX509ChainArray returns the extra_data for an X509 entry whose
+// certificate was issued by |chain|, as served by get-entries.
+func MarshalX509ChainArray(chain []ASN1Cert) ([]byte, error) {
+	return tls.Marshal(CertificateChain{Entries: chain})
+}
+
+// MarshalPrecertChainArray returns the extra_data for a Precertificate entry,
+// as served by get-entries. |chain| holds the Precertificate followed by its
+// chain, as returned by UnmarshalPrecertChainArray.
+func MarshalPrecertChainArray(chain []ASN1Cert) ([]byte, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("precertificate chain is empty")
 	}
-	chain = append(chain, precert)
-	// and then read and return the chain up to the root:
-	remainingChain, err := readASN1CertList(reader, CertificateChainLengthBytes, CertificateLengthBytes)
+	return tls.Marshal(PrecertChainEntry{PreCertificate: chain[0], CertificateChain: chain[1:]})
+}
+
+// RawLogEntryFromLeaf decodes the |leafInput| and |extraData| returned by
+// get-entries for the entry at |index| in a log into a LogEntry with its Leaf,
+// Chain and JSONData populated. The entry's certificate is not parsed; see
+// LogEntryFromLeaf.
+// Returns an error if |leafInput| or |extraData| are malformed.
+func RawLogEntryFromLeaf(index int64, leafInput LeafInput, extraData []byte) (*LogEntry, error) {
+	r := bytes.NewReader(leafInput)
+	leaf, err := ReadMerkleTreeLeaf(r)
 	if err != nil {
-		return chain, err
+		return nil, fmt.Errorf("failed to parse leaf_input: %v", err)
+	}
+	if r.Len() > 0 {
+		return nil, errors.New("trailing data after leaf_input")
 	}
-	chain = append(chain, remainingChain...)
-	return chain, nil
-}
 
-// UnmarshalDigitallySigned reconstructs a DigitallySigned structure from a Reader
-func UnmarshalDigitallySigned(r io.Reader) (*DigitallySigned, error) {
-	var h byte
-	if err := binary.Read(r, binary.BigEndian, &h); err != nil {
-		return nil, fmt.Errorf("failed to read HashAlgorithm: %v", err)
+	entry := &LogEntry{Index: index, Leaf: *leaf}
+	switch leaf.TimestampedEntry.EntryType {
+	case X509LogEntryType:
+		entry.Chain, err = UnmarshalX509ChainArray(extraData)
+	case PrecertLogEntryType:
+		entry.Chain, err = UnmarshalPrecertChainArray(extraData)
+	case XJSONLogEntryType:
+		entry.JSONData = leaf.TimestampedEntry.JSONData
+	default:
+		return nil, fmt.Errorf("unknown entry type %v", leaf.TimestampedEntry.EntryType)
 	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse extra_data: %v", err)
+	}
+	return entry, nil
+}
 
-	var s byte
-	if err := binary.Read(r, binary.BigEndian, &s); err != nil {
-		return nil, fmt.Errorf("failed to read SignatureAlgorithm: %v", err)
+// ParseCertificate parses the certificate in the entry's Leaf into X509Cert,
+// for X.509 entries, or Precert, for Precertificate entries. It does nothing
+// for other entry types.
+// As with x509.ParseCertificate, an x509.NonFatalErrors error means that the
+// certificate was parsed despite its problems; after any other error X509Cert
+// and Precert are left unset.
+func (e *LogEntry) ParseCertificate() error {
+	te := e.Leaf.TimestampedEntry
+	switch te.EntryType {
+	case X509LogEntryType:
+		cert, err := x509.ParseCertificate(te.X509Entry)
+		if x509.IsFatal(err) {
+			return err
+		}
+		e.X509Cert = cert
+		return err
+	case PrecertLogEntryType:
+		if len(e.Chain) == 0 {
+			return errors.New("precertificate entry has no precertificate in its chain")
+		}
+		tbs, err := x509.ParseTBSCertificate(te.PrecertEntry.TBSCertificate)
+		if x509.IsFatal(err) {
+			return err
+		}
+		e.Precert = &Precertificate{
+			Raw:            e.Chain[0],
+			IssuerKeyHash:  te.PrecertEntry.IssuerKeyHash,
+			TBSCertificate: *tbs,
+		}
+		return err
 	}
+	return nil
+}
 
-	sig, err := readVarBytes(r, SignatureLengthBytes)
+// LogEntryFromLeaf builds the fully populated LogEntry for the entry at
+// |index| in a log from the |leafInput| and |extraData| returned for it by
+// get-entries, as RawLogEntryFromLeaf followed by ParseCertificate.
+// If the certificate has problems which x509 considers non-fatal the entry is
+// returned along with the x509.NonFatalErrors describing them; after any
+// other error the returned entry is nil.
+func LogEntryFromLeaf(index int64, leafInput LeafInput, extraData []byte) (*LogEntry, error) {
+	entry, err := RawLogEntryFromLeaf(index, leafInput, extraData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read Signature bytes: %v", err)
+		return nil, err
 	}
+	err = entry.ParseCertificate()
+	if x509.IsFatal(err) {
+		return nil, err
+	}
+	return entry, err
+}
 
-	return &DigitallySigned{
-		HashAlgorithm:      HashAlgorithm(h),
-		SignatureAlgorithm: SignatureAlgorithm(s),
-		Signature:          sig,
-	}, nil
+// UnmarshalDigitallySigned reconstructs a DigitallySigned structure from a Reader
+func UnmarshalDigitallySigned(r io.Reader) (*DigitallySigned, error) {
+	var ds DigitallySigned
+	if err := tls.Read(r, &ds); err != nil {
+		return nil, fmt.Errorf("failed to read DigitallySigned: %v", err)
+	}
+	return &ds, nil
 }
 
 func marshalDigitallySignedHere(ds DigitallySigned, here []byte) ([]byte, error) {
-	sigLen := len(ds.Signature)
-	dsOutLen := 2 + SignatureLengthBytes + sigLen
+	b, err := tls.Marshal(ds)
+	if err != nil {
+		return nil, err
+	}
+	return copyHere(b, here)
+}
+
+// copyHere copies |b| into |here|, if it is non-nil, and returns the slice of
+// |here| which it occupies; otherwise |b| is returned.
+func copyHere(b, here []byte) ([]byte, error) {
 	if here == nil {
-		here = make([]byte, dsOutLen)
+		return b, nil
 	}
-	if len(here) < dsOutLen {
+	if len(here) < len(b) {
 		return nil, ErrNotEnoughBuffer
 	}
-	here = here[0:dsOutLen]
-
-	here[0] = byte(ds.HashAlgorithm)
-	here[1] = byte(ds.SignatureAlgorithm)
-	binary.BigEndian.PutUint16(here[2:4], uint16(sigLen))
-	copy(here[4:], ds.Signature)
-
-	return here, nil
+	return here[:copy(here, b)], nil
 }
 
 // MarshalDigitallySigned marshalls a DigitallySigned structure into a byte array
@@ -312,90 +303,6 @@ func checkExtensionsFormat(ext CTExtensions) error {
 	return nil
 }
 
-func serializeV1CertSCTSignatureInput(timestamp uint64, cert ASN1Cert, ext CTExtensions) ([]byte, error) {
-	if err := checkCertificateFormat(cert); err != nil {
-		return nil, err
-	}
-	if err := checkExtensionsFormat(ext); err != nil {
-		return nil, err
-	}
-	var buf bytes.Buffer
-	if err := binary.Write(&buf, binary.BigEndian, V1); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(&buf, binary.BigEndian, CertificateTimestampSignatureType); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(&buf, binary.BigEndian, timestamp); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(&buf, binary.BigEndian, X509LogEntryType); err != nil {
-		return nil, err
-	}
-	if err := writeVarBytes(&buf, cert, CertificateLengthBytes); err != nil {
-		return nil, err
-	}
-	if err := writeVarBytes(&buf, ext, ExtensionsLengthBytes); err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
-}
-
-func serializeV1JSONSCTSignatureInput(timestamp uint64, j []byte) ([]byte, error) {
-	var buf bytes.Buffer
-	if err := binary.Write(&buf, binary.BigEndian, V1); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(&buf, binary.BigEndian, CertificateTimestampSignatureType); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(&buf, binary.BigEndian, timestamp); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(&buf, binary.BigEndian, XJSONLogEntryType); err != nil {
-		return nil, err
-	}
-	if err := writeVarBytes(&buf, j, JSONLengthBytes); err != nil {
-		return nil, err
-	}
-	if err := writeVarBytes(&buf, nil, ExtensionsLengthBytes); err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
-}
-
-func serializeV1PrecertSCTSignatureInput(timestamp uint64, issuerKeyHash [issuerKeyHashLength]byte, tbs []byte, ext CTExtensions) ([]byte, error) {
-	if err := checkCertificateFormat(tbs); err != nil {
-		return nil, err
-	}
-	if err := checkExtensionsFormat(ext); err != nil {
-		return nil, err
-	}
-	var buf bytes.Buffer
-	if err := binary.Write(&buf, binary.BigEndian, V1); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(&buf, binary.BigEndian, CertificateTimestampSignatureType); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(&buf, binary.BigEndian, timestamp); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(&buf, binary.BigEndian, PrecertLogEntryType); err != nil {
-		return nil, err
-	}
-	if _, err := buf.Write(issuerKeyHash[:]); err != nil {
-		return nil, err
-	}
-	if err := writeVarBytes(&buf, tbs, CertificateLengthBytes); err != nil {
-		return nil, err
-	}
-	if err := writeVarBytes(&buf, ext, ExtensionsLengthBytes); err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
-}
-
 func serializeV1SCTSignatureInput(sct SignedCertificateTimestamp, entry LogEntry) ([]byte, error) {
 	if sct.SCTVersion != V1 {
 		return nil, fmt.Errorf("unsupported SCT version, expected V1, but got %s", sct.SCTVersion)
@@ -403,18 +310,36 @@ func serializeV1SCTSignatureInput(sct SignedCertificateTimestamp, entry LogEntry
 	if entry.Leaf.LeafType != TimestampedEntryLeafType {
 		return nil, fmt.Errorf("Unsupported leaf type %s", entry.Leaf.LeafType)
 	}
-	switch entry.Leaf.TimestampedEntry.EntryType {
+	te := entry.Leaf.TimestampedEntry
+	input := CertificateTimestamp{
+		SCTVersion:    V1,
+		SignatureType: CertificateTimestampSignatureType,
+		Timestamp:     sct.Timestamp,
+		EntryType:     te.EntryType,
+		Extensions:    te.Extensions,
+	}
+	switch te.EntryType {
 	case X509LogEntryType:
-		return serializeV1CertSCTSignatureInput(sct.Timestamp, entry.Leaf.TimestampedEntry.X509Entry, entry.Leaf.TimestampedEntry.Extensions)
+		if err := checkCertificateFormat(te.X509Entry); err != nil {
+			return nil, err
+		}
+		input.X509Entry = te.X509Entry
 	case PrecertLogEntryType:
-		return serializeV1PrecertSCTSignatureInput(sct.Timestamp, entry.Leaf.TimestampedEntry.PrecertEntry.IssuerKeyHash,
-			entry.Leaf.TimestampedEntry.PrecertEntry.TBSCertificate,
-			entry.Leaf.TimestampedEntry.Extensions)
+		if err := checkCertificateFormat(te.PrecertEntry.TBSCertificate); err != nil {
+			return nil, err
+		}
+		input.PrecertEntry = te.PrecertEntry
 	case XJSONLogEntryType:
-		return serializeV1JSONSCTSignatureInput(sct.Timestamp, entry.Leaf.TimestampedEntry.JSONData)
+		input.JSONEntry = te.JSONData
+		// Extensions have never been covered by signatures on JSON entries.
+		input.Extensions = nil
 	default:
-		return nil, fmt.Errorf("unknown TimestampedEntryLeafType %s", entry.Leaf.TimestampedEntry.EntryType)
+		return nil, fmt.Errorf("unknown TimestampedEntryLeafType %s", te.EntryType)
+	}
+	if err := checkExtensionsFormat(input.Extensions); err != nil {
+		return nil, err
 	}
+	return tls.Marshal(input)
 }
 
 // SerializeSCTSignatureInput serializes the passed in sct and log entry into
@@ -444,43 +369,14 @@ func serializeV1SCTHere(sct SignedCertificateTimestamp, here []byte) ([]byte, er
 	if sct.SCTVersion != V1 {
 		return nil, ErrInvalidVersion
 	}
-	sctLen, err := sct.SerializedLength()
-	if err != nil {
-		return nil, err
-	}
-	if here == nil {
-		here = make([]byte, sctLen)
-	}
-	if len(here) < sctLen {
-		return nil, ErrNotEnoughBuffer
-	}
 	if err := checkExtensionsFormat(sct.Extensions); err != nil {
 		return nil, err
 	}
-
-	here = here[0:sctLen]
-
-	// Write Version
-	here[0] = byte(sct.SCTVersion)
-
-	// Write LogID
-	copy(here[1:33], sct.LogID[:])
-
-	// Write Timestamp
-	binary.BigEndian.PutUint64(here[33:41], sct.Timestamp)
-
-	// Write Extensions
-	extLen := len(sct.Extensions)
-	binary.BigEndian.PutUint16(here[41:43], uint16(extLen))
-	n := 43 + extLen
-	copy(here[43:n], sct.Extensions)
-
-	// Write Signature
-	_, err = marshalDigitallySignedHere(sct.Signature, here[n:])
+	b, err := tls.Marshal(sct)
 	if err != nil {
 		return nil, err
 	}
-	return here, nil
+	return copyHere(b, here)
 }
 
 // SerializeSCTHere serializes the passed in sct into the format specified
@@ -506,38 +402,13 @@ func SerializeSCT(sct SignedCertificateTimestamp) ([]byte, error) {
 	return SerializeSCTHere(sct, nil)
 }
 
-func deserializeSCTV1(r io.Reader, sct *SignedCertificateTimestamp) error {
-	if err := binary.Read(r, binary.BigEndian, &sct.LogID); err != nil {
-		return err
-	}
-	if err := binary.Read(r, binary.BigEndian, &sct.Timestamp); err != nil {
-		return err
-	}
-	ext, err := readVarBytes(r, ExtensionsLengthBytes)
-	if err != nil {
-		return err
-	}
-	sct.Extensions = ext
-	ds, err := UnmarshalDigitallySigned(r)
-	if err != nil {
-		return err
-	}
-	sct.Signature = *ds
-	return nil
-}
-
 // DeserializeSCT reads an SCT from Reader.
 func DeserializeSCT(r io.Reader) (*SignedCertificateTimestamp, error) {
 	var sct SignedCertificateTimestamp
-	if err := binary.Read(r, binary.BigEndian, &sct.SCTVersion); err != nil {
+	if err := tls.Read(r, &sct); err != nil {
 		return nil, err
 	}
-	switch sct.SCTVersion {
-	case V1:
-		return &sct, deserializeSCTV1(r, &sct)
-	default:
-		return nil, fmt.Errorf("unknown SCT version %d", sct.SCTVersion)
-	}
+	return &sct, nil
 }
 
 func serializeV1STHSignatureInput(sth SignedTreeHead) ([]byte, error) {
@@ -551,23 +422,13 @@ func serializeV1STHSignatureInput(sth SignedTreeHead) ([]byte, error) {
 		return nil, fmt.Errorf("invalid TreeHash length, got %d expected %d", len(sth.SHA256RootHash), crypto.SHA256.Size())
 	}
 
-	var buf bytes.Buffer
-	if err := binary.Write(&buf, binary.BigEndian, V1); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(&buf, binary.BigEndian, TreeHashSignatureType); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(&buf, binary.BigEndian, sth.Timestamp); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(&buf, binary.BigEndian, sth.TreeSize); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(&buf, binary.BigEndian, sth.SHA256RootHash); err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
+	return tls.Marshal(TreeHeadSignature{
+		Version:        V1,
+		SignatureType:  TreeHashSignatureType,
+		Timestamp:      sth.Timestamp,
+		TreeSize:       sth.TreeSize,
+		SHA256RootHash: sth.SHA256RootHash,
+	})
 }
 
 // SerializeSTHSignatureInput serializes the passed in sth into the correct
@@ -630,25 +491,66 @@ func SerializeSCTList(scts []SignedCertificateTimestamp) ([]byte, error) {
 	return asn1.Marshal(buf.Bytes()) // transform to Octet String
 }
 
-// SerializeMerkleTreeLeaf writes MerkleTreeLeaf to Writer.
-// In case of error, w may contain garbage.
-func SerializeMerkleTreeLeaf(w io.Writer, m *MerkleTreeLeaf) error {
-	if m.Version != V1 {
-		return fmt.Errorf("unknown Version %d", m.Version)
+// DeserializeSCTList parses the DER encoded OCTET STRING |data|, as produced
+// by SerializeSCTList and found in the X.509v3 and OCSP SCT list extensions,
+// into the SCTs it contains (see RFC6962 Section 3.3).
+// SCTs with a version this package doesn't understand are returned, still
+// serialized, in |unknown| rather than causing an error.
+func DeserializeSCTList(data []byte) (scts []SignedCertificateTimestamp, unknown [][]byte, err error) {
+	var list []byte
+	if rest, err := asn1.Unmarshal(data, &list); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse SCT list OCTET STRING: %v", err)
+	} else if len(rest) > 0 {
+		return nil, nil, errors.New("trailing data after SCT list OCTET STRING")
 	}
-	if err := binary.Write(w, binary.BigEndian, m.Version); err != nil {
-		return err
+	return DeserializeTLSSCTList(list)
+}
+
+// DeserializeTLSSCTList parses the TLS encoded SignedCertificateTimestampList
+// |data|, as found in the signed_certificate_timestamp TLS extension, into the
+// SCTs it contains. Unknown SCT versions are handled as by DeserializeSCTList.
+func DeserializeTLSSCTList(data []byte) (scts []SignedCertificateTimestamp, unknown [][]byte, err error) {
+	r := bytes.NewReader(data)
+	list, err := readVarBytes(r, 2)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read SCT list: %v", err)
 	}
-	if m.LeafType != TimestampedEntryLeafType {
-		return fmt.Errorf("unknown LeafType %d", m.LeafType)
+	if r.Len() > 0 {
+		return nil, nil, errors.New("trailing data after SCT list")
 	}
-	if err := binary.Write(w, binary.BigEndian, m.LeafType); err != nil {
-		return err
+	if len(list) == 0 {
+		return nil, nil, errors.New("SCT list empty")
 	}
-	if err := SerializeTimestampedEntry(w, &m.TimestampedEntry); err != nil {
-		return err
+	r = bytes.NewReader(list)
+	for i := 0; r.Len() > 0; i++ {
+		b, err := readVarBytes(r, 2)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read SCT in position %d: %v", i, err)
+		}
+		if len(b) == 0 {
+			return nil, nil, fmt.Errorf("SCT in position %d empty", i)
+		}
+		if Version(b[0]) != V1 {
+			unknown = append(unknown, b)
+			continue
+		}
+		sr := bytes.NewReader(b)
+		sct, err := DeserializeSCT(sr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse SCT in position %d: %v", i, err)
+		}
+		if sr.Len() > 0 {
+			return nil, nil, fmt.Errorf("trailing data after SCT in position %d", i)
+		}
+		scts = append(scts, *sct)
 	}
-	return nil
+	return scts, unknown, nil
+}
+
+// SerializeMerkleTreeLeaf writes MerkleTreeLeaf to Writer.
+// In case of error, w may contain garbage.
+func SerializeMerkleTreeLeaf(w io.Writer, m *MerkleTreeLeaf) error {
+	return tls.Write(w, m)
 }
 
 // CreateX509MerkleTreeLeaf generates a MerkleTreeLeaf for an X509 cert
@@ -665,27 +567,129 @@ func CreateX509MerkleTreeLeaf(cert ASN1Cert, timestamp uint64) *MerkleTreeLeaf {
 }
 
 // CreateJSONMerkleTreeLeaf creates the merkle tree leaf for json data.
+// It returns nil if |data| cannot be encoded; use CreateXJSONMerkleTreeLeaf
+// to get the error and the leaf hash.
 func CreateJSONMerkleTreeLeaf(data interface{}, timestamp uint64) *MerkleTreeLeaf {
-	jsonData, err := json.Marshal(AddJSONRequest{Data: data})
+	leaf, _, err := CreateXJSONMerkleTreeLeaf(data, timestamp)
 	if err != nil {
 		return nil
 	}
-	// Match the JSON serialization implemented by json-c
-	jsonStr := strings.Replace(string(jsonData), ":", ": ", -1)
-	jsonStr = strings.Replace(jsonStr, ",", ", ", -1)
-	jsonStr = strings.Replace(jsonStr, "{", "{ ", -1)
-	jsonStr = strings.Replace(jsonStr, "}", " }", -1)
-	jsonStr = strings.Replace(jsonStr, "/", `\/`, -1)
-	// TODO: Pending google/certificate-transparency#1243, replace with
-	// ObjectHash once supported by CT server.
+	return leaf
+}
 
-	return &MerkleTreeLeaf{
+// CreateXJSONMerkleTreeLeaf builds the MerkleTreeLeaf that an XJSON log
+// creates for an add-json submission of |data| at |timestamp|, along with
+// its leaf hash, which can be used to request an inclusion proof.
+// The submission is stored in the canonical JSON form produced by the CT
+// server (json-c), so that the leaf matches the one the log builds.
+func CreateXJSONMerkleTreeLeaf(data interface{}, timestamp uint64) (*MerkleTreeLeaf, SHA256Hash, error) {
+	var hash SHA256Hash
+	jsonData, err := canonicaljson.Marshal(AddJSONRequest{Data: data})
+	if err != nil {
+		return nil, hash, err
+	}
+	leaf := &MerkleTreeLeaf{
 		Version:  V1,
 		LeafType: TimestampedEntryLeafType,
 		TimestampedEntry: TimestampedEntry{
 			Timestamp: timestamp,
 			EntryType: XJSONLogEntryType,
-			JSONData:  []byte(jsonStr),
+			JSONData:  jsonData,
 		},
 	}
+	if hash, err = LeafHashForLeaf(leaf); err != nil {
+		return nil, hash, err
+	}
+	return leaf, hash, nil
+}
+
+// VerifyXJSONMerkleTreeLeaf checks that |leaf| is an XJSON entry holding an
+// add-json submission of |data|.  The stored JSON and the submission are
+// compared by ObjectHash, so differences in formatting or key order do not
+// matter.
+func VerifyXJSONMerkleTreeLeaf(leaf *MerkleTreeLeaf, data interface{}) error {
+	if leaf.LeafType != TimestampedEntryLeafType || leaf.TimestampedEntry.EntryType != XJSONLogEntryType {
+		return errors.New("leaf is not an XJSON entry")
+	}
+	got, err := objecthash.CommonJSON(leaf.TimestampedEntry.JSONData)
+	if err != nil {
+		return fmt.Errorf("failed to hash leaf JSON data: %v", err)
+	}
+	jsonData, err := json.Marshal(AddJSONRequest{Data: data})
+	if err != nil {
+		return err
+	}
+	want, err := objecthash.CommonJSON(jsonData)
+	if err != nil {
+		return fmt.Errorf("failed to hash data: %v", err)
+	}
+	if got != want {
+		return fmt.Errorf("leaf JSON data has ObjectHash %x, want %x", got, want)
+	}
+	return nil
+}
+
+// CreatePrecertMerkleTreeLeaf builds the MerkleTreeLeaf for the precertificate
+// |chain| logged at |timestamp|, as described in RFC6962 section 3.2, and
+// returns it along with its Merkle leaf hash, which can be passed to
+// get-proof-by-hash.
+// |chain| must start with the precertificate followed by its issuer; if the
+// issuer is a Precertificate Signing Certificate then the CA which issued it
+// must come next.
+func CreatePrecertMerkleTreeLeaf(chain []ASN1Cert, timestamp uint64) (*MerkleTreeLeaf, SHA256Hash, error) {
+	var hash SHA256Hash
+	if len(chain) < 2 {
+		return nil, hash, errors.New("precertificate chain must include the issuer")
+	}
+	precert, err := x509.ParseCertificate(chain[0])
+	if x509.IsFatal(err) {
+		return nil, hash, fmt.Errorf("failed to parse precertificate: %v", err)
+	}
+	if !precert.IsPrecertificate() {
+		return nil, hash, errors.New("certificate is not a precertificate")
+	}
+	issuer, err := x509.ParseCertificate(chain[1])
+	if x509.IsFatal(err) {
+		return nil, hash, fmt.Errorf("failed to parse issuer: %v", err)
+	}
+	var preIssuer *x509.Certificate
+	if issuer.IsPrecertificateSigningCert() {
+		if len(chain) < 3 {
+			return nil, hash, errors.New("precertificate chain must include the issuer of the Precertificate Signing Certificate")
+		}
+		preIssuer = issuer
+		if issuer, err = x509.ParseCertificate(chain[2]); x509.IsFatal(err) {
+			return nil, hash, fmt.Errorf("failed to parse issuer: %v", err)
+		}
+	}
+	tbs, err := x509.BuildPrecertTBS(precert.RawTBSCertificate, preIssuer)
+	if err != nil {
+		return nil, hash, err
+	}
+	leaf := &MerkleTreeLeaf{
+		Version:  V1,
+		LeafType: TimestampedEntryLeafType,
+		TimestampedEntry: TimestampedEntry{
+			Timestamp: timestamp,
+			EntryType: PrecertLogEntryType,
+			PrecertEntry: PreCert{
+				IssuerKeyHash:  sha256.Sum256(issuer.RawSubjectPublicKeyInfo),
+				TBSCertificate: tbs,
+			},
+		},
+	}
+	if hash, err = LeafHashForLeaf(leaf); err != nil {
+		return nil, hash, err
+	}
+	return leaf, hash, nil
+}
+
+// LeafHashForLeaf returns the RFC6962 Merkle tree hash of |leaf|, which is
+// what get-proof-by-hash expects.
+func LeafHashForLeaf(leaf *MerkleTreeLeaf) (SHA256Hash, error) {
+	b, err := tls.Marshal(leaf)
+	if err != nil {
+		return SHA256Hash{}, err
+	}
+	return sha256.Sum256(append([]byte{0}, b...)), nil
 }
@@ -2,12 +2,17 @@ package ct
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/pem"
 	"io/ioutil"
+	mrand "math/rand"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/google/certificate-transparency/go/testonly"
+	"github.com/google/certificate-transparency/go/x509"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -508,6 +513,134 @@ func TestSerializeSCTList(t *testing.T) {
 	}
 }
 
+func TestDeserializeSCTList(t *testing.T) {
+	want := []SignedCertificateTimestamp{defaultSCT(), defaultSCT()}
+	list := mustDehex(t, defaultSCTListHexString)
+	scts, unknown, err := DeserializeSCTList(list)
+	if err != nil {
+		t.Fatalf("DeserializeSCTList()=%v", err)
+	}
+	assert.Equal(t, want, scts)
+	assert.Empty(t, unknown)
+
+	// The same list without the OCTET STRING wrapping, as sent in TLS.
+	scts, unknown, err = DeserializeTLSSCTList(list[2:])
+	if err != nil {
+		t.Fatalf("DeserializeTLSSCTList()=%v", err)
+	}
+	assert.Equal(t, want, scts)
+	assert.Empty(t, unknown)
+
+	// An SCT with an unknown version is passed through untouched.
+	v2 := []byte{0x01, 0xde, 0xad, 0xbe, 0xef}
+	tls := append([]byte{0x00, byte(len(list) - 4 + 2 + len(v2))}, list[4:4+0x3a]...)
+	tls = append(tls, 0x00, byte(len(v2)))
+	tls = append(tls, v2...)
+	tls = append(tls, list[4+0x3a:]...)
+	scts, unknown, err = DeserializeTLSSCTList(tls)
+	if err != nil {
+		t.Fatalf("DeserializeTLSSCTList() with unknown version=%v", err)
+	}
+	assert.Equal(t, want, scts)
+	assert.Equal(t, [][]byte{v2}, unknown)
+
+	for _, test := range []struct {
+		desc string
+		tls  string
+	}{
+		{"empty", ""},
+		{"empty list", "0000"},
+		{"truncated list", "0005000300"},
+		{"empty SCT", "00020000"},
+		{"truncated SCT", "0004000200"},
+		{"trailing data after list", "0004000201ff00"},
+		{"trailing data in SCT", "003b0039" + defaultSCTHexString + "00"},
+	} {
+		if _, _, err := DeserializeTLSSCTList(mustDehex(t, test.tls)); err == nil {
+			t.Errorf("%s: DeserializeTLSSCTList() succeeded, want error", test.desc)
+		}
+	}
+	if _, _, err := DeserializeSCTList(append(list, 0)); err == nil {
+		t.Error("DeserializeSCTList() with trailing data succeeded, want error")
+	}
+	if _, _, err := DeserializeSCTList(list[2:]); err == nil {
+		t.Error("DeserializeSCTList() without OCTET STRING succeeded, want error")
+	}
+}
+
+func randomBytes(r *mrand.Rand, n int) []byte {
+	b := make([]byte, n)
+	r.Read(b)
+	return b
+}
+
+func randomSCT(r *mrand.Rand) SignedCertificateTimestamp {
+	sct := SignedCertificateTimestamp{
+		SCTVersion: V1,
+		Timestamp:  uint64(r.Int63()),
+		Extensions: randomBytes(r, r.Intn(64)),
+		Signature: DigitallySigned{
+			HashAlgorithm:      SHA256,
+			SignatureAlgorithm: ECDSA,
+			Signature:          randomBytes(r, r.Intn(128)),
+		},
+	}
+	r.Read(sct.LogID[:])
+	return sct
+}
+
+// Round-trips randomly generated SCT lists through SerializeSCTList and
+// DeserializeSCTList, and checks that corrupted lists are either rejected or
+// parsed without panicking.
+func TestDeserializeSCTListFuzz(t *testing.T) {
+	r := mrand.New(mrand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		scts := make([]SignedCertificateTimestamp, 1+r.Intn(5))
+		for j := range scts {
+			scts[j] = randomSCT(r)
+		}
+		b, err := SerializeSCTList(scts)
+		if err != nil {
+			t.Fatalf("SerializeSCTList()=%v", err)
+		}
+		got, unknown, err := DeserializeSCTList(b)
+		if err != nil {
+			t.Fatalf("DeserializeSCTList(%x)=%v", b, err)
+		}
+		if !reflect.DeepEqual(got, scts) || len(unknown) != 0 {
+			t.Fatalf("DeserializeSCTList(%x)=%v, %v; want %v", b, got, unknown, scts)
+		}
+
+		for j := 1 + r.Intn(4); j > 0; j-- {
+			switch r.Intn(3) {
+			case 0:
+				b[r.Intn(len(b))] ^= byte(r.Intn(255) + 1)
+			case 1:
+				b = b[:r.Intn(len(b))]
+			case 2:
+				b = append(b, randomBytes(r, 1+r.Intn(8))...)
+			}
+			if len(b) == 0 {
+				break
+			}
+		}
+		got, unknown, err = DeserializeSCTList(b)
+		if err != nil {
+			continue
+		}
+		// Anything which parsed must serialize back to the same bytes.
+		if len(unknown) == 0 {
+			reserialized, err := SerializeSCTList(got)
+			if err != nil {
+				t.Fatalf("SerializeSCTList() of parsed %x=%v", b, err)
+			}
+			if !bytes.Equal(reserialized, b) {
+				t.Fatalf("DeserializeSCTList(%x) round-tripped to %x", b, reserialized)
+			}
+		}
+	}
+}
+
 func TestDeserializeSCT(t *testing.T) {
 	sct, err := DeserializeSCT(bytes.NewReader(mustDehex(t, defaultSCTHexString)))
 	if err != nil {
@@ -561,3 +694,318 @@ func TestJSONMerkleTreeLeaf(t *testing.T) {
 		t.Errorf("CreateJSONMerkleTreeLeaf(): got\n%x, want\n%x", b.Bytes(), leafBytes)
 	}
 }
+
+func TestCreateXJSONMerkleTreeLeaf(t *testing.T) {
+	data := map[string]interface{}{
+		"url":  "https://example.com/a,b",
+		"note": "key: value, {braces}",
+		"n":    []int{1, 2},
+	}
+	leaf, hash, err := CreateXJSONMerkleTreeLeaf(data, 1469664866615)
+	if err != nil {
+		t.Fatalf("CreateXJSONMerkleTreeLeaf()=nil,_,%v", err)
+	}
+	want := `{ "data": { "n": [ 1, 2 ], "note": "key: value, {braces}", "url": "https:\/\/example.com\/a,b" } }`
+	if got := string(leaf.TimestampedEntry.JSONData); got != want {
+		t.Errorf("CreateXJSONMerkleTreeLeaf().JSONData=%s; want %s", got, want)
+	}
+	b := bytes.NewBuffer([]byte{0x00})
+	if err := SerializeMerkleTreeLeaf(b, leaf); err != nil {
+		t.Fatalf("SerializeMerkleTreeLeaf()=%v", err)
+	}
+	if wantHash := sha256.Sum256(b.Bytes()); hash != SHA256Hash(wantHash) {
+		t.Errorf("CreateXJSONMerkleTreeLeaf() hash=%x; want %x", hash, wantHash)
+	}
+	if err := VerifyXJSONMerkleTreeLeaf(leaf, data); err != nil {
+		t.Errorf("VerifyXJSONMerkleTreeLeaf()=%v; want nil", err)
+	}
+
+	if _, _, err := CreateXJSONMerkleTreeLeaf(make(chan int), 1); err == nil {
+		t.Error("CreateXJSONMerkleTreeLeaf(chan)=_,_,nil; want error")
+	}
+	if leaf := CreateJSONMerkleTreeLeaf(make(chan int), 1); leaf != nil {
+		t.Errorf("CreateJSONMerkleTreeLeaf(chan)=%v; want nil", leaf)
+	}
+}
+
+func TestCreateJSONMerkleTreeLeafKeepsFieldOrder(t *testing.T) {
+	leaf := CreateJSONMerkleTreeLeaf(struct{ B, A int }{1, 2}, 1)
+	if leaf == nil {
+		t.Fatal("CreateJSONMerkleTreeLeaf()=nil")
+	}
+	want := `{ "data": { "B": 1, "A": 2 } }`
+	if got := string(leaf.TimestampedEntry.JSONData); got != want {
+		t.Errorf("CreateJSONMerkleTreeLeaf().JSONData=%s; want %s", got, want)
+	}
+}
+
+func TestVerifyXJSONMerkleTreeLeaf(t *testing.T) {
+	data := map[string]interface{}{"a": "x:y", "b": 1}
+	xjsonLeaf := func(jsonData string) *MerkleTreeLeaf {
+		return &MerkleTreeLeaf{
+			Version:  V1,
+			LeafType: TimestampedEntryLeafType,
+			TimestampedEntry: TimestampedEntry{
+				EntryType: XJSONLogEntryType,
+				JSONData:  []byte(jsonData),
+			},
+		}
+	}
+	var tests = []struct {
+		desc    string
+		leaf    *MerkleTreeLeaf
+		wantErr bool
+	}{
+		{desc: "canonical", leaf: xjsonLeaf(`{ "data": { "a": "x:y", "b": 1 } }`)},
+		{desc: "reformatted", leaf: xjsonLeaf(`{"data":{"b":1.0,"a":"x:y"}}`)},
+		{desc: "different value", leaf: xjsonLeaf(`{ "data": { "a": "x: y", "b": 1 } }`), wantErr: true},
+		{desc: "extra key", leaf: xjsonLeaf(`{ "data": { "a": "x:y", "b": 1, "c": null } }`), wantErr: true},
+		{desc: "invalid JSON", leaf: xjsonLeaf(`{ "data": `), wantErr: true},
+		{desc: "X.509 leaf", leaf: CreateX509MerkleTreeLeaf([]byte{1}, 1), wantErr: true},
+	}
+	for _, test := range tests {
+		err := VerifyXJSONMerkleTreeLeaf(test.leaf, data)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("%s: VerifyXJSONMerkleTreeLeaf()=%v; want error %v", test.desc, err, test.wantErr)
+		}
+	}
+}
+
+func TestChainArrays(t *testing.T) {
+	chain := []ASN1Cert{ASN1Cert("issuer"), ASN1Cert("root")}
+	x509Data, err := MarshalX509ChainArray(chain)
+	if err != nil {
+		t.Fatalf("MarshalX509ChainArray()=%v", err)
+	}
+	if want := marshalChain(t, chain[0], chain[1]); !bytes.Equal(x509Data, want) {
+		t.Errorf("MarshalX509ChainArray()=%x, want %x", x509Data, want)
+	}
+	if got, err := UnmarshalX509ChainArray(x509Data); err != nil || !reflect.DeepEqual(got, chain) {
+		t.Errorf("UnmarshalX509ChainArray()=%q,%v, want %q,nil", got, err, chain)
+	}
+	if got, err := UnmarshalX509ChainArray(marshalChain(t)); err != nil || len(got) != 0 {
+		t.Errorf("UnmarshalX509ChainArray(empty)=%q,%v, want [],nil", got, err)
+	}
+
+	preChain := append([]ASN1Cert{ASN1Cert("precert")}, chain...)
+	precertData, err := MarshalPrecertChainArray(preChain)
+	if err != nil {
+		t.Fatalf("MarshalPrecertChainArray()=%v", err)
+	}
+	if want := append(dh("000007"), append([]byte("precert"), x509Data...)...); !bytes.Equal(precertData, want) {
+		t.Errorf("MarshalPrecertChainArray()=%x, want %x", precertData, want)
+	}
+	if got, err := UnmarshalPrecertChainArray(precertData); err != nil || !reflect.DeepEqual(got, preChain) {
+		t.Errorf("UnmarshalPrecertChainArray()=%q,%v, want %q,nil", got, err, preChain)
+	}
+
+	if _, err := MarshalPrecertChainArray(nil); err == nil {
+		t.Error("MarshalPrecertChainArray(nil) succeeded, want error")
+	}
+	if _, err := MarshalX509ChainArray([]ASN1Cert{{}}); err == nil {
+		t.Error("MarshalX509ChainArray() with empty certificate succeeded, want error")
+	}
+	for _, data := range [][]byte{
+		append(x509Data, 0),
+		marshalChain(t, []byte{}),
+		x509Data[:len(x509Data)-1],
+	} {
+		if got, err := UnmarshalX509ChainArray(data); err == nil {
+			t.Errorf("UnmarshalX509ChainArray(%x)=%q, want error", data, got)
+		}
+	}
+	for _, data := range [][]byte{
+		append(precertData, 0),
+		x509Data,
+		dh("000000000000"),
+	} {
+		if got, err := UnmarshalPrecertChainArray(data); err == nil {
+			t.Errorf("UnmarshalPrecertChainArray(%x)=%q, want error", data, got)
+		}
+	}
+}
+
+// marshalChain returns the TLS encoding of |certs| as a list of ASN.1Certs.
+func marshalChain(t *testing.T, certs ...[]byte) []byte {
+	var list bytes.Buffer
+	for _, c := range certs {
+		if err := writeVarBytes(&list, c, CertificateLengthBytes); err != nil {
+			t.Fatalf("writeVarBytes()=%v", err)
+		}
+	}
+	var b bytes.Buffer
+	if err := writeVarBytes(&b, list.Bytes(), CertificateChainLengthBytes); err != nil {
+		t.Fatalf("writeVarBytes()=%v", err)
+	}
+	return b.Bytes()
+}
+
+func serializeLeaf(t *testing.T, leaf *MerkleTreeLeaf) []byte {
+	var b bytes.Buffer
+	if err := SerializeMerkleTreeLeaf(&b, leaf); err != nil {
+		t.Fatalf("SerializeMerkleTreeLeaf()=%v", err)
+	}
+	return b.Bytes()
+}
+
+func TestLogEntryFromLeaf(t *testing.T) {
+	cert := readTestCert(t, "test-cert.pem")
+	ca := readTestCert(t, "ca-cert.pem")
+	entry, err := LogEntryFromLeaf(7, serializeLeaf(t, CreateX509MerkleTreeLeaf(cert.Raw, 1234)), marshalChain(t, ca.Raw))
+	if err != nil {
+		t.Fatalf("LogEntryFromLeaf(X509)=%v", err)
+	}
+	if entry.Index != 7 || entry.Leaf.TimestampedEntry.Timestamp != 1234 {
+		t.Errorf("LogEntryFromLeaf(X509) has index %d and timestamp %d, want 7 and 1234", entry.Index, entry.Leaf.TimestampedEntry.Timestamp)
+	}
+	if entry.X509Cert == nil || !entry.X509Cert.Equal(cert) {
+		t.Errorf("LogEntryFromLeaf(X509) has X509Cert %v, want test-cert", entry.X509Cert)
+	}
+	if len(entry.Chain) != 1 || !bytes.Equal(entry.Chain[0], ca.Raw) {
+		t.Errorf("LogEntryFromLeaf(X509) has chain of %d certs, want [ca-cert]", len(entry.Chain))
+	}
+
+	precert := readTestCert(t, "test-embedded-pre-cert.pem")
+	tbs, err := x509.BuildPrecertTBS(precert.RawTBSCertificate, nil)
+	if err != nil {
+		t.Fatalf("BuildPrecertTBS()=%v", err)
+	}
+	leaf := &MerkleTreeLeaf{
+		Version:  V1,
+		LeafType: TimestampedEntryLeafType,
+		TimestampedEntry: TimestampedEntry{
+			Timestamp: 5678,
+			EntryType: PrecertLogEntryType,
+			PrecertEntry: PreCert{
+				IssuerKeyHash:  sha256.Sum256(ca.RawSubjectPublicKeyInfo),
+				TBSCertificate: tbs,
+			},
+		},
+	}
+	var extra bytes.Buffer
+	writeVarBytes(&extra, precert.Raw, CertificateLengthBytes)
+	extra.Write(marshalChain(t, ca.Raw))
+	entry, err = LogEntryFromLeaf(8, serializeLeaf(t, leaf), extra.Bytes())
+	if err != nil {
+		t.Fatalf("LogEntryFromLeaf(Precert)=%v", err)
+	}
+	if entry.Precert == nil {
+		t.Fatal("LogEntryFromLeaf(Precert) has no Precert")
+	}
+	if !bytes.Equal(entry.Precert.Raw, precert.Raw) {
+		t.Error("LogEntryFromLeaf(Precert) has wrong Precert.Raw")
+	}
+	if entry.Precert.IssuerKeyHash != leaf.TimestampedEntry.PrecertEntry.IssuerKeyHash {
+		t.Error("LogEntryFromLeaf(Precert) has wrong Precert.IssuerKeyHash")
+	}
+	if got, want := entry.Precert.TBSCertificate.Subject.CommonName, precert.Subject.CommonName; got != want {
+		t.Errorf("LogEntryFromLeaf(Precert) has subject CN %q, want %q", got, want)
+	}
+	if entry.Precert.TBSCertificate.IsPrecertificate() {
+		t.Error("LogEntryFromLeaf(Precert) TBSCertificate still has the poison extension")
+	}
+	if len(entry.Chain) != 2 || !bytes.Equal(entry.Chain[1], ca.Raw) {
+		t.Errorf("LogEntryFromLeaf(Precert) has chain of %d certs, want [precert, ca-cert]", len(entry.Chain))
+	}
+
+	jsonLeaf := CreateJSONMerkleTreeLeaf("data", 91011)
+	entry, err = LogEntryFromLeaf(9, serializeLeaf(t, jsonLeaf), nil)
+	if err != nil {
+		t.Fatalf("LogEntryFromLeaf(XJSON)=%v", err)
+	}
+	if !bytes.Equal(entry.JSONData, jsonLeaf.TimestampedEntry.JSONData) {
+		t.Errorf("LogEntryFromLeaf(XJSON) has JSONData %q, want %q", entry.JSONData, jsonLeaf.TimestampedEntry.JSONData)
+	}
+}
+
+func TestLogEntryFromLeafErrors(t *testing.T) {
+	ca := readTestCert(t, "ca-cert.pem")
+	leaf := serializeLeaf(t, CreateX509MerkleTreeLeaf(ca.Raw, 1234))
+	for _, test := range []struct {
+		desc      string
+		leaf      []byte
+		extraData []byte
+	}{
+		{"truncated leaf", leaf[:len(leaf)-1], marshalChain(t)},
+		{"trailing data after leaf", append(leaf, 0), marshalChain(t)},
+		{"truncated extra data", leaf, marshalChain(t, ca.Raw)[:10]},
+		{"precert without precert", serializeLeaf(t, &MerkleTreeLeaf{
+			TimestampedEntry: TimestampedEntry{EntryType: PrecertLogEntryType, PrecertEntry: PreCert{TBSCertificate: []byte{1}}},
+		}), nil},
+	} {
+		if _, err := RawLogEntryFromLeaf(0, test.leaf, test.extraData); err == nil {
+			t.Errorf("%s: RawLogEntryFromLeaf() succeeded, want error", test.desc)
+		}
+		if entry, err := LogEntryFromLeaf(0, test.leaf, test.extraData); err == nil || entry != nil {
+			t.Errorf("%s: LogEntryFromLeaf()=%v, %v; want nil, error", test.desc, entry, err)
+		}
+	}
+
+	// A well-formed entry whose certificate can't be parsed.
+	garbage := serializeLeaf(t, CreateX509MerkleTreeLeaf([]byte("not a certificate"), 1234))
+	entry, err := RawLogEntryFromLeaf(0, garbage, marshalChain(t))
+	if err != nil {
+		t.Fatalf("RawLogEntryFromLeaf() of unparsable certificate=%v", err)
+	}
+	if err := entry.ParseCertificate(); err == nil || entry.X509Cert != nil {
+		t.Errorf("ParseCertificate() of unparsable certificate=%v with X509Cert %v, want error and nil", err, entry.X509Cert)
+	}
+	if entry, err := LogEntryFromLeaf(0, garbage, marshalChain(t)); err == nil || entry != nil {
+		t.Errorf("LogEntryFromLeaf() of unparsable certificate=%v, %v; want nil, error", entry, err)
+	}
+}
+
+func TestCreatePrecertMerkleTreeLeaf(t *testing.T) {
+	verifiers := testLogVerifiers(t)
+	for _, test := range []struct {
+		proof string
+		chain []string
+	}{
+		{"test-embedded-pre-cert.proof", []string{"test-embedded-pre-cert.pem", "ca-cert.pem"}},
+		{"test-embedded-with-preca-pre-cert.proof", []string{"test-embedded-with-preca-pre-cert.pem", "ca-pre-cert.pem", "ca-cert.pem"}},
+		{"test-embedded-with-intermediate-pre-cert.proof", []string{"test-embedded-with-intermediate-pre-cert.pem", "intermediate-cert.pem", "ca-cert.pem"}},
+		{"test-embedded-with-intermediate-preca-pre-cert.proof", []string{"test-embedded-with-intermediate-preca-pre-cert.pem", "intermediate-pre-cert.pem", "intermediate-cert.pem", "ca-cert.pem"}},
+	} {
+		sct, err := DeserializeSCT(bytes.NewReader(testonly.ReadFile(t, test.proof)))
+		if err != nil {
+			t.Fatalf("%s: DeserializeSCT()=%v", test.proof, err)
+		}
+		var chain []ASN1Cert
+		for _, name := range test.chain {
+			chain = append(chain, readTestCert(t, name).Raw)
+		}
+		leaf, hash, err := CreatePrecertMerkleTreeLeaf(chain, sct.Timestamp)
+		if err != nil {
+			t.Errorf("%s: CreatePrecertMerkleTreeLeaf()=%v", test.proof, err)
+			continue
+		}
+		// The log's SCT signature covers the same data as the leaf.
+		leaf.TimestampedEntry.Extensions = sct.Extensions
+		if err := verifiers[sct.LogID].VerifySCTSignature(*sct, LogEntry{Leaf: *leaf}); err != nil {
+			t.Errorf("%s: VerifySCTSignature() over leaf=%v", test.proof, err)
+		}
+		leaf.TimestampedEntry.Extensions = nil
+		want := sha256.Sum256(append([]byte{0}, serializeLeaf(t, leaf)...))
+		if hash != want {
+			t.Errorf("%s: CreatePrecertMerkleTreeLeaf() leaf hash=%x, want %x", test.proof, hash, want)
+		}
+	}
+}
+
+func TestCreatePrecertMerkleTreeLeafErrors(t *testing.T) {
+	precert := readTestCert(t, "test-embedded-with-preca-pre-cert.pem").Raw
+	for _, test := range []struct {
+		desc  string
+		chain []ASN1Cert
+	}{
+		{"no issuer", []ASN1Cert{precert}},
+		{"not a precertificate", []ASN1Cert{readTestCert(t, "test-cert.pem").Raw, readTestCert(t, "ca-cert.pem").Raw}},
+		{"unparsable precertificate", []ASN1Cert{[]byte("garbage"), readTestCert(t, "ca-cert.pem").Raw}},
+		{"unparsable issuer", []ASN1Cert{precert, []byte("garbage")}},
+		{"no issuer for Precertificate Signing Certificate", []ASN1Cert{precert, readTestCert(t, "ca-pre-cert.pem").Raw}},
+	} {
+		if _, _, err := CreatePrecertMerkleTreeLeaf(test.chain, 1234); err == nil {
+			t.Errorf("%s: CreatePrecertMerkleTreeLeaf() succeeded, want error", test.desc)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+)
+
+// httpError is returned by handlers to send a non-200 response.
+type httpError struct {
+	status int
+	err    error
+}
+
+func badRequest(format string, args ...interface{}) *httpError {
+	return &httpError{http.StatusBadRequest, fmt.Errorf(format, args...)}
+}
+
+func internalError(err error) *httpError {
+	return &httpError{http.StatusInternalServerError, err}
+}
+
+type handlerFunc func(s *Server, r *http.Request) (interface{}, *httpError)
+
+// Handler returns an http.Handler which serves the log's /ct/v1/* endpoints.
+func (s *Server) Handler() http.Handler {
+	handlers := map[string]struct {
+		method string
+		fn     handlerFunc
+	}{
+		client.AddChainPath:          {"POST", handleAddChain},
+		client.AddPreChainPath:       {"POST", handleAddPreChain},
+		client.AddJSONPath:           {"POST", handleAddJSON},
+		client.GetSTHPath:            {"GET", handleGetSTH},
+		client.GetEntriesPath:        {"GET", handleGetEntries},
+		client.GetProofByHashPath:    {"GET", handleGetProofByHash},
+		client.GetSTHConsistencyPath: {"GET", handleGetSTHConsistency},
+		client.GetRootsPath:          {"GET", handleGetRoots},
+		client.GetEntryAndProofPath:  {"GET", handleGetEntryAndProof},
+	}
+	mux := http.NewServeMux()
+	for path, h := range handlers {
+		h := h
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != h.method {
+				w.Header().Set("Allow", h.method)
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			rsp, httpErr := h.fn(s, r)
+			if httpErr != nil {
+				http.Error(w, httpErr.err.Error(), httpErr.status)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(rsp)
+		})
+	}
+	return mux
+}
+
+func sctResponse(sct *ct.SignedCertificateTimestamp) (interface{}, *httpError) {
+	sig, err := ct.MarshalDigitallySigned(sct.Signature)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	return &client.AddChainResponse{
+		SCTVersion: sct.SCTVersion,
+		ID:         sct.LogID[:],
+		Timestamp:  sct.Timestamp,
+		Extensions: base64.StdEncoding.EncodeToString(sct.Extensions),
+		Signature:  sig,
+	}, nil
+}
+
+func parseChain(r *http.Request) ([]ct.ASN1Cert, *httpError) {
+	var req client.AddChainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, badRequest("failed to parse request: %v", err)
+	}
+	chain := make([]ct.ASN1Cert, len(req.Chain))
+	for i, c := range req.Chain {
+		chain[i] = c
+	}
+	return chain, nil
+}
+
+func handleAddChain(s *Server, r *http.Request) (interface{}, *httpError) {
+	chain, httpErr := parseChain(r)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+	sct, err := s.AddChain(chain)
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	return sctResponse(sct)
+}
+
+func handleAddPreChain(s *Server, r *http.Request) (interface{}, *httpError) {
+	chain, httpErr := parseChain(r)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+	sct, err := s.AddPreChain(chain)
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	return sctResponse(sct)
+}
+
+func handleAddJSON(s *Server, r *http.Request) (interface{}, *httpError) {
+	var req client.AddJSONRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, badRequest("failed to parse request: %v", err)
+	}
+	sct, err := s.AddJSON(req.Data)
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	return sctResponse(sct)
+}
+
+func handleGetSTH(s *Server, r *http.Request) (interface{}, *httpError) {
+	sth, err := s.storage.LatestSTH()
+	if err != nil {
+		return nil, internalError(err)
+	}
+	sig, err := ct.MarshalDigitallySigned(sth.TreeHeadSignature)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	return &client.GetSTHResponse{
+		TreeSize:          sth.TreeSize,
+		Timestamp:         sth.Timestamp,
+		SHA256RootHash:    sth.SHA256RootHash[:],
+		TreeHeadSignature: sig,
+	}, nil
+}
+
+// uintParam parses the query parameter |name| as an unsigned integer.
+func uintParam(r *http.Request, name string) (uint64, *httpError) {
+	v, err := strconv.ParseUint(r.URL.Query().Get(name), 10, 63)
+	if err != nil {
+		return 0, badRequest("invalid %s parameter: %v", name, err)
+	}
+	return v, nil
+}
+
+func handleGetEntries(s *Server, r *http.Request) (interface{}, *httpError) {
+	start, httpErr := uintParam(r, "start")
+	if httpErr != nil {
+		return nil, httpErr
+	}
+	end, httpErr := uintParam(r, "end")
+	if httpErr != nil {
+		return nil, httpErr
+	}
+	if end < start {
+		return nil, badRequest("start %d > end %d", start, end)
+	}
+	if end-start >= uint64(s.maxGetEntries) {
+		end = start + uint64(s.maxGetEntries) - 1
+	}
+	// Only serve entries covered by the latest STH, so that clients can
+	// always verify the entries they're given.
+	sth, err := s.storage.LatestSTH()
+	if err != nil {
+		return nil, internalError(err)
+	}
+	if start >= sth.TreeSize {
+		return nil, badRequest("start %d beyond tree of size %d", start, sth.TreeSize)
+	}
+	if end >= sth.TreeSize {
+		end = sth.TreeSize - 1
+	}
+	entries, err := s.storage.GetEntries(int64(start), int64(end))
+	if err != nil {
+		return nil, internalError(err)
+	}
+	rsp := client.GetEntriesResponse{Entries: []client.LeafEntry{}}
+	for _, e := range entries {
+		rsp.Entries = append(rsp.Entries, client.LeafEntry{LeafInput: e.LeafInput, ExtraData: e.ExtraData})
+	}
+	return &rsp, nil
+}
+
+func handleGetProofByHash(s *Server, r *http.Request) (interface{}, *httpError) {
+	hash, err := base64.StdEncoding.DecodeString(r.URL.Query().Get("hash"))
+	if err != nil {
+		return nil, badRequest("invalid hash parameter: %v", err)
+	}
+	treeSize, httpErr := uintParam(r, "tree_size")
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	s.treeMu.Lock()
+	defer s.treeMu.Unlock()
+	if treeSize > s.tree.LeafCount() {
+		return nil, badRequest("tree_size %d beyond tree of size %d", treeSize, s.tree.LeafCount())
+	}
+	index, ok := s.byLeafHash[string(hash)]
+	if !ok || uint64(index) >= treeSize {
+		return nil, badRequest("hash not found in tree of size %d", treeSize)
+	}
+	path, err := s.tree.PathToRootAtSnapshot(uint64(index+1), treeSize)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	return &client.GetProofByHashResponse{LeafIndex: index, AuditPath: nonNil(path)}, nil
+}
+
+func handleGetSTHConsistency(s *Server, r *http.Request) (interface{}, *httpError) {
+	first, httpErr := uintParam(r, "first")
+	if httpErr != nil {
+		return nil, httpErr
+	}
+	second, httpErr := uintParam(r, "second")
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	s.treeMu.Lock()
+	defer s.treeMu.Unlock()
+	if first == 0 || first > second || second > s.tree.LeafCount() {
+		return nil, badRequest("invalid range [%d, %d] for tree of size %d", first, second, s.tree.LeafCount())
+	}
+	var proof [][]byte
+	if first < second {
+		var err error
+		if proof, err = s.tree.SnapshotConsistency(first, second); err != nil {
+			return nil, internalError(err)
+		}
+	}
+	return &client.GetSTHConsistencyResponse{Consistency: nonNil(proof)}, nil
+}
+
+func handleGetRoots(s *Server, r *http.Request) (interface{}, *httpError) {
+	rsp := client.GetRootsResponse{Certificates: [][]byte{}}
+	for _, root := range s.roots {
+		rsp.Certificates = append(rsp.Certificates, root.Raw)
+	}
+	return &rsp, nil
+}
+
+func handleGetEntryAndProof(s *Server, r *http.Request) (interface{}, *httpError) {
+	index, httpErr := uintParam(r, "leaf_index")
+	if httpErr != nil {
+		return nil, httpErr
+	}
+	treeSize, httpErr := uintParam(r, "tree_size")
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	s.treeMu.Lock()
+	if index >= treeSize || treeSize > s.tree.LeafCount() {
+		s.treeMu.Unlock()
+		return nil, badRequest("invalid leaf_index %d for tree_size %d", index, treeSize)
+	}
+	path, err := s.tree.PathToRootAtSnapshot(index+1, treeSize)
+	s.treeMu.Unlock()
+	if err != nil {
+		return nil, internalError(err)
+	}
+	entries, err := s.storage.GetEntries(int64(index), int64(index))
+	if err != nil {
+		return nil, internalError(err)
+	}
+	e := entries[0]
+	return &client.GetEntryAndProofResponse{LeafInput: e.LeafInput, ExtraData: e.ExtraData, AuditPath: nonNil(path)}, nil
+}
+
+// nonNil returns |hashes|, or an empty slice if it is nil, so that it is
+// encoded as an empty JSON array rather than null.
+func nonNil(hashes [][]byte) [][]byte {
+	if hashes == nil {
+		return [][]byte{}
+	}
+	return hashes
+}
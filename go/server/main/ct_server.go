@@ -0,0 +1,137 @@
+package main
+
+import (
+	"crypto"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/certificate-transparency/go/server"
+	"github.com/google/certificate-transparency/go/x509"
+	"golang.org/x/net/context"
+)
+
+var listenAddress = flag.String("listen", ":6962", "Listen address:port for HTTP server.")
+var privateKey = flag.String("private_key", "", "File containing the log's private key in PEM format.")
+var rootsFile = flag.String("roots", "", "File containing the PEM encoded root certificates which the log accepts chains to.")
+var dbPath = flag.String("database", "", "Path to the SQLite3 database holding the log. If empty, the log is kept in memory and lost on exit.")
+var sequenceInterval = flag.Duration("sequence_interval", 10*time.Second, "Interval at which pending entries are integrated into the tree.")
+var batchSize = flag.Int("batch_size", 1000, "Maximum number of entries integrated each time the sequencer runs.")
+var maxGetEntries = flag.Int64("max_get_entries", 1000, "Maximum number of entries returned by each get-entries request.")
+
+// loadPrivateKey reads the first private key from the PEM file |path|,
+// skipping any other blocks such as EC PARAMETERS.
+func loadPrivateKey(path string) (crypto.Signer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no private key found in %s", path)
+		}
+		var key interface{}
+		switch block.Type {
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s in %s: %v", block.Type, path, err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported key type %T in %s", key, path)
+		}
+		return signer, nil
+	}
+}
+
+// loadRoots reads all of the certificates from the PEM file |path|.
+func loadRoots(path string) ([]*x509.Certificate, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var roots []*x509.Certificate
+	for len(data) > 0 {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if x509.IsFatal(err) {
+			return nil, fmt.Errorf("failed to parse certificate in %s: %v", path, err)
+		}
+		roots = append(roots, cert)
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return roots, nil
+}
+
+func openStorage() (server.Storage, error) {
+	if len(*dbPath) == 0 {
+		log.Print("No --database given, keeping the log in memory")
+		return server.NewMemoryStorage(), nil
+	}
+	return server.OpenSQLiteStorage(*dbPath)
+}
+
+func main() {
+	flag.Parse()
+	if len(*privateKey) == 0 || len(*rootsFile) == 0 {
+		log.Fatal("--private_key and --roots are required")
+	}
+	key, err := loadPrivateKey(*privateKey)
+	if err != nil {
+		log.Fatalf("Failed to load private key: %v", err)
+	}
+	roots, err := loadRoots(*rootsFile)
+	if err != nil {
+		log.Fatalf("Failed to load roots: %v", err)
+	}
+	log.Printf("Loaded %d roots from %s", len(roots), *rootsFile)
+	storage, err := openStorage()
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer storage.Close()
+
+	s, err := server.New(server.Config{
+		Signer:        key,
+		Roots:         roots,
+		Storage:       storage,
+		MaxGetEntries: *maxGetEntries,
+		BatchSize:     *batchSize,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create log: %v", err)
+	}
+	log.Printf("Starting log with LogID %s", s.LogID().Base64String())
+	go s.RunSequencer(context.Background(), *sequenceInterval)
+
+	httpServer := &http.Server{
+		Addr:    *listenAddress,
+		Handler: s.Handler(),
+	}
+	if err := httpServer.ListenAndServe(); err != nil {
+		log.Printf("Error serving: %v", err)
+	}
+}
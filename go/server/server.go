@@ -0,0 +1,348 @@
+// Package server implements an RFC6962 Certificate Transparency log.
+//
+// A Server accepts submissions through the /ct/v1/add-chain and
+// /ct/v1/add-pre-chain endpoints, checking that they chain to one of its
+// configured roots, and through /ct/v1/add-json, and immediately returns an
+// SCT for each new entry. The entry is held in Storage as pending until the
+// sequencer assigns it a position in the log and integrates it into the Merkle
+// tree, after which it is served by get-entries and covered by the next STH.
+//
+// The Merkle tree itself is kept in memory, and rebuilt from Storage when a
+// Server is created.
+package server
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/merkletree"
+	"github.com/google/certificate-transparency/go/x509"
+	"golang.org/x/net/context"
+)
+
+const (
+	defaultMaxGetEntries = 1000
+	defaultBatchSize     = 1000
+)
+
+// Config configures a Server.
+type Config struct {
+	// Signer holds the log's private key, which must be RSA or ECDSA P-256.
+	Signer crypto.Signer
+	// Roots are the certificates which submitted chains must chain to.
+	Roots []*x509.Certificate
+	// Storage persists the log's entries and tree heads.
+	Storage Storage
+	// MaxGetEntries limits the number of entries returned by a single
+	// get-entries request. Defaults to 1000.
+	MaxGetEntries int64
+	// BatchSize is the maximum number of pending entries integrated into the
+	// tree by each run of the sequencer. Defaults to 1000.
+	BatchSize int
+	// Now returns the current time, used to timestamp SCTs and STHs.
+	// Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Server is an RFC6962 log.
+type Server struct {
+	signer        *ct.Signer
+	roots         []*x509.Certificate
+	rootPool      *x509.CertPool
+	storage       Storage
+	maxGetEntries int64
+	batchSize     int
+	now           func() time.Time
+
+	// seqMu serializes runs of the sequencer.
+	seqMu sync.Mutex
+
+	// treeMu guards tree and byLeafHash. The tree must be locked even for
+	// reads, as it computes internal nodes lazily.
+	treeMu     sync.Mutex
+	tree       *merkletree.InMemoryMerkleTree
+	byLeafHash map[string]int64 // leaf hash to index
+}
+
+func sha256Hash(b []byte) []byte {
+	h := sha256.Sum256(b)
+	return h[:]
+}
+
+// New returns a Server for the log held in |cfg.Storage|, rebuilding its
+// Merkle tree from the stored entries. If the storage holds no STH, New signs
+// one for the current tree.
+func New(cfg Config) (*Server, error) {
+	sgn, err := ct.NewSigner(cfg.Signer)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Storage == nil {
+		return nil, errors.New("no storage")
+	}
+	if len(cfg.Roots) == 0 {
+		return nil, errors.New("no roots")
+	}
+	s := &Server{
+		signer:        sgn,
+		roots:         cfg.Roots,
+		rootPool:      x509.NewCertPool(),
+		storage:       cfg.Storage,
+		maxGetEntries: cfg.MaxGetEntries,
+		batchSize:     cfg.BatchSize,
+		now:           cfg.Now,
+		tree:          merkletree.NewInMemoryMerkleTree(sha256Hash),
+		byLeafHash:    make(map[string]int64),
+	}
+	for _, root := range cfg.Roots {
+		s.rootPool.AddCert(root)
+	}
+	if s.maxGetEntries <= 0 {
+		s.maxGetEntries = defaultMaxGetEntries
+	}
+	if s.batchSize <= 0 {
+		s.batchSize = defaultBatchSize
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+	if err := s.loadTree(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// loadTree rebuilds the Merkle tree from the sequenced entries in storage,
+// and checks it against the latest stored STH.
+func (s *Server) loadTree() error {
+	size, err := s.storage.TreeSize()
+	if err != nil {
+		return fmt.Errorf("failed to get tree size: %v", err)
+	}
+	for start := int64(0); start < size; start += int64(s.batchSize) {
+		entries, err := s.storage.GetEntries(start, start+int64(s.batchSize)-1)
+		if err != nil {
+			return fmt.Errorf("failed to read entries from %d: %v", start, err)
+		}
+		s.integrate(entries)
+	}
+
+	sth, err := s.storage.LatestSTH()
+	if err != nil {
+		return fmt.Errorf("failed to get latest STH: %v", err)
+	}
+	if sth == nil {
+		return s.signAndStoreSTH()
+	}
+	if sth.LogID != s.signer.LogID() {
+		return fmt.Errorf("stored STH has LogID %s, but the log's key has LogID %s", sth.LogID.Base64String(), s.signer.LogID().Base64String())
+	}
+	root, err := s.tree.RootAtSnapshot(sth.TreeSize)
+	if err != nil {
+		return fmt.Errorf("stored STH has size %d, but only %d entries are sequenced", sth.TreeSize, size)
+	}
+	if !bytes.Equal(root, sth.SHA256RootHash[:]) {
+		return fmt.Errorf("stored STH has root %s, but the stored entries have root %x", sth.SHA256RootHash.Base64String(), root)
+	}
+	return nil
+}
+
+// integrate adds the sequenced |entries| to the Merkle tree.
+func (s *Server) integrate(entries []*Entry) {
+	s.treeMu.Lock()
+	defer s.treeMu.Unlock()
+	for _, e := range entries {
+		s.tree.AddLeaf(e.LeafInput)
+		hash, _ := s.tree.LeafHash(s.tree.LeafCount())
+		s.byLeafHash[string(hash)] = e.Index
+	}
+}
+
+// LogID returns the log's ID, the SHA-256 hash of its public key.
+func (s *Server) LogID() ct.SHA256Hash {
+	return s.signer.LogID()
+}
+
+func (s *Server) timestamp() uint64 {
+	return uint64(s.now().UnixNano() / int64(time.Millisecond))
+}
+
+// signAndStoreSTH signs and stores an STH for the current tree.
+func (s *Server) signAndStoreSTH() error {
+	s.treeMu.Lock()
+	size := s.tree.LeafCount()
+	root, err := s.tree.CurrentRoot()
+	s.treeMu.Unlock()
+	if err != nil {
+		return err
+	}
+	var rootHash ct.SHA256Hash
+	copy(rootHash[:], root)
+	sth, err := s.signer.CreateSTH(size, s.timestamp(), rootHash)
+	if err != nil {
+		return fmt.Errorf("failed to sign STH: %v", err)
+	}
+	return s.storage.AddSTH(sth)
+}
+
+// Sequence assigns positions in the log to up to Config.BatchSize pending
+// entries, integrates them into the Merkle tree, and stores a new STH.
+// A new STH is produced even if there were no pending entries, so that the
+// log's latest STH stays fresh.
+func (s *Server) Sequence() error {
+	s.seqMu.Lock()
+	defer s.seqMu.Unlock()
+	entries, err := s.storage.PendingEntries(s.batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get pending entries: %v", err)
+	}
+	if len(entries) > 0 {
+		if err := s.storage.SequenceEntries(entries); err != nil {
+			return fmt.Errorf("failed to sequence %d entries: %v", len(entries), err)
+		}
+		s.integrate(entries)
+	}
+	if err := s.signAndStoreSTH(); err != nil {
+		return fmt.Errorf("failed to store new STH: %v", err)
+	}
+	return nil
+}
+
+// RunSequencer calls Sequence every |interval| until |ctx| is done. Errors are
+// logged, and sequencing is retried at the next interval.
+func (s *Server) RunSequencer(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Sequence(); err != nil {
+				log.Printf("Sequencing failed: %v", err)
+			}
+		}
+	}
+}
+
+// AddChain adds the X509 certificate |chain| to the log, as the add-chain
+// endpoint does, and returns an SCT for it. Resubmitting a certificate
+// returns the original SCT.
+func (s *Server) AddChain(chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	verified, err := s.verifyChain(chain, false)
+	if err != nil {
+		return nil, err
+	}
+	leaf := ct.CreateX509MerkleTreeLeaf(verified[0], s.timestamp())
+	extraData, err := ct.MarshalX509ChainArray(verified[1:])
+	if err != nil {
+		return nil, err
+	}
+	return s.addEntry(leaf, verified[0], extraData)
+}
+
+// AddPreChain adds the precertificate |chain| to the log, as the
+// add-pre-chain endpoint does, and returns an SCT for it. Resubmitting a
+// precertificate returns the original SCT.
+func (s *Server) AddPreChain(chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	verified, err := s.verifyChain(chain, true)
+	if err != nil {
+		return nil, err
+	}
+	leaf, _, err := ct.CreatePrecertMerkleTreeLeaf(verified, s.timestamp())
+	if err != nil {
+		return nil, err
+	}
+	extraData, err := ct.MarshalPrecertChainArray(verified)
+	if err != nil {
+		return nil, err
+	}
+	return s.addEntry(leaf, verified[0], extraData)
+}
+
+// AddJSON adds |data| to the log, as the add-json endpoint does, and returns
+// an SCT for it. Resubmitting the same data returns the original SCT.
+func (s *Server) AddJSON(data interface{}) (*ct.SignedCertificateTimestamp, error) {
+	leaf := ct.CreateJSONMerkleTreeLeaf(data, s.timestamp())
+	if leaf == nil {
+		return nil, errors.New("failed to marshal JSON data")
+	}
+	return s.addEntry(leaf, leaf.TimestampedEntry.JSONData, nil)
+}
+
+// addEntry signs an SCT for |leaf| and stores it as a pending entry, unless
+// |cert| has already been submitted as the same type of entry, in which case
+// the original SCT is returned.
+func (s *Server) addEntry(leaf *ct.MerkleTreeLeaf, cert ct.ASN1Cert, extraData []byte) (*ct.SignedCertificateTimestamp, error) {
+	var leafInput bytes.Buffer
+	if err := ct.SerializeMerkleTreeLeaf(&leafInput, leaf); err != nil {
+		return nil, fmt.Errorf("failed to serialize MerkleTreeLeaf: %v", err)
+	}
+	sct, err := s.signer.CreateSCT(ct.LogEntry{Leaf: *leaf})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign SCT: %v", err)
+	}
+	identity := sha256.New()
+	identity.Write([]byte{byte(leaf.TimestampedEntry.EntryType >> 8), byte(leaf.TimestampedEntry.EntryType)})
+	identity.Write(cert)
+	e, err := s.storage.AddPendingEntry(&Entry{
+		IdentityHash: identity.Sum(nil),
+		LeafInput:    leafInput.Bytes(),
+		ExtraData:    extraData,
+		SCT:          *sct,
+		Index:        -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store entry: %v", err)
+	}
+	return &e.SCT, nil
+}
+
+// verifyChain checks that |chain| is a [pre-]certificate followed by
+// certificates which chain it to one of the log's roots, and returns the
+// verified chain, ending in the root.
+func (s *Server) verifyChain(chain []ct.ASN1Cert, precert bool) ([]ct.ASN1Cert, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("empty chain")
+	}
+	leaf, err := x509.ParseCertificate(chain[0])
+	if x509.IsFatal(err) {
+		return nil, fmt.Errorf("failed to parse certificate: %v", err)
+	}
+	if leaf.IsPrecertificate() != precert {
+		if precert {
+			return nil, errors.New("certificate is not a precertificate")
+		}
+		return nil, errors.New("precertificates must be submitted to add-pre-chain")
+	}
+	intermediates := x509.NewCertPool()
+	for i, der := range chain[1:] {
+		cert, err := x509.ParseCertificate(der)
+		if x509.IsFatal(err) {
+			return nil, fmt.Errorf("failed to parse certificate %d in chain: %v", i+1, err)
+		}
+		intermediates.AddCert(cert)
+	}
+	chains, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         s.rootPool,
+		Intermediates: intermediates,
+		// Logs accept expired certificates, and ones for any purpose.
+		DisableTimeChecks: true,
+		KeyUsages:         []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify chain: %v", err)
+	}
+	var verified []ct.ASN1Cert
+	for _, cert := range chains[0] {
+		verified = append(verified, cert.Raw)
+	}
+	return verified, nil
+}
@@ -0,0 +1,313 @@
+package server
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+	"github.com/google/certificate-transparency/go/client/verifiedfetch"
+	"github.com/google/certificate-transparency/go/merkletree"
+	"github.com/google/certificate-transparency/go/scanner"
+	"github.com/google/certificate-transparency/go/testonly"
+	ctx509 "github.com/google/certificate-transparency/go/x509"
+	"golang.org/x/net/context"
+)
+
+func testRoots(t *testing.T) []*ctx509.Certificate {
+	root, err := ctx509.ParseCertificate(testonly.ReadPEM(t, "ca-cert.pem"))
+	if ctx509.IsFatal(err) {
+		t.Fatalf("failed to parse root: %v", err)
+	}
+	return []*ctx509.Certificate{root}
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+type testLog struct {
+	server *Server
+	http   *httptest.Server
+	client *client.LogClient
+}
+
+// newTestLog starts a Server for |storage| with |key|, and returns it along
+// with a client for it which verifies signatures.
+func newTestLog(t *testing.T, key crypto.Signer, storage Storage) *testLog {
+	s, err := New(Config{Signer: key, Roots: testRoots(t), Storage: storage, MaxGetEntries: 2})
+	if err != nil {
+		t.Fatalf("New()=%v", err)
+	}
+	hs := httptest.NewServer(s.Handler())
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	c, err := client.NewWithPubKey(hs.URL, &http.Client{}, string(pubKey))
+	if err != nil {
+		hs.Close()
+		t.Fatalf("NewWithPubKey()=%v", err)
+	}
+	return &testLog{server: s, http: hs, client: c}
+}
+
+func (l *testLog) Close() {
+	l.http.Close()
+}
+
+func TestSubmitAndFetch(t *testing.T) {
+	l := newTestLog(t, newKey(t), NewMemoryStorage())
+	defer l.Close()
+	ctx := context.Background()
+	ca := testonly.ReadPEM(t, "ca-cert.pem")
+
+	sth0, err := l.client.GetSTHWithContext(ctx)
+	if err != nil {
+		t.Fatalf("GetSTH()=%v", err)
+	}
+	if sth0.TreeSize != 0 {
+		t.Errorf("new log has STH with size %d", sth0.TreeSize)
+	}
+
+	// The client verifies the SCTs.
+	sct, err := l.client.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-cert.pem"), ca})
+	if err != nil {
+		t.Fatalf("AddChain()=%v", err)
+	}
+	if again, err := l.client.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-cert.pem")}); err != nil || again.Timestamp != sct.Timestamp {
+		t.Errorf("resubmitting certificate gave %v, %v; want original SCT", again, err)
+	}
+	if _, err := l.client.AddPreChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-embedded-pre-cert.pem"), ca}); err != nil {
+		t.Fatalf("AddPreChain()=%v", err)
+	}
+	// A precertificate issued by a Precertificate Signing Certificate.
+	if _, err := l.client.AddPreChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-embedded-with-preca-pre-cert.pem"), testonly.ReadPEM(t, "ca-pre-cert.pem"), ca}); err != nil {
+		t.Fatalf("AddPreChain(Precertificate Signing Certificate)=%v", err)
+	}
+
+	// Nothing is visible until the sequencer runs.
+	if sth, err := l.client.GetSTHWithContext(ctx); err != nil || sth.TreeSize != 0 {
+		t.Fatalf("GetSTH() before sequencing = %v, %v", sth, err)
+	}
+	if err := l.server.Sequence(); err != nil {
+		t.Fatalf("Sequence()=%v", err)
+	}
+	sth1, err := l.client.GetSTHWithContext(ctx)
+	if err != nil {
+		t.Fatalf("GetSTH()=%v", err)
+	}
+	if sth1.TreeSize != 3 {
+		t.Fatalf("STH has TreeSize %d, want 3", sth1.TreeSize)
+	}
+
+	entries, err := verifiedfetch.NewFetcher(l.client, sth1).GetEntries(ctx, 0, 2)
+	if err != nil {
+		t.Fatalf("GetEntries()=%v", err)
+	}
+	// MaxGetEntries is 2.
+	if len(entries) != 2 {
+		t.Fatalf("GetEntries() returned %d entries, want 2", len(entries))
+	}
+	if len(entries[0].Chain) != 1 || string(entries[0].Chain[0]) != string(ca) {
+		t.Errorf("X509 entry has chain of length %d, want root", len(entries[0].Chain))
+	}
+	if entries[1].Leaf.TimestampedEntry.EntryType != ct.PrecertLogEntryType {
+		t.Errorf("entry 1 has type %v", entries[1].Leaf.TimestampedEntry.EntryType)
+	}
+
+	rsp, err := l.client.GetEntryAndProof(ctx, 2, sth1.TreeSize)
+	if err != nil {
+		t.Fatalf("GetEntryAndProof()=%v", err)
+	}
+	verifier := merkletree.NewMerkleVerifier(sha256Hash)
+	if err := verifier.VerifyInclusionProof(2, int64(sth1.TreeSize), rsp.AuditPath, sth1.SHA256RootHash[:], rsp.LeafInput); err != nil {
+		t.Errorf("VerifyInclusionProof()=%v", err)
+	}
+
+	if _, err := l.client.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-embedded-cert.pem")}); err != nil {
+		t.Fatalf("AddChain()=%v", err)
+	}
+	if err := l.server.Sequence(); err != nil {
+		t.Fatalf("Sequence()=%v", err)
+	}
+	sth2, err := l.client.GetSTHWithContext(ctx)
+	if err != nil {
+		t.Fatalf("GetSTH()=%v", err)
+	}
+	proof, err := l.client.GetSTHConsistency(ctx, sth1.TreeSize, sth2.TreeSize)
+	if err != nil {
+		t.Fatalf("GetSTHConsistency()=%v", err)
+	}
+	if err := verifier.VerifyConsistencyProof(int64(sth1.TreeSize), int64(sth2.TreeSize), sth1.SHA256RootHash[:], sth2.SHA256RootHash[:], proof); err != nil {
+		t.Errorf("VerifyConsistencyProof()=%v", err)
+	}
+
+	roots, err := l.client.GetAcceptedRoots(ctx)
+	if err != nil || len(roots) != 1 || string(roots[0].Raw) != string(ca) {
+		t.Errorf("GetAcceptedRoots()=%v, %v; want ca-cert.pem", roots, err)
+	}
+}
+
+func TestRejectsBadChains(t *testing.T) {
+	l := newTestLog(t, newKey(t), NewMemoryStorage())
+	defer l.Close()
+	tests := []struct {
+		desc    string
+		precert bool
+		chain   []string
+	}{
+		{"unknown root", false, []string{"google-cert.pem"}},
+		{"precert to add-chain", false, []string{"test-embedded-pre-cert.pem", "ca-cert.pem"}},
+		{"cert to add-pre-chain", true, []string{"test-cert.pem", "ca-cert.pem"}},
+		{"missing intermediate", false, []string{"test-intermediate-cert.pem", "ca-cert.pem"}},
+	}
+	for _, test := range tests {
+		var chain []ct.ASN1Cert
+		for _, name := range test.chain {
+			chain = append(chain, testonly.ReadPEM(t, name))
+		}
+		var err error
+		if test.precert {
+			_, err = l.client.AddPreChain(chain)
+		} else {
+			_, err = l.client.AddChain(chain)
+		}
+		if rspErr, ok := err.(client.RspError); !ok || rspErr.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: got %v, want RspError with status 400", test.desc, err)
+		}
+	}
+
+	// With the intermediate, the chain is accepted.
+	if _, err := l.client.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-intermediate-cert.pem"), testonly.ReadPEM(t, "intermediate-cert.pem")}); err != nil {
+		t.Errorf("AddChain(with intermediate)=%v", err)
+	}
+}
+
+func TestAddJSON(t *testing.T) {
+	l := newTestLog(t, newKey(t), NewMemoryStorage())
+	defer l.Close()
+	sct, err := l.client.AddJSON(map[string]string{"a": "b"})
+	if err != nil {
+		t.Fatalf("AddJSON()=%v", err)
+	}
+	if again, err := l.client.AddJSON(map[string]string{"a": "b"}); err != nil || again.Timestamp != sct.Timestamp {
+		t.Errorf("resubmitting data gave %v, %v; want original SCT", again, err)
+	}
+	if err := l.server.Sequence(); err != nil {
+		t.Fatalf("Sequence()=%v", err)
+	}
+	entries, err := l.client.GetEntries(0, 0)
+	if err != nil {
+		t.Fatalf("GetEntries()=%v", err)
+	}
+	if len(entries) != 1 || entries[0].Leaf.TimestampedEntry.EntryType != ct.XJSONLogEntryType {
+		t.Errorf("GetEntries() returned %v, want one XJSON entry", entries)
+	}
+}
+
+func TestReloadFromSQLite(t *testing.T) {
+	path := tempDBPath(t)
+	defer os.Remove(path)
+	key := newKey(t)
+
+	storage, err := OpenSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("OpenSQLiteStorage()=%v", err)
+	}
+	l := newTestLog(t, key, storage)
+	for _, name := range []string{"test-cert.pem", "test-embedded-cert.pem"} {
+		if _, err := l.client.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, name)}); err != nil {
+			t.Fatalf("AddChain(%s)=%v", name, err)
+		}
+	}
+	if err := l.server.Sequence(); err != nil {
+		t.Fatalf("Sequence()=%v", err)
+	}
+	// Leave an entry pending.
+	if _, err := l.client.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, "intermediate-cert.pem")}); err != nil {
+		t.Fatalf("AddChain()=%v", err)
+	}
+	sth1, err := l.client.GetSTH()
+	if err != nil {
+		t.Fatalf("GetSTH()=%v", err)
+	}
+	l.Close()
+	storage.Close()
+
+	if storage, err = OpenSQLiteStorage(path); err != nil {
+		t.Fatalf("OpenSQLiteStorage()=%v", err)
+	}
+	defer storage.Close()
+	if _, err := New(Config{Signer: newKey(t), Roots: testRoots(t), Storage: storage}); err == nil {
+		t.Error("New() accepted storage for a log with a different key")
+	}
+	l = newTestLog(t, key, storage)
+	defer l.Close()
+	if err := l.server.Sequence(); err != nil {
+		t.Fatalf("Sequence()=%v", err)
+	}
+	sth2, err := l.client.GetSTH()
+	if err != nil {
+		t.Fatalf("GetSTH()=%v", err)
+	}
+	if sth2.TreeSize != 3 {
+		t.Errorf("reloaded log has tree size %d, want 3", sth2.TreeSize)
+	}
+	proof, err := l.client.GetSTHConsistency(context.Background(), sth1.TreeSize, sth2.TreeSize)
+	if err != nil {
+		t.Fatalf("GetSTHConsistency()=%v", err)
+	}
+	if err := merkletree.NewMerkleVerifier(sha256Hash).VerifyConsistencyProof(int64(sth1.TreeSize), int64(sth2.TreeSize), sth1.SHA256RootHash[:], sth2.SHA256RootHash[:], proof); err != nil {
+		t.Errorf("VerifyConsistencyProof()=%v", err)
+	}
+}
+
+func TestScanner(t *testing.T) {
+	l := newTestLog(t, newKey(t), NewMemoryStorage())
+	defer l.Close()
+	ca := testonly.ReadPEM(t, "ca-cert.pem")
+	if _, err := l.client.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-cert.pem")}); err != nil {
+		t.Fatalf("AddChain()=%v", err)
+	}
+	if _, err := l.client.AddPreChain([]ct.ASN1Cert{testonly.ReadPEM(t, "test-embedded-pre-cert.pem"), ca}); err != nil {
+		t.Fatalf("AddPreChain()=%v", err)
+	}
+	if err := l.server.Sequence(); err != nil {
+		t.Fatalf("Sequence()=%v", err)
+	}
+
+	opts := scanner.DefaultScannerOptions()
+	opts.Quiet = true
+	var mu sync.Mutex
+	var certs, precerts int
+	err := scanner.NewScanner(l.client, *opts).Scan(func(*ct.LogEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		certs++
+	}, func(*ct.LogEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		precerts++
+	})
+	if err != nil {
+		t.Fatalf("Scan()=%v", err)
+	}
+	if certs != 1 || precerts != 1 {
+		t.Errorf("Scan() found %d certs and %d precerts, want 1 of each", certs, precerts)
+	}
+}
@@ -0,0 +1,288 @@
+package server
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	ct "github.com/google/certificate-transparency/go"
+	sqlite3 "github.com/mattn/go-sqlite3"
+)
+
+const logSchema = `
+        CREATE TABLE IF NOT EXISTS entries (
+                seq             INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+                identity_hash   BYTES NOT NULL UNIQUE,
+                leaf_input      BYTES NOT NULL,
+                extra_data      BYTES NOT NULL,
+                sct             BYTES NOT NULL,
+                leaf_index      INTEGER UNIQUE
+        );
+
+        CREATE TABLE IF NOT EXISTS sths (
+                sth_id      INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+                tree_size   INTEGER NOT NULL,
+                timestamp   INTEGER NOT NULL,
+                root_hash   BYTES NOT NULL,
+                signature   BYTES NOT NULL,
+                log_id      BYTES NOT NULL
+        );`
+
+const insertEntry = `INSERT INTO entries(identity_hash, leaf_input, extra_data, sct) VALUES ($1, $2, $3, $4);`
+const insertLogSTH = `INSERT INTO sths(tree_size, timestamp, root_hash, signature, log_id) VALUES ($1, $2, $3, $4, $5);`
+const deleteOldSTHs = `DELETE FROM sths WHERE sth_id < $1;`
+const updateLeafIndex = `UPDATE entries SET leaf_index = $1 WHERE identity_hash = $2 AND leaf_index IS NULL;`
+
+const selectEntryByIdentity = `SELECT identity_hash, leaf_input, extra_data, sct, leaf_index FROM entries WHERE identity_hash = $1;`
+const selectPendingEntries = `SELECT identity_hash, leaf_input, extra_data, sct, leaf_index FROM entries
+                                 WHERE leaf_index IS NULL ORDER BY seq LIMIT $1;`
+const selectSequencedEntries = `SELECT identity_hash, leaf_input, extra_data, sct, leaf_index FROM entries
+                                   WHERE leaf_index >= $1 AND leaf_index <= $2 ORDER BY leaf_index;`
+const selectTreeSize = `SELECT COUNT(*) FROM entries WHERE leaf_index IS NOT NULL;`
+const selectLatestSTH = `SELECT tree_size, timestamp, root_hash, signature, log_id FROM sths ORDER BY sth_id DESC LIMIT 1;`
+
+// SQLiteStorage is a Storage which persists the log in an SQLite3 database.
+type SQLiteStorage struct {
+	db                     *sql.DB
+	insertEntry            *sql.Stmt
+	insertSTH              *sql.Stmt
+	deleteOldSTHs          *sql.Stmt
+	updateLeafIndex        *sql.Stmt
+	selectEntryByIdentity  *sql.Stmt
+	selectPendingEntries   *sql.Stmt
+	selectSequencedEntries *sql.Stmt
+	selectTreeSize         *sql.Stmt
+	selectLatestSTH        *sql.Stmt
+}
+
+// OpenSQLiteStorage opens (creating if necessary) the SQLite3 database at
+// |dbPath| for use as log storage.
+func OpenSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
+	if len(dbPath) == 0 {
+		return nil, errors.New("attempting to open storage with an empty file name")
+	}
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite only allows one writer at a time, so serialize access rather
+	// than have concurrent transactions fail with SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(logSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	s := &SQLiteStorage{db: db}
+	for _, p := range []struct {
+		stmt **sql.Stmt
+		sql  string
+	}{
+		{&s.insertEntry, insertEntry},
+		{&s.insertSTH, insertLogSTH},
+		{&s.deleteOldSTHs, deleteOldSTHs},
+		{&s.updateLeafIndex, updateLeafIndex},
+		{&s.selectEntryByIdentity, selectEntryByIdentity},
+		{&s.selectPendingEntries, selectPendingEntries},
+		{&s.selectSequencedEntries, selectSequencedEntries},
+		{&s.selectTreeSize, selectTreeSize},
+		{&s.selectLatestSTH, selectLatestSTH}} {
+		if *p.stmt, err = db.Prepare(p.sql); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to prepare %q: %v", p.sql, err)
+		}
+	}
+	return s, nil
+}
+
+// Close implements Storage.
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
+
+// scanEntries reads all of the Entries from |rows|, which must have come from
+// one of the select*Entries statements.
+func scanEntries(rows *sql.Rows) ([]*Entry, error) {
+	defer rows.Close()
+	var entries []*Entry
+	for rows.Next() {
+		var e Entry
+		var sct []byte
+		var index sql.NullInt64
+		if err := rows.Scan(&e.IdentityHash, &e.LeafInput, &e.ExtraData, &sct, &index); err != nil {
+			return nil, err
+		}
+		s, err := ct.DeserializeSCT(bytes.NewReader(sct))
+		if err != nil {
+			return nil, fmt.Errorf("failed to deserialize stored SCT: %v", err)
+		}
+		e.SCT = *s
+		e.Index = -1
+		if index.Valid {
+			e.Index = index.Int64
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// AddPendingEntry implements Storage.
+func (s *SQLiteStorage) AddPendingEntry(e *Entry) (*Entry, error) {
+	sct, err := ct.SerializeSCT(e.SCT)
+	if err != nil {
+		return nil, err
+	}
+	extraData := e.ExtraData
+	if extraData == nil {
+		// The column is NOT NULL.
+		extraData = []byte{}
+	}
+	if _, err := s.insertEntry.Exec(e.IdentityHash, e.LeafInput, extraData, sct); err != nil {
+		if sqlErr, ok := err.(sqlite3.Error); !ok || sqlErr.Code != sqlite3.ErrConstraint {
+			return nil, err
+		}
+		// Already submitted; return the original entry.
+	}
+	rows, err := s.selectEntryByIdentity.Query(e.IdentityHash)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := scanEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) != 1 {
+		return nil, fmt.Errorf("found %d entries with identity hash %x", len(entries), e.IdentityHash)
+	}
+	return entries[0], nil
+}
+
+// PendingEntries implements Storage.
+func (s *SQLiteStorage) PendingEntries(limit int) ([]*Entry, error) {
+	rows, err := s.selectPendingEntries.Query(limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanEntries(rows)
+}
+
+// SequenceEntries implements Storage.
+func (s *SQLiteStorage) SequenceEntries(entries []*Entry) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	// If we return a non-nil error, then rollback the transaction.
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var size int64
+	if err := tx.Stmt(s.selectTreeSize).QueryRow().Scan(&size); err != nil {
+		return err
+	}
+	update := tx.Stmt(s.updateLeafIndex)
+	for i, e := range entries {
+		r, err := update.Exec(size+int64(i), e.IdentityHash)
+		if err != nil {
+			return err
+		}
+		if n, err := r.RowsAffected(); err != nil {
+			return err
+		} else if n != 1 {
+			return fmt.Errorf("entry with identity hash %x is not pending", e.IdentityHash)
+		}
+	}
+	for i, e := range entries {
+		e.Index = size + int64(i)
+	}
+	return nil
+}
+
+// TreeSize implements Storage.
+func (s *SQLiteStorage) TreeSize() (int64, error) {
+	var size int64
+	err := s.selectTreeSize.QueryRow().Scan(&size)
+	return size, err
+}
+
+// GetEntries implements Storage.
+func (s *SQLiteStorage) GetEntries(start, end int64) ([]*Entry, error) {
+	if start < 0 || start > end {
+		return nil, fmt.Errorf("invalid range [%d, %d]", start, end)
+	}
+	rows, err := s.selectSequencedEntries.Query(start, end)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := scanEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrEntryNotFound
+	}
+	return entries, nil
+}
+
+// AddSTH implements Storage. Only the latest STH is kept, so that the table
+// doesn't grow each time the sequencer refreshes the STH of an idle log.
+func (s *SQLiteStorage) AddSTH(sth *ct.SignedTreeHead) (err error) {
+	sig, err := ct.MarshalDigitallySigned(sth.TreeHeadSignature)
+	if err != nil {
+		return err
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	// If we return a non-nil error, then rollback the transaction.
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	r, err := tx.Stmt(s.insertSTH).Exec(int64(sth.TreeSize), int64(sth.Timestamp), sth.SHA256RootHash[:], sig, sth.LogID[:])
+	if err != nil {
+		return err
+	}
+	id, err := r.LastInsertId()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Stmt(s.deleteOldSTHs).Exec(id)
+	return err
+}
+
+// LatestSTH implements Storage.
+func (s *SQLiteStorage) LatestSTH() (*ct.SignedTreeHead, error) {
+	var treeSize, timestamp int64
+	var rootHash, sig, logID []byte
+	err := s.selectLatestSTH.QueryRow().Scan(&treeSize, &timestamp, &rootHash, &sig, &logID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ds, err := ct.UnmarshalDigitallySigned(bytes.NewReader(sig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stored STH signature: %v", err)
+	}
+	sth := &ct.SignedTreeHead{
+		Version:           ct.V1,
+		TreeSize:          uint64(treeSize),
+		Timestamp:         uint64(timestamp),
+		TreeHeadSignature: *ds,
+	}
+	copy(sth.SHA256RootHash[:], rootHash)
+	copy(sth.LogID[:], logID)
+	return sth, nil
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	ct "github.com/google/certificate-transparency/go"
+)
+
+// Entry is a single submission to the log.
+type Entry struct {
+	// IdentityHash identifies the submission for de-duplication: a second
+	// submission with the same IdentityHash gets the same SCT back rather
+	// than a new entry.
+	IdentityHash []byte
+	// LeafInput is the serialized MerkleTreeLeaf.
+	LeafInput []byte
+	// ExtraData is the extra_data returned for the entry by get-entries.
+	ExtraData []byte
+	// SCT is the SCT which was issued for the entry.
+	SCT ct.SignedCertificateTimestamp
+	// Index is the entry's position in the log, or -1 if it has not yet been
+	// sequenced.
+	Index int64
+}
+
+// ErrEntryNotFound is returned by Storage when asked for entries beyond the
+// end of the log.
+var ErrEntryNotFound = errors.New("entry not found")
+
+// Storage persists the entries and tree heads of a log.
+// Implementations must be safe for concurrent use.
+type Storage interface {
+	// AddPendingEntry records |e|, which is awaiting sequencing. If an entry
+	// with the same IdentityHash has already been added that entry is
+	// returned instead, and |e| is discarded.
+	AddPendingEntry(e *Entry) (*Entry, error)
+	// PendingEntries returns up to |limit| entries which have not yet been
+	// sequenced, in the order in which they were added.
+	PendingEntries(limit int) ([]*Entry, error)
+	// SequenceEntries assigns the next consecutive indexes in the log to
+	// |entries|, which must have been returned by PendingEntries, and sets
+	// their Index fields. Either all of the entries are sequenced or none of
+	// them are.
+	SequenceEntries(entries []*Entry) error
+	// TreeSize returns the number of sequenced entries.
+	TreeSize() (int64, error)
+	// GetEntries returns the sequenced entries in the range [|start|, |end|].
+	// Returns ErrEntryNotFound if |start| is beyond the last sequenced entry;
+	// otherwise the result is truncated to the sequenced entries.
+	GetEntries(start, end int64) ([]*Entry, error)
+	// AddSTH records |sth| as the latest tree head. Earlier tree heads need
+	// not be kept, as the sequencer replaces the STH on every run.
+	AddSTH(sth *ct.SignedTreeHead) error
+	// LatestSTH returns the most recently added tree head, or nil if there
+	// isn't one.
+	LatestSTH() (*ct.SignedTreeHead, error)
+	// Close releases any resources held by the storage.
+	Close() error
+}
+
+// MemoryStorage is a Storage which keeps everything in memory, and so loses
+// the log when the process exits. It is intended for tests and for logs which
+// don't need to outlive the process.
+type MemoryStorage struct {
+	mu         sync.Mutex
+	entries    []*Entry
+	pending    []*Entry
+	byIdentity map[string]*Entry
+	sth        *ct.SignedTreeHead
+}
+
+// NewMemoryStorage returns an empty MemoryStorage.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{byIdentity: make(map[string]*Entry)}
+}
+
+func copyEntry(e *Entry) *Entry {
+	c := *e
+	return &c
+}
+
+// AddPendingEntry implements Storage.
+func (s *MemoryStorage) AddPendingEntry(e *Entry) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.byIdentity[string(e.IdentityHash)]; ok {
+		return copyEntry(existing), nil
+	}
+	e = copyEntry(e)
+	e.Index = -1
+	s.byIdentity[string(e.IdentityHash)] = e
+	s.pending = append(s.pending, e)
+	return copyEntry(e), nil
+}
+
+// PendingEntries implements Storage.
+func (s *MemoryStorage) PendingEntries(limit int) ([]*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var entries []*Entry
+	for _, e := range s.pending {
+		if len(entries) >= limit {
+			break
+		}
+		entries = append(entries, copyEntry(e))
+	}
+	return entries, nil
+}
+
+// SequenceEntries implements Storage.
+func (s *MemoryStorage) SequenceEntries(entries []*Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(entries) > len(s.pending) {
+		return fmt.Errorf("asked to sequence %d entries, but only %d are pending", len(entries), len(s.pending))
+	}
+	for i, e := range entries {
+		if string(s.pending[i].IdentityHash) != string(e.IdentityHash) {
+			return fmt.Errorf("entry %d is not the next pending entry", i)
+		}
+	}
+	for i, e := range entries {
+		e.Index = int64(len(s.entries))
+		s.pending[i].Index = e.Index
+		s.entries = append(s.entries, s.pending[i])
+	}
+	s.pending = s.pending[len(entries):]
+	return nil
+}
+
+// TreeSize implements Storage.
+func (s *MemoryStorage) TreeSize() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int64(len(s.entries)), nil
+}
+
+// GetEntries implements Storage.
+func (s *MemoryStorage) GetEntries(start, end int64) ([]*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if start < 0 || start > end {
+		return nil, fmt.Errorf("invalid range [%d, %d]", start, end)
+	}
+	if start >= int64(len(s.entries)) {
+		return nil, ErrEntryNotFound
+	}
+	if end >= int64(len(s.entries)) {
+		end = int64(len(s.entries)) - 1
+	}
+	var entries []*Entry
+	for _, e := range s.entries[start : end+1] {
+		entries = append(entries, copyEntry(e))
+	}
+	return entries, nil
+}
+
+// AddSTH implements Storage.
+func (s *MemoryStorage) AddSTH(sth *ct.SignedTreeHead) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := *sth
+	s.sth = &c
+	return nil
+}
+
+// LatestSTH implements Storage.
+func (s *MemoryStorage) LatestSTH() (*ct.SignedTreeHead, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sth == nil {
+		return nil, nil
+	}
+	c := *s.sth
+	return &c, nil
+}
+
+// Close implements Storage.
+func (s *MemoryStorage) Close() error {
+	return nil
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	ct "github.com/google/certificate-transparency/go"
+)
+
+func tempDBPath(t *testing.T) string {
+	f, err := ioutil.TempFile("", "server_test")
+	if err != nil {
+		t.Fatalf("Failed to get a temporary file: %v", err)
+	}
+	f.Close()
+	// SQLite will create the database itself.
+	os.Remove(f.Name())
+	return f.Name()
+}
+
+// forEachStorage runs |fn| against each Storage implementation.
+func forEachStorage(t *testing.T, fn func(t *testing.T, s Storage)) {
+	fn(t, NewMemoryStorage())
+
+	path := tempDBPath(t)
+	defer os.Remove(path)
+	s, err := OpenSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("OpenSQLiteStorage()=%v", err)
+	}
+	defer s.Close()
+	fn(t, s)
+}
+
+func testEntry(i int) *Entry {
+	return &Entry{
+		IdentityHash: []byte(fmt.Sprintf("identity %d", i)),
+		LeafInput:    []byte(fmt.Sprintf("leaf %d", i)),
+		ExtraData:    []byte(fmt.Sprintf("extra %d", i)),
+		SCT: ct.SignedCertificateTimestamp{
+			SCTVersion: ct.V1,
+			Timestamp:  uint64(i),
+			Signature:  ct.DigitallySigned{HashAlgorithm: ct.SHA256, SignatureAlgorithm: ct.ECDSA, Signature: []byte("sig")},
+		},
+		Index: -1,
+	}
+}
+
+func TestStorageSequencing(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s Storage) {
+		for i := 0; i < 5; i++ {
+			e, err := s.AddPendingEntry(testEntry(i))
+			if err != nil {
+				t.Fatalf("%T.AddPendingEntry(%d)=%v", s, i, err)
+			}
+			if e.Index != -1 || e.SCT.Timestamp != uint64(i) {
+				t.Errorf("%T.AddPendingEntry(%d) returned %+v", s, i, e)
+			}
+		}
+		// A duplicate submission gets the original entry back.
+		dup := testEntry(1)
+		dup.SCT.Timestamp = 100
+		if e, err := s.AddPendingEntry(dup); err != nil || e.SCT.Timestamp != 1 {
+			t.Errorf("%T.AddPendingEntry(duplicate)=%+v, %v; want original entry", s, e, err)
+		}
+
+		pending, err := s.PendingEntries(3)
+		if err != nil || len(pending) != 3 {
+			t.Fatalf("%T.PendingEntries(3) returned %d entries, %v", s, len(pending), err)
+		}
+		if err := s.SequenceEntries(pending); err != nil {
+			t.Fatalf("%T.SequenceEntries()=%v", s, err)
+		}
+		// Entries can't be sequenced twice.
+		if err := s.SequenceEntries(pending[2:]); err == nil {
+			t.Errorf("%T.SequenceEntries() succeeded for already sequenced entry", s)
+		}
+		pending, err = s.PendingEntries(10)
+		if err != nil || len(pending) != 2 {
+			t.Fatalf("%T.PendingEntries(10) returned %d entries, %v", s, len(pending), err)
+		}
+		if err := s.SequenceEntries(pending); err != nil {
+			t.Fatalf("%T.SequenceEntries()=%v", s, err)
+		}
+		if size, err := s.TreeSize(); err != nil || size != 5 {
+			t.Errorf("%T.TreeSize()=%d, %v; want 5", s, size, err)
+		}
+
+		entries, err := s.GetEntries(3, 10)
+		if err != nil || len(entries) != 2 {
+			t.Fatalf("%T.GetEntries(3, 10) returned %d entries, %v", s, len(entries), err)
+		}
+		for i, e := range entries {
+			want := testEntry(i + 3)
+			want.Index = int64(i + 3)
+			if fmt.Sprintf("%+v", e) != fmt.Sprintf("%+v", want) {
+				t.Errorf("%T.GetEntries() entry %d = %+v, want %+v", s, i, e, want)
+			}
+		}
+		if _, err := s.GetEntries(5, 6); err != ErrEntryNotFound {
+			t.Errorf("%T.GetEntries(5, 6)=%v, want ErrEntryNotFound", s, err)
+		}
+	})
+}
+
+func TestStorageSTH(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s Storage) {
+		if sth, err := s.LatestSTH(); err != nil || sth != nil {
+			t.Errorf("%T.LatestSTH()=%v, %v; want nil", s, sth, err)
+		}
+		for i := uint64(1); i <= 2; i++ {
+			sth := &ct.SignedTreeHead{
+				Version:           ct.V1,
+				TreeSize:          i,
+				Timestamp:         1000 + i,
+				TreeHeadSignature: ct.DigitallySigned{HashAlgorithm: ct.SHA256, SignatureAlgorithm: ct.RSA, Signature: []byte("sig")},
+			}
+			sth.SHA256RootHash[0] = byte(i)
+			sth.LogID[0] = 0xff
+			if err := s.AddSTH(sth); err != nil {
+				t.Fatalf("%T.AddSTH()=%v", s, err)
+			}
+			got, err := s.LatestSTH()
+			if err != nil {
+				t.Fatalf("%T.LatestSTH()=%v", s, err)
+			}
+			if fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", sth) {
+				t.Errorf("%T.LatestSTH()=%+v, want %+v", s, got, sth)
+			}
+		}
+		if sq, ok := s.(*SQLiteStorage); ok {
+			var n int
+			if err := sq.db.QueryRow("SELECT COUNT(*) FROM sths;").Scan(&n); err != nil {
+				t.Fatalf("failed to count STHs: %v", err)
+			}
+			if n != 1 {
+				t.Errorf("SQLiteStorage holds %d STHs, want only the latest", n)
+			}
+		}
+	})
+}
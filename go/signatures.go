@@ -4,9 +4,11 @@ import (
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/asn1"
 	"encoding/pem"
 	"errors"
@@ -14,6 +16,8 @@ import (
 	"fmt"
 	"log"
 	"math/big"
+
+	"golang.org/x/crypto/ed25519"
 )
 
 var allowVerificationWithNonCompliantKeys = flag.Bool("allow_verification_with_non_compliant_keys", false,
@@ -25,17 +29,60 @@ func PublicKeyFromPEM(b []byte) (crypto.PublicKey, SHA256Hash, []byte, error) {
 	if p == nil {
 		return nil, [sha256.Size]byte{}, rest, fmt.Errorf("no PEM block found in %s", string(b))
 	}
-	k, err := x509.ParsePKIXPublicKey(p.Bytes)
+	k, err := ParsePKIXPublicKey(p.Bytes)
 	return k, sha256.Sum256(p.Bytes), rest, err
 }
 
+// oidPublicKeyEd25519 identifies Ed25519 keys, see RFC8410.
+var oidPublicKeyEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+// ParsePKIXPublicKey parses a DER encoded SubjectPublicKeyInfo, as
+// x509.ParsePKIXPublicKey does. Ed25519 keys, which x509 only understands
+// from Go 1.13, are parsed here and returned as ed25519.PublicKey.
+func ParsePKIXPublicKey(der []byte) (crypto.PublicKey, error) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	rest, err := asn1.Unmarshal(der, &spki)
+	if err != nil || len(rest) != 0 || !spki.Algorithm.Algorithm.Equal(oidPublicKeyEd25519) {
+		return x509.ParsePKIXPublicKey(der)
+	}
+	if len(spki.Algorithm.Parameters.FullBytes) != 0 {
+		return nil, errors.New("Ed25519 key has algorithm parameters")
+	}
+	if spki.PublicKey.BitLength != 8*ed25519.PublicKeySize {
+		return nil, fmt.Errorf("Ed25519 key has %d bits, want %d", spki.PublicKey.BitLength, 8*ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(append([]byte(nil), spki.PublicKey.Bytes...)), nil
+}
+
 // SignatureVerifier can verify signatures on SCTs and STHs
 type SignatureVerifier struct {
-	pubKey crypto.PublicKey
+	pubKey             crypto.PublicKey
+	extendedAlgorithms bool
+}
+
+// VerifierOption configures optional behaviour of a SignatureVerifier.
+type VerifierOption func(*SignatureVerifier)
+
+// WithExtendedAlgorithms allows a SignatureVerifier to use the keys and
+// signature algorithms beyond those permitted by RFC6962 which RFC6962-bis
+// logs and test logs may use: Ed25519, ECDSA on P-384 with SHA-384, and
+// RSA-PSS.
+func WithExtendedAlgorithms() VerifierOption {
+	return func(s *SignatureVerifier) {
+		s.extendedAlgorithms = true
+	}
 }
 
 // NewSignatureVerifier creates a new SignatureVerifier using the passed in PublicKey.
-func NewSignatureVerifier(pk crypto.PublicKey) (*SignatureVerifier, error) {
+// |opts| configure optional behaviour, such as which algorithms are allowed.
+func NewSignatureVerifier(pk crypto.PublicKey, opts ...VerifierOption) (*SignatureVerifier, error) {
+	sv := &SignatureVerifier{pubKey: pk}
+	for _, opt := range opts {
+		opt(sv)
+	}
 	switch pkType := pk.(type) {
 	case *rsa.PublicKey:
 		if pkType.N.BitLen() < 2048 {
@@ -47,7 +94,7 @@ func NewSignatureVerifier(pk crypto.PublicKey) (*SignatureVerifier, error) {
 		}
 	case *ecdsa.PublicKey:
 		params := *(pkType.Params())
-		if params != *elliptic.P256().Params() {
+		if params != *elliptic.P256().Params() && !(sv.extendedAlgorithms && params == *elliptic.P384().Params()) {
 			e := fmt.Errorf("public is ECDSA, but not on the P256 curve")
 			if !(*allowVerificationWithNonCompliantKeys) {
 				return nil, e
@@ -55,28 +102,40 @@ func NewSignatureVerifier(pk crypto.PublicKey) (*SignatureVerifier, error) {
 			log.Printf("WARNING: %v", e)
 
 		}
+	case ed25519.PublicKey:
+		if !sv.extendedAlgorithms {
+			return nil, errors.New("Ed25519 public keys are only supported with extended algorithms")
+		}
 	default:
 		return nil, fmt.Errorf("Unsupported public key type %v", pkType)
 	}
 
-	return &SignatureVerifier{
-		pubKey: pk,
-	}, nil
+	return sv, nil
 }
 
-// verifySignature verifies that the passed in signature over data was created by our PublicKey.
-// Currently, only SHA256 is supported as a HashAlgorithm, and only ECDSA and RSA signatures are supported.
-func (s SignatureVerifier) verifySignature(data []byte, sig DigitallySigned) error {
-	if sig.HashAlgorithm != SHA256 {
-		return fmt.Errorf("unsupported HashAlgorithm in signature: %v", sig.HashAlgorithm)
+// digest returns the hash of data using hasherType.
+func digest(hasherType crypto.Hash, data []byte) ([]byte, error) {
+	hasher := hasherType.New()
+	if _, err := hasher.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to write to hasher: %v", err)
 	}
+	return hasher.Sum([]byte{}), nil
+}
 
+// verifySignature verifies that the passed in signature over data was created by our PublicKey.
+// Only SHA256 ECDSA and RSA signatures are supported, unless the verifier allows
+// extended algorithms, in which case SHA384 ECDSA signatures with P-384 keys,
+// RSA-PSS and Ed25519 signatures are also supported.
+func (s SignatureVerifier) verifySignature(data []byte, sig DigitallySigned) error {
 	hasherType := crypto.SHA256
-	hasher := hasherType.New()
-	if _, err := hasher.Write(data); err != nil {
-		return fmt.Errorf("failed to write to hasher: %v", err)
+	switch {
+	case sig.HashAlgorithm == SHA256:
+	case s.extendedAlgorithms && sig.HashAlgorithm == SHA384 && sig.SignatureAlgorithm == ECDSA:
+		hasherType = crypto.SHA384
+	case s.extendedAlgorithms && sig.HashAlgorithm == Intrinsic:
+	default:
+		return fmt.Errorf("unsupported HashAlgorithm in signature: %v", sig.HashAlgorithm)
 	}
-	hash := hasher.Sum([]byte{})
 
 	switch sig.SignatureAlgorithm {
 	case RSA:
@@ -84,6 +143,13 @@ func (s SignatureVerifier) verifySignature(data []byte, sig DigitallySigned) err
 		if !ok {
 			return fmt.Errorf("cannot verify RSA signature with %T key", s.pubKey)
 		}
+		if sig.HashAlgorithm != SHA256 {
+			return fmt.Errorf("unsupported HashAlgorithm %v for RSA signature", sig.HashAlgorithm)
+		}
+		hash, err := digest(hasherType, data)
+		if err != nil {
+			return err
+		}
 		if err := rsa.VerifyPKCS1v15(rsaKey, hasherType, hash, sig.Signature); err != nil {
 			return fmt.Errorf("failed to verify rsa signature: %v", err)
 		}
@@ -92,6 +158,16 @@ func (s SignatureVerifier) verifySignature(data []byte, sig DigitallySigned) err
 		if !ok {
 			return fmt.Errorf("cannot verify ECDSA signature with %T key", s.pubKey)
 		}
+		if sig.HashAlgorithm == SHA384 && *(ecdsaKey.Params()) != *elliptic.P384().Params() {
+			return errors.New("SHA384 ECDSA signatures are only supported with P-384 keys")
+		}
+		if sig.HashAlgorithm == Intrinsic {
+			return errors.New("unsupported HashAlgorithm Intrinsic for ECDSA signature")
+		}
+		hash, err := digest(hasherType, data)
+		if err != nil {
+			return err
+		}
 		var ecdsaSig struct {
 			R, S *big.Int
 		}
@@ -106,6 +182,37 @@ func (s SignatureVerifier) verifySignature(data []byte, sig DigitallySigned) err
 		if !ecdsa.Verify(ecdsaKey, hash, ecdsaSig.R, ecdsaSig.S) {
 			return errors.New("failed to verify ecdsa signature")
 		}
+	case RSAPSSWithSHA256, RSAPSSWithSHA384, RSAPSSWithSHA512:
+		rsaKey, ok := s.pubKey.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("cannot verify RSA-PSS signature with %T key", s.pubKey)
+		}
+		if sig.HashAlgorithm != Intrinsic {
+			return fmt.Errorf("unsupported HashAlgorithm %v for RSA-PSS signature", sig.HashAlgorithm)
+		}
+		pssHash := map[SignatureAlgorithm]crypto.Hash{
+			RSAPSSWithSHA256: crypto.SHA256,
+			RSAPSSWithSHA384: crypto.SHA384,
+			RSAPSSWithSHA512: crypto.SHA512,
+		}[sig.SignatureAlgorithm]
+		hash, err := digest(pssHash, data)
+		if err != nil {
+			return err
+		}
+		if err := rsa.VerifyPSS(rsaKey, pssHash, hash, sig.Signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}); err != nil {
+			return fmt.Errorf("failed to verify rsa-pss signature: %v", err)
+		}
+	case Ed25519:
+		edKey, ok := s.pubKey.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("cannot verify Ed25519 signature with %T key", s.pubKey)
+		}
+		if sig.HashAlgorithm != Intrinsic {
+			return fmt.Errorf("unsupported HashAlgorithm %v for Ed25519 signature", sig.HashAlgorithm)
+		}
+		if !ed25519.Verify(edKey, data, sig.Signature) {
+			return errors.New("failed to verify ed25519 signature")
+		}
 	default:
 		return fmt.Errorf("unsupported signature type %v", sig.SignatureAlgorithm)
 	}
@@ -129,3 +236,124 @@ func (s SignatureVerifier) VerifySTHSignature(sth SignedTreeHead) error {
 	}
 	return s.verifySignature(sthData, sth.TreeHeadSignature)
 }
+
+// Signer can create signatures on SCTs and STHs.
+type Signer struct {
+	key    crypto.Signer
+	sigAlg SignatureAlgorithm
+	logID  SHA256Hash
+}
+
+// NewSigner creates a new Signer using the passed in private key, which must
+// be an RSA key of at least 2048 bits or an ECDSA key on the P256 curve, as
+// required by RFC6962 section 2.1.4.
+func NewSigner(key crypto.Signer) (*Signer, error) {
+	if key == nil {
+		return nil, errors.New("no private key")
+	}
+	var sigAlg SignatureAlgorithm
+	switch pkType := key.Public().(type) {
+	case *rsa.PublicKey:
+		if pkType.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("private key is RSA with < 2048 bits (size:%d)", pkType.N.BitLen())
+		}
+		sigAlg = RSA
+	case *ecdsa.PublicKey:
+		params := *(pkType.Params())
+		if params != *elliptic.P256().Params() {
+			return nil, errors.New("private key is ECDSA, but not on the P256 curve")
+		}
+		sigAlg = ECDSA
+	default:
+		return nil, fmt.Errorf("Unsupported private key type %T", pkType)
+	}
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %v", err)
+	}
+	return &Signer{
+		key:    key,
+		sigAlg: sigAlg,
+		logID:  sha256.Sum256(der),
+	}, nil
+}
+
+// LogID returns the ID of the log whose key this is: the SHA256 hash of the
+// DER encoded public key.
+func (s Signer) LogID() SHA256Hash {
+	return s.logID
+}
+
+// PublicKey returns the public half of the Signer's key.
+func (s Signer) PublicKey() crypto.PublicKey {
+	return s.key.Public()
+}
+
+// sign creates a signature over the SHA256 hash of data.
+func (s Signer) sign(data []byte) (*DigitallySigned, error) {
+	hash := sha256.Sum256(data)
+	sig, err := s.key.Sign(rand.Reader, hash[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %v", err)
+	}
+	return &DigitallySigned{
+		HashAlgorithm:      SHA256,
+		SignatureAlgorithm: s.sigAlg,
+		Signature:          sig,
+	}, nil
+}
+
+// SignSCT creates the signature for an SCT over the given LogEntry, as
+// checked by SignatureVerifier.VerifySCTSignature.
+func (s Signer) SignSCT(sct SignedCertificateTimestamp, entry LogEntry) (*DigitallySigned, error) {
+	sctData, err := SerializeSCTSignatureInput(sct, entry)
+	if err != nil {
+		return nil, err
+	}
+	return s.sign(sctData)
+}
+
+// SignSTH creates the signature for an STH, as checked by
+// SignatureVerifier.VerifySTHSignature.
+func (s Signer) SignSTH(sth SignedTreeHead) (*DigitallySigned, error) {
+	sthData, err := SerializeSTHSignatureInput(sth)
+	if err != nil {
+		return nil, err
+	}
+	return s.sign(sthData)
+}
+
+// CreateSCT creates a complete, signed, V1 SCT for the given LogEntry, with the
+// timestamp and extensions of the entry's leaf.
+func (s Signer) CreateSCT(entry LogEntry) (*SignedCertificateTimestamp, error) {
+	sct := SignedCertificateTimestamp{
+		SCTVersion: V1,
+		LogID:      s.logID,
+		Timestamp:  entry.Leaf.TimestampedEntry.Timestamp,
+		Extensions: entry.Leaf.TimestampedEntry.Extensions,
+	}
+	sig, err := s.SignSCT(sct, entry)
+	if err != nil {
+		return nil, err
+	}
+	sct.Signature = *sig
+	return &sct, nil
+}
+
+// CreateSTH creates a complete, signed, V1 STH for the tree with the given
+// size and root hash.
+func (s Signer) CreateSTH(treeSize, timestamp uint64, rootHash SHA256Hash) (*SignedTreeHead, error) {
+	sth := SignedTreeHead{
+		Version:        V1,
+		TreeSize:       treeSize,
+		Timestamp:      timestamp,
+		SHA256RootHash: rootHash,
+		LogID:          s.logID,
+	}
+	sig, err := s.SignSTH(sth)
+	if err != nil {
+		return nil, err
+	}
+	sth.TreeHeadSignature = *sig
+	return &sth, nil
+}
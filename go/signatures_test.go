@@ -8,9 +8,16 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
 	"encoding/hex"
+	"encoding/pem"
 	mrand "math/rand"
+	"reflect"
 	"testing"
+
+	"golang.org/x/crypto/ed25519"
 )
 
 const (
@@ -441,6 +448,7 @@ func TestNewSignatureVerifierFailsWithBadKeyParametersForRSA(t *testing.T) {
 }
 
 func TestWillAllowNonCompliantECKeyWithOverride(t *testing.T) {
+	defer func(old bool) { *allowVerificationWithNonCompliantKeys = old }(*allowVerificationWithNonCompliantKeys)
 	*allowVerificationWithNonCompliantKeys = true
 	k, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
 	if err != nil {
@@ -452,6 +460,7 @@ func TestWillAllowNonCompliantECKeyWithOverride(t *testing.T) {
 }
 
 func TestWillAllowNonCompliantRSAKeyWithOverride(t *testing.T) {
+	defer func(old bool) { *allowVerificationWithNonCompliantKeys = old }(*allowVerificationWithNonCompliantKeys)
 	*allowVerificationWithNonCompliantKeys = true
 	k, err := rsa.GenerateKey(rand.Reader, 1024)
 	if err != nil {
@@ -461,3 +470,307 @@ func TestWillAllowNonCompliantRSAKeyWithOverride(t *testing.T) {
 		t.Fatalf("Incorrectly disallowed 1024 bit RSA key with override set: %v", err)
 	}
 }
+
+func sigTestECPrivateKey(t *testing.T) crypto.Signer {
+	p, _ := pem.Decode([]byte(sigTestEC256PrivateKeyPEM))
+	k, err := x509.ParseECPrivateKey(p.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse sigTestEC256PrivateKey: %v", err)
+	}
+	return k
+}
+
+func sigTestRSAPrivateKey(t *testing.T) crypto.Signer {
+	p, _ := pem.Decode([]byte(sigTestRSAPrivateKeyPEM))
+	k, err := x509.ParsePKCS1PrivateKey(p.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse sigTestRSAPrivateKey: %v", err)
+	}
+	return k
+}
+
+func mustCreateSigner(t *testing.T, k crypto.Signer) Signer {
+	s, err := NewSigner(k)
+	if err != nil {
+		t.Fatalf("Failed to create Signer: %v", err)
+	}
+	return *s
+}
+
+func testSignerRoundTrip(t *testing.T, k crypto.Signer, pubKeyPEM string) {
+	s := mustCreateSigner(t, k)
+	pk, keyID, _, err := PublicKeyFromPEM([]byte(pubKeyPEM))
+	if err != nil {
+		t.Fatalf("Failed to parse public key: %v", err)
+	}
+	v := mustCreateSignatureVerifier(t, pk)
+	if s.LogID() != keyID {
+		t.Errorf("LogID()=%x, want %x", s.LogID(), keyID)
+	}
+
+	entry := sigTestCertLogEntry(t)
+	sct, err := s.CreateSCT(entry)
+	if err != nil {
+		t.Fatalf("CreateSCT()=%v", err)
+	}
+	if sct.SCTVersion != V1 || sct.LogID != s.LogID() || sct.Timestamp != sigTestSCTTimestamp {
+		t.Errorf("CreateSCT() returned %v", sct)
+	}
+	if err := v.VerifySCTSignature(*sct, entry); err != nil {
+		t.Errorf("VerifySCTSignature() failed for created SCT: %v", err)
+	}
+	corruptBytes(entry.Leaf.TimestampedEntry.X509Entry)
+	if err := v.VerifySCTSignature(*sct, entry); err == nil {
+		t.Error("VerifySCTSignature() succeeded for a different entry")
+	}
+
+	want := sigTestDefaultSTH(t)
+	sth, err := s.CreateSTH(want.TreeSize, want.Timestamp, want.SHA256RootHash)
+	if err != nil {
+		t.Fatalf("CreateSTH()=%v", err)
+	}
+	if sth.Version != V1 || sth.TreeSize != want.TreeSize || sth.Timestamp != want.Timestamp || sth.SHA256RootHash != want.SHA256RootHash || sth.LogID != s.LogID() {
+		t.Errorf("CreateSTH() returned %+v", sth)
+	}
+	expectVerifySTHToPass(t, v, *sth)
+	sth.TreeSize++
+	expectVerifySTHToFail(t, v, *sth)
+}
+
+func TestSignerRoundTripEC(t *testing.T) {
+	testSignerRoundTrip(t, sigTestECPrivateKey(t), sigTestEC256PublicKeyPEM)
+}
+
+func TestSignerRoundTripRSA(t *testing.T) {
+	testSignerRoundTrip(t, sigTestRSAPrivateKey(t), sigTestRSAPublicKeyPEM)
+}
+
+func TestSignSCTRSAMatchesKnownSignature(t *testing.T) {
+	// PKCS#1 v1.5 signatures are deterministic, so we should reproduce the
+	// test SCT exactly.
+	s := mustCreateSigner(t, sigTestRSAPrivateKey(t))
+	sct := sigTestSCTRSA(t)
+	sig, err := s.SignSCT(sct, sigTestCertLogEntry(t))
+	if err != nil {
+		t.Fatalf("SignSCT()=%v", err)
+	}
+	got, err := MarshalDigitallySigned(*sig)
+	if err != nil {
+		t.Fatalf("MarshalDigitallySigned()=%v", err)
+	}
+	if want := mustDehex(t, sigTestCertSCTSignatureRSA); !bytes.Equal(got, want) {
+		t.Errorf("SignSCT()=%x, want %x", got, want)
+	}
+}
+
+func TestNewSignerFailsWithNonCompliantKeys(t *testing.T) {
+	ec, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key on P224: %v", err)
+	}
+	if _, err := NewSigner(ec); err == nil {
+		t.Error("Incorrectly created new Signer with EC P224 key.")
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Failed to generate 1024 bit RSA key: %v", err)
+	}
+	if _, err := NewSigner(rsaKey); err == nil {
+		t.Error("Incorrectly created new Signer with 1024 bit RSA key.")
+	}
+}
+
+// extendedSignature signs the STH signature input for |sth| with |key|,
+// using the given algorithms.
+func extendedSignature(t *testing.T, key crypto.Signer, sth SignedTreeHead, hashAlg HashAlgorithm, sigAlg SignatureAlgorithm) DigitallySigned {
+	data, err := SerializeSTHSignatureInput(sth)
+	if err != nil {
+		t.Fatalf("SerializeSTHSignatureInput()=%v", err)
+	}
+	var sig []byte
+	switch sigAlg {
+	case Ed25519:
+		sig, err = key.Sign(rand.Reader, data, crypto.Hash(0))
+	case ECDSA:
+		hash := crypto.SHA384
+		if hashAlg == SHA256 {
+			hash = crypto.SHA256
+		}
+		h := hash.New()
+		h.Write(data)
+		sig, err = key.Sign(rand.Reader, h.Sum(nil), hash)
+	default:
+		hash := map[SignatureAlgorithm]crypto.Hash{RSAPSSWithSHA256: crypto.SHA256, RSAPSSWithSHA384: crypto.SHA384, RSAPSSWithSHA512: crypto.SHA512}[sigAlg]
+		h := hash.New()
+		h.Write(data)
+		sig, err = key.Sign(rand.Reader, h.Sum(nil), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash})
+	}
+	if err != nil {
+		t.Fatalf("Sign()=%v", err)
+	}
+	return DigitallySigned{HashAlgorithm: hashAlg, SignatureAlgorithm: sigAlg, Signature: sig}
+}
+
+func TestVerifySTHSignatureExtendedAlgorithms(t *testing.T) {
+	defer func(old bool) { *allowVerificationWithNonCompliantKeys = old }(*allowVerificationWithNonCompliantKeys)
+	*allowVerificationWithNonCompliantKeys = false
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key on P384: %v", err)
+	}
+	rsaKey := sigTestRSAPrivateKey(t)
+	sth := sigTestDefaultSTH(t)
+
+	for _, test := range []struct {
+		desc    string
+		key     crypto.Signer
+		hashAlg HashAlgorithm
+		sigAlg  SignatureAlgorithm
+		// compliant is true if the key may be used without extended algorithms.
+		compliant bool
+	}{
+		{"Ed25519", edKey, Intrinsic, Ed25519, false},
+		{"ECDSA P-384", p384Key, SHA384, ECDSA, false},
+		{"RSA-PSS SHA256", rsaKey, Intrinsic, RSAPSSWithSHA256, true},
+		{"RSA-PSS SHA384", rsaKey, Intrinsic, RSAPSSWithSHA384, true},
+		{"RSA-PSS SHA512", rsaKey, Intrinsic, RSAPSSWithSHA512, true},
+	} {
+		sth.TreeHeadSignature = extendedSignature(t, test.key, sth, test.hashAlg, test.sigAlg)
+
+		v, err := NewSignatureVerifier(test.key.Public())
+		if test.compliant {
+			if err != nil {
+				t.Fatalf("%s: NewSignatureVerifier()=%v", test.desc, err)
+			}
+			if err := v.VerifySTHSignature(sth); err == nil {
+				t.Errorf("%s: VerifySTHSignature() without extended algorithms succeeded, want error", test.desc)
+			}
+		} else if err == nil {
+			t.Errorf("%s: NewSignatureVerifier() without extended algorithms succeeded, want error", test.desc)
+		}
+
+		v, err = NewSignatureVerifier(test.key.Public(), WithExtendedAlgorithms())
+		if err != nil {
+			t.Fatalf("%s: NewSignatureVerifier(WithExtendedAlgorithms())=%v", test.desc, err)
+		}
+		if err := v.VerifySTHSignature(sth); err != nil {
+			t.Errorf("%s: VerifySTHSignature()=%v", test.desc, err)
+		}
+
+		bad := sth
+		bad.TreeSize++
+		if err := v.VerifySTHSignature(bad); err == nil {
+			t.Errorf("%s: VerifySTHSignature() of modified STH succeeded, want error", test.desc)
+		}
+		bad = sth
+		bad.TreeHeadSignature.HashAlgorithm = SHA256
+		if err := v.VerifySTHSignature(bad); err == nil {
+			t.Errorf("%s: VerifySTHSignature() with SHA256 hash algorithm succeeded, want error", test.desc)
+		}
+		bad.TreeHeadSignature.HashAlgorithm = SHA512
+		if err := v.VerifySTHSignature(bad); err == nil {
+			t.Errorf("%s: VerifySTHSignature() with SHA512 hash algorithm succeeded, want error", test.desc)
+		}
+	}
+}
+
+func TestVerifySTHSignatureExtendedAlgorithmMismatches(t *testing.T) {
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key on P256: %v", err)
+	}
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key on P384: %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+	sth := sigTestDefaultSTH(t)
+	for _, test := range []struct {
+		desc string
+		key  crypto.Signer
+		sig  DigitallySigned
+	}{
+		{"SHA384 with P-256 key", p256Key, extendedSignature(t, p256Key, sth, SHA384, ECDSA)},
+		{"Intrinsic ECDSA", p384Key, extendedSignature(t, p384Key, sth, Intrinsic, ECDSA)},
+		{"RSA-PSS with ECDSA key", p384Key, extendedSignature(t, sigTestRSAPrivateKey(t), sth, Intrinsic, RSAPSSWithSHA256)},
+		{"Ed25519 with ECDSA key", p256Key, extendedSignature(t, edKey, sth, Intrinsic, Ed25519)},
+		{"RSA PKCS#1 with Intrinsic", sigTestRSAPrivateKey(t), DigitallySigned{HashAlgorithm: Intrinsic, SignatureAlgorithm: RSA}},
+	} {
+		v, err := NewSignatureVerifier(test.key.Public(), WithExtendedAlgorithms())
+		if err != nil {
+			t.Fatalf("%s: NewSignatureVerifier()=%v", test.desc, err)
+		}
+		sth.TreeHeadSignature = test.sig
+		if err := v.VerifySTHSignature(sth); err == nil {
+			t.Errorf("%s: VerifySTHSignature() succeeded, want error", test.desc)
+		}
+	}
+}
+
+// marshalEd25519PublicKey returns the DER SubjectPublicKeyInfo for |pub|,
+// which x509.MarshalPKIXPublicKey can't produce before Go 1.13.
+func marshalEd25519PublicKey(t *testing.T, pub ed25519.PublicKey) []byte {
+	der, err := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidPublicKeyEd25519},
+		PublicKey: asn1.BitString{Bytes: pub, BitLength: 8 * len(pub)},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal Ed25519 key: %v", err)
+	}
+	return der
+}
+
+func TestParsePKIXPublicKey(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+	der := marshalEd25519PublicKey(t, pub)
+	key, err := ParsePKIXPublicKey(der)
+	if err != nil {
+		t.Fatalf("ParsePKIXPublicKey(Ed25519)=%v", err)
+	}
+	if got, ok := key.(ed25519.PublicKey); !ok || !bytes.Equal(got, pub) {
+		t.Errorf("ParsePKIXPublicKey(Ed25519)=%T %x, want %x", key, key, pub)
+	}
+	if _, err := ParsePKIXPublicKey(der[:len(der)-1]); err == nil {
+		t.Error("ParsePKIXPublicKey(truncated Ed25519 key) succeeded")
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key: %v", err)
+	}
+	der, err = x509.MarshalPKIXPublicKey(ecKey.Public())
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey()=%v", err)
+	}
+	if key, err := ParsePKIXPublicKey(der); err != nil || !reflect.DeepEqual(key, ecKey.Public()) {
+		t.Errorf("ParsePKIXPublicKey(ECDSA)=%v, %v", key, err)
+	}
+}
+
+func TestLogVerifiersWithExtendedAlgorithms(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+	der := marshalEd25519PublicKey(t, pub)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	if err := make(LogVerifiers).AddPEM(keyPEM); err == nil {
+		t.Error("AddPEM() of Ed25519 key succeeded, want error")
+	}
+	if err := make(LogVerifiers).AddPEM(keyPEM, WithExtendedAlgorithms()); err != nil {
+		t.Errorf("AddPEM(WithExtendedAlgorithms()) of Ed25519 key=%v", err)
+	}
+}
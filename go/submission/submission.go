@@ -0,0 +1,158 @@
+// Package submission submits certificate and precertificate chains to several
+// CT logs at once, collecting SCTs until a policy is satisfied.
+package submission
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+	"golang.org/x/net/context"
+)
+
+// ErrPolicyNotSatisfied is returned when every log has either issued an SCT
+// or failed, without the SCTs collected satisfying the Submitter's Policy.
+var ErrPolicyNotSatisfied = errors.New("SCTs collected do not satisfy policy")
+
+// Log describes a CT log which chains can be submitted to.
+type Log struct {
+	Name     string            // uniquely identifies the log, e.g. its URL
+	Operator string            // the organisation which runs the log
+	Client   *client.LogClient // used to talk to the log
+	// Timeout bounds the time spent submitting to this log. Zero means that
+	// only the context passed to the Submitter applies.
+	Timeout time.Duration
+}
+
+// LogSCT is an SCT along with the log which issued it.
+type LogSCT struct {
+	Log *Log
+	SCT *ct.SignedCertificateTimestamp
+}
+
+// Result holds the outcome of a submission.
+type Result struct {
+	SCTs []LogSCT // the SCTs collected, in the order they were received
+	// Errors holds the error returned by each log which failed, keyed by
+	// Log.Name. Logs which were still working when the policy was satisfied
+	// are cancelled and don't appear here.
+	Errors map[string]error
+}
+
+// Policy decides when enough SCTs have been collected.
+type Policy interface {
+	// Satisfied returns true if |scts| are sufficient.
+	Satisfied(scts []LogSCT) bool
+}
+
+// OperatorPolicy is a Policy which requires at least MinSCTs SCTs, issued by
+// logs belonging to at least MinOperators distinct operators.
+type OperatorPolicy struct {
+	MinSCTs      int
+	MinOperators int
+}
+
+// Satisfied implements Policy.
+func (p OperatorPolicy) Satisfied(scts []LogSCT) bool {
+	if len(scts) < p.MinSCTs {
+		return false
+	}
+	operators := make(map[string]bool)
+	for _, s := range scts {
+		operators[s.Log.Operator] = true
+	}
+	return len(operators) >= p.MinOperators
+}
+
+// Submitter submits chains to a set of logs concurrently.
+type Submitter struct {
+	logs   []Log
+	policy Policy
+}
+
+// NewSubmitter returns a Submitter which submits to all of |logs|, and
+// returns as soon as the SCTs collected satisfy |policy|. Returns an error if
+// two of |logs| have the same Name.
+func NewSubmitter(logs []Log, policy Policy) (*Submitter, error) {
+	names := make(map[string]bool)
+	for _, l := range logs {
+		if names[l.Name] {
+			return nil, fmt.Errorf("duplicate log name %q", l.Name)
+		}
+		names[l.Name] = true
+	}
+	s := &Submitter{
+		logs:   make([]Log, len(logs)),
+		policy: policy,
+	}
+	copy(s.logs, logs)
+	return s, nil
+}
+
+// SubmitChain submits the (DER represented) X509 |chain| to all logs.
+// Returns once the policy is satisfied, cancelling any outstanding
+// submissions, or once all logs have responded, or when |ctx| expires.
+// The Result is always returned; the error is ErrPolicyNotSatisfied if the
+// SCTs collected are insufficient.
+func (s *Submitter) SubmitChain(ctx context.Context, chain []ct.ASN1Cert) (*Result, error) {
+	return s.submit(ctx, func(ctx context.Context, c *client.LogClient) (*ct.SignedCertificateTimestamp, error) {
+		return c.AddChainWithContext(ctx, chain)
+	})
+}
+
+// SubmitPreChain submits the (DER represented) Precertificate |chain| to all
+// logs, as for SubmitChain.
+func (s *Submitter) SubmitPreChain(ctx context.Context, chain []ct.ASN1Cert) (*Result, error) {
+	return s.submit(ctx, func(ctx context.Context, c *client.LogClient) (*ct.SignedCertificateTimestamp, error) {
+		return c.AddPreChainWithContext(ctx, chain)
+	})
+}
+
+type addFunc func(context.Context, *client.LogClient) (*ct.SignedCertificateTimestamp, error)
+
+type logResult struct {
+	log *Log
+	sct *ct.SignedCertificateTimestamp
+	err error
+}
+
+func (s *Submitter) submit(ctx context.Context, add addFunc) (*Result, error) {
+	ret := &Result{Errors: make(map[string]error)}
+	if s.policy.Satisfied(nil) {
+		return ret, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	// Cancels any stragglers once we return.
+	defer cancel()
+
+	// Buffered so that stragglers never block.
+	results := make(chan logResult, len(s.logs))
+	for i := range s.logs {
+		go submitToLog(ctx, &s.logs[i], add, results)
+	}
+	for range s.logs {
+		r := <-results
+		if r.err != nil {
+			ret.Errors[r.log.Name] = r.err
+			continue
+		}
+		ret.SCTs = append(ret.SCTs, LogSCT{Log: r.log, SCT: r.sct})
+		if s.policy.Satisfied(ret.SCTs) {
+			return ret, nil
+		}
+	}
+	return ret, ErrPolicyNotSatisfied
+}
+
+func submitToLog(ctx context.Context, l *Log, add addFunc, results chan<- logResult) {
+	if l.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.Timeout)
+		defer cancel()
+	}
+	sct, err := add(ctx, l.Client)
+	results <- logResult{log: l, sct: sct, err: err}
+}
@@ -0,0 +1,208 @@
+package submission
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+	"golang.org/x/net/context"
+)
+
+const sctResponse = `{"sct_version":0,"id":"KHYaGJAn++880NYaAY12sFBXKcenQRvMvfYE9F1CYVM=","timestamp":1337,"extensions":"","signature":"BAMARjBEAiAIc21J5ZbdKZHw5wLxCP+MhBEsV5+nfvGyakOIv6FOvAIgWYMZb6Pw///uiNM7QTg2Of1OqmK1GbeGuEl9VJN8v8c="}`
+
+var testChain = []ct.ASN1Cert{[]byte("not really a cert")}
+
+// fakeLog describes how a test log responds to submissions.
+type fakeLog struct {
+	name, operator string
+	status         int           // zero means respond with an SCT
+	block          bool          // don't respond until the request is cancelled
+	timeout        time.Duration // the Log.Timeout to use
+}
+
+// startLogs starts a test server for each of |fakes|, and returns the
+// corresponding Logs along with a function which stops the servers.
+func startLogs(t *testing.T, fakes []fakeLog) ([]Log, func()) {
+	var logs []Log
+	var servers []*httptest.Server
+	unblock := make(chan struct{})
+	for _, f := range fakes {
+		f := f
+		hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasSuffix(r.URL.Path, client.AddChainPath) && !strings.HasSuffix(r.URL.Path, client.AddPreChainPath) {
+				t.Errorf("%s: unexpected request to %s", f.name, r.URL.Path)
+			}
+			switch {
+			case f.block:
+				<-unblock
+			case f.status != 0:
+				w.WriteHeader(f.status)
+			default:
+				w.Write([]byte(sctResponse))
+			}
+		}))
+		servers = append(servers, hs)
+		logs = append(logs, Log{
+			Name:     f.name,
+			Operator: f.operator,
+			Client:   client.New(hs.URL, &http.Client{}),
+			Timeout:  f.timeout,
+		})
+	}
+	return logs, func() {
+		close(unblock)
+		for _, hs := range servers {
+			hs.Close()
+		}
+	}
+}
+
+func logNames(scts []LogSCT) map[string]bool {
+	names := make(map[string]bool)
+	for _, s := range scts {
+		names[s.Log.Name] = true
+	}
+	return names
+}
+
+func mustSubmitter(t *testing.T, logs []Log, policy Policy) *Submitter {
+	s, err := NewSubmitter(logs, policy)
+	if err != nil {
+		t.Fatalf("NewSubmitter()=%v", err)
+	}
+	return s
+}
+
+func TestSubmitAllLogs(t *testing.T) {
+	logs, stop := startLogs(t, []fakeLog{
+		{name: "a", operator: "A"},
+		{name: "b", operator: "B"},
+		{name: "c", operator: "C"},
+	})
+	defer stop()
+
+	s := mustSubmitter(t, logs, OperatorPolicy{MinSCTs: 3, MinOperators: 3})
+	for _, submit := range []func(context.Context, []ct.ASN1Cert) (*Result, error){s.SubmitChain, s.SubmitPreChain} {
+		res, err := submit(context.Background(), testChain)
+		if err != nil {
+			t.Fatalf("submit()=%v", err)
+		}
+		if got := logNames(res.SCTs); len(got) != 3 {
+			t.Errorf("got SCTs from %v, want a, b and c", got)
+		}
+		for _, s := range res.SCTs {
+			if s.SCT == nil || s.SCT.Timestamp != 1337 {
+				t.Errorf("%s: got SCT %v", s.Log.Name, s.SCT)
+			}
+		}
+		if len(res.Errors) != 0 {
+			t.Errorf("got errors %v, want none", res.Errors)
+		}
+	}
+}
+
+func TestSubmitCancelsStragglers(t *testing.T) {
+	logs, stop := startLogs(t, []fakeLog{
+		{name: "a", operator: "A"},
+		{name: "slow", operator: "B", block: true},
+		{name: "b", operator: "B"},
+	})
+	defer stop()
+
+	start := time.Now()
+	res, err := mustSubmitter(t, logs, OperatorPolicy{MinSCTs: 2, MinOperators: 2}).SubmitChain(context.Background(), testChain)
+	if err != nil {
+		t.Fatalf("SubmitChain()=%v", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("SubmitChain() took %v, should not wait for the slow log", d)
+	}
+	if got := logNames(res.SCTs); !got["a"] || !got["b"] || got["slow"] {
+		t.Errorf("got SCTs from %v, want a and b", got)
+	}
+	if _, ok := res.Errors["slow"]; ok {
+		t.Errorf("cancelled log recorded as an error: %v", res.Errors)
+	}
+}
+
+func TestSubmitPerLogTimeout(t *testing.T) {
+	logs, stop := startLogs(t, []fakeLog{
+		{name: "a", operator: "A"},
+		{name: "slow", operator: "B", block: true, timeout: 50 * time.Millisecond},
+	})
+	defer stop()
+
+	res, err := mustSubmitter(t, logs, OperatorPolicy{MinSCTs: 2, MinOperators: 2}).SubmitChain(context.Background(), testChain)
+	if err != ErrPolicyNotSatisfied {
+		t.Fatalf("SubmitChain()=%v, want ErrPolicyNotSatisfied", err)
+	}
+	if got := logNames(res.SCTs); len(got) != 1 || !got["a"] {
+		t.Errorf("got SCTs from %v, want a", got)
+	}
+	if res.Errors["slow"] == nil {
+		t.Errorf("no error recorded for log which timed out: %v", res.Errors)
+	}
+}
+
+func TestSubmitPolicyNotSatisfied(t *testing.T) {
+	logs, stop := startLogs(t, []fakeLog{
+		{name: "a", operator: "A"},
+		{name: "b", operator: "B", status: http.StatusBadRequest},
+		{name: "c", operator: "C", status: http.StatusBadRequest},
+	})
+	defer stop()
+
+	res, err := mustSubmitter(t, logs, OperatorPolicy{MinSCTs: 2}).SubmitChain(context.Background(), testChain)
+	if err != ErrPolicyNotSatisfied {
+		t.Fatalf("SubmitChain()=%v, want ErrPolicyNotSatisfied", err)
+	}
+	if len(res.SCTs) != 1 {
+		t.Errorf("got %d SCTs, want 1", len(res.SCTs))
+	}
+	for _, name := range []string{"b", "c"} {
+		rspErr, ok := res.Errors[name].(client.RspError)
+		if !ok || rspErr.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: got error %v, want RspError with status 400", name, res.Errors[name])
+		}
+	}
+}
+
+func TestOperatorPolicy(t *testing.T) {
+	a1, a2, b := &Log{Operator: "A"}, &Log{Operator: "A"}, &Log{Operator: "B"}
+	tests := []struct {
+		policy OperatorPolicy
+		logs   []*Log
+		want   bool
+	}{
+		{OperatorPolicy{}, nil, true},
+		{OperatorPolicy{MinSCTs: 1}, nil, false},
+		{OperatorPolicy{MinSCTs: 1}, []*Log{a1}, true},
+		{OperatorPolicy{MinSCTs: 2, MinOperators: 2}, []*Log{a1, a2}, false},
+		{OperatorPolicy{MinSCTs: 2, MinOperators: 2}, []*Log{a1, b}, true},
+		{OperatorPolicy{MinSCTs: 3, MinOperators: 2}, []*Log{a1, b}, false},
+		{OperatorPolicy{MinSCTs: 3, MinOperators: 2}, []*Log{a1, a2, b}, true},
+	}
+	for i, test := range tests {
+		var scts []LogSCT
+		for _, l := range test.logs {
+			scts = append(scts, LogSCT{Log: l})
+		}
+		if got := test.policy.Satisfied(scts); got != test.want {
+			t.Errorf("%d: %+v.Satisfied(%d SCTs)=%v, want %v", i, test.policy, len(scts), got, test.want)
+		}
+	}
+}
+
+func TestNewSubmitterRejectsDuplicateNames(t *testing.T) {
+	logs := []Log{{Name: "a", Operator: "A"}, {Name: "b", Operator: "B"}, {Name: "a", Operator: "C"}}
+	if _, err := NewSubmitter(logs, OperatorPolicy{MinSCTs: 1}); err == nil {
+		t.Fatal("NewSubmitter() with duplicate names succeeded, want error")
+	}
+	if _, err := NewSubmitter(logs[:2], OperatorPolicy{MinSCTs: 1}); err != nil {
+		t.Fatalf("NewSubmitter()=%v", err)
+	}
+}
@@ -0,0 +1,46 @@
+// Package testonly holds helpers for tests which use the certificates, keys
+// and SCTs in the repository's test/testdata directory. It deliberately
+// depends on nothing else in the repository, so that it can be used by the
+// tests of any package, including ct and x509 themselves.
+package testonly
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// testdataDir is the path of test/testdata, found relative to this source
+// file so that it doesn't depend on the directory the test runs in.
+var testdataDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "test", "testdata")
+}()
+
+// Path returns the path of the file |name| in test/testdata.
+func Path(name string) string {
+	return filepath.Join(testdataDir, name)
+}
+
+// ReadFile returns the contents of the file |name| in test/testdata, and
+// fails |t| if it can't be read.
+func ReadFile(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile(Path(name))
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", name, err)
+	}
+	return data
+}
+
+// ReadPEM returns the contents of the first PEM block in the file |name| in
+// test/testdata, such as the DER encoding of a certificate or public key, and
+// fails |t| if there isn't one.
+func ReadPEM(t *testing.T, name string) []byte {
+	block, _ := pem.Decode(ReadFile(t, name))
+	if block == nil {
+		t.Fatalf("no PEM block in %s", name)
+	}
+	return block.Bytes
+}
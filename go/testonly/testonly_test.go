@@ -0,0 +1,16 @@
+package testonly
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReadPEM(t *testing.T) {
+	// A DER certificate is a SEQUENCE.
+	if der := ReadPEM(t, "ca-cert.pem"); len(der) == 0 || der[0] != 0x30 {
+		t.Errorf("ReadPEM(ca-cert.pem) returned %x, want DER certificate", der)
+	}
+	if data := ReadFile(t, "ca-cert.pem"); !bytes.Contains(data, []byte("-----BEGIN CERTIFICATE-----")) {
+		t.Errorf("ReadFile(ca-cert.pem) returned %q, want PEM file", data)
+	}
+}
@@ -0,0 +1,485 @@
+// Package tls implements the encoding of data structures described using the
+// TLS presentation language (RFC5246 section 4), as used throughout
+// Certificate Transparency.
+//
+// As with encoding/asn1, the layout of the encoding is derived from the Go
+// type being marshalled, annotated with struct field tags of the form
+// `tls:"key:value,key:value"`. The following keys are understood:
+//
+//	minlen:N, maxlen:M  For slices: the encoding is a vector<N..M>, whose
+//	                    length prefix is the number of bytes needed to hold M.
+//	elemminlen:N,       For slices of slices: each element is itself a
+//	elemmaxlen:M        vector<N..M>, e.g. the ASN.1Cert list
+//	                    `ASN.1Cert certificate_chain<0..2^24-1>` where
+//	                    ASN.1Cert is opaque<1..2^24-1>.
+//	size:N              For unsigned integers: encode in N bytes rather than
+//	                    the natural size of the Go type (e.g. size:3 on a
+//	                    uint32 for a uint24).
+//	selector:F,val:V    The field is a variant of a select() on the earlier
+//	                    unsigned integer field F, and is only present in the
+//	                    encoding when F has the value V. val may be repeated
+//	                    for a variant shared by several values of F. Any
+//	                    value of F without a corresponding variant is an
+//	                    error.
+//	-                   The field is not part of the encoding.
+//
+// Unsigned integers are encoded big-endian, fixed size arrays as their
+// elements in order, and structs as their fields in order. Pointers are
+// followed, and allocated when unmarshalling.
+package tls
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// A StructuralError suggests that the Go type or value being (un)marshalled
+// can't be represented in the TLS encoding.
+type StructuralError struct {
+	Field string
+	Msg   string
+}
+
+func (e StructuralError) Error() string {
+	if e.Field == "" {
+		return "tls: structure error: " + e.Msg
+	}
+	return "tls: structure error in " + e.Field + ": " + e.Msg
+}
+
+// A SyntaxError suggests that the data being unmarshalled isn't a valid
+// encoding of the target type.
+type SyntaxError struct {
+	Field string
+	Msg   string
+}
+
+func (e SyntaxError) Error() string {
+	if e.Field == "" {
+		return "tls: syntax error: " + e.Msg
+	}
+	return "tls: syntax error in " + e.Field + ": " + e.Msg
+}
+
+// fieldParams holds the parsed contents of a field's tls tag.
+type fieldParams struct {
+	name     string // for error messages
+	skip     bool
+	hasLen   bool
+	minLen   int
+	maxLen   int
+	size     int
+	selector string
+	vals     []uint64
+	// Bounds on the elements of a slice of slices.
+	hasElemLen bool
+	elemMinLen int
+	elemMaxLen int
+}
+
+func parseFieldParams(name, tag string) (fieldParams, error) {
+	p := fieldParams{name: name}
+	if tag == "" {
+		return p, nil
+	}
+	if tag == "-" {
+		p.skip = true
+		return p, nil
+	}
+	for _, part := range strings.Split(tag, ",") {
+		kv := strings.SplitN(part, ":", 2)
+		if len(kv) != 2 {
+			return p, StructuralError{name, fmt.Sprintf("malformed tag component %q", part)}
+		}
+		key, value := kv[0], kv[1]
+		if key == "selector" {
+			p.selector = value
+			continue
+		}
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return p, StructuralError{name, fmt.Sprintf("malformed value in tag component %q", part)}
+		}
+		switch key {
+		case "minlen":
+			p.hasLen = true
+			p.minLen = int(n)
+		case "maxlen":
+			p.hasLen = true
+			p.maxLen = int(n)
+		case "elemminlen":
+			p.hasElemLen = true
+			p.elemMinLen = int(n)
+		case "elemmaxlen":
+			p.hasElemLen = true
+			p.elemMaxLen = int(n)
+		case "size":
+			p.size = int(n)
+		case "val":
+			p.vals = append(p.vals, n)
+		default:
+			return p, StructuralError{name, fmt.Sprintf("unknown tag key %q", key)}
+		}
+	}
+	if p.hasLen && (p.maxLen == 0 || p.minLen > p.maxLen) {
+		return p, StructuralError{name, fmt.Sprintf("invalid length bounds [%d, %d]", p.minLen, p.maxLen)}
+	}
+	if p.hasElemLen && (p.elemMaxLen == 0 || p.elemMinLen > p.elemMaxLen) {
+		return p, StructuralError{name, fmt.Sprintf("invalid element length bounds [%d, %d]", p.elemMinLen, p.elemMaxLen)}
+	}
+	if p.hasElemLen && !p.hasLen {
+		return p, StructuralError{name, "element length bounds given without maxlen"}
+	}
+	if (p.selector != "") != (len(p.vals) > 0) {
+		return p, StructuralError{name, "selector and val must be given together"}
+	}
+	return p, nil
+}
+
+// elemParams returns the params for the elements of the slice with params |p|.
+func (p fieldParams) elemParams() fieldParams {
+	return fieldParams{name: p.name, hasLen: p.hasElemLen, minLen: p.elemMinLen, maxLen: p.elemMaxLen}
+}
+
+// lengthBytes returns the number of bytes needed to hold |maxLen|.
+func lengthBytes(maxLen int) int {
+	n := 0
+	for ; maxLen > 0; maxLen >>= 8 {
+		n++
+	}
+	return n
+}
+
+// uintSize returns the number of bytes used to encode an unsigned integer of
+// type |t| with params |p|.
+func uintSize(t reflect.Type, p fieldParams) (int, error) {
+	natural := int(t.Size())
+	if p.size == 0 {
+		return natural, nil
+	}
+	if p.size > natural {
+		return 0, StructuralError{p.name, fmt.Sprintf("size %d too large for %v", p.size, t)}
+	}
+	return p.size, nil
+}
+
+func isUint(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func writeUint(w io.Writer, value uint64, numBytes int) error {
+	buf := make([]byte, numBytes)
+	for i := numBytes - 1; i >= 0; i-- {
+		buf[i] = byte(value)
+		value >>= 8
+	}
+	if value != 0 {
+		return fmt.Errorf("value too large for %d bytes", numBytes)
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// readFull wraps io.ReadFull, turning a short read into a SyntaxError.
+func readFull(r io.Reader, buf []byte, p fieldParams) error {
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return SyntaxError{p.name, "truncated data"}
+		}
+		return err
+	}
+	return nil
+}
+
+// selectorCheck tracks the selector values seen while (un)marshalling a
+// struct, and checks that each has a corresponding variant.
+type selectorCheck struct {
+	t        reflect.Type
+	variants map[string][]uint64
+	values   map[string]uint64
+}
+
+func newSelectorCheck(t reflect.Type) (*selectorCheck, []fieldParams, error) {
+	s := &selectorCheck{t: t, variants: make(map[string][]uint64), values: make(map[string]uint64)}
+	params := make([]fieldParams, t.NumField())
+	for i := range params {
+		f := t.Field(i)
+		p, err := parseFieldParams(t.Name()+"."+f.Name, f.Tag.Get("tls"))
+		if err != nil {
+			return nil, nil, err
+		}
+		if !p.skip && f.PkgPath != "" {
+			return nil, nil, StructuralError{p.name, "unexported field"}
+		}
+		if p.selector != "" {
+			sf, ok := t.FieldByName(p.selector)
+			if !ok || sf.Index[0] >= i || !isUint(sf.Type.Kind()) {
+				return nil, nil, StructuralError{p.name, fmt.Sprintf("selector %s is not an earlier unsigned integer field", p.selector)}
+			}
+			s.variants[p.selector] = append(s.variants[p.selector], p.vals...)
+		}
+		params[i] = p
+	}
+	return s, params, nil
+}
+
+// selected reports whether the field with params |p| is present.
+func (s *selectorCheck) selected(p fieldParams) bool {
+	if p.selector == "" {
+		return true
+	}
+	for _, val := range p.vals {
+		if s.values[p.selector] == val {
+			return true
+		}
+	}
+	return false
+}
+
+// record notes the value of field |name|, if it is a selector, and checks
+// that it has a variant.
+func (s *selectorCheck) record(name string, v reflect.Value, syntax bool) error {
+	vals, ok := s.variants[name]
+	if !ok {
+		return nil
+	}
+	value := v.Uint()
+	for _, val := range vals {
+		if val == value {
+			s.values[name] = value
+			return nil
+		}
+	}
+	msg := fmt.Sprintf("unknown %s %d", name, value)
+	if syntax {
+		return SyntaxError{s.t.Name() + "." + name, msg}
+	}
+	return StructuralError{s.t.Name() + "." + name, msg}
+}
+
+func marshalField(w io.Writer, v reflect.Value, p fieldParams) error {
+	t := v.Type()
+	if p.hasLen && t.Kind() != reflect.Slice {
+		return StructuralError{p.name, fmt.Sprintf("length bounds given for %v", t)}
+	}
+	switch t.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size, err := uintSize(t, p)
+		if err != nil {
+			return err
+		}
+		if err := writeUint(w, v.Uint(), size); err != nil {
+			return StructuralError{p.name, err.Error()}
+		}
+		return nil
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			_, err := w.Write(b)
+			return err
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := marshalField(w, v.Index(i), fieldParams{name: p.name}); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if !p.hasLen {
+			return StructuralError{p.name, "slice has no maxlen"}
+		}
+		if p.hasElemLen && t.Elem().Kind() != reflect.Slice {
+			return StructuralError{p.name, fmt.Sprintf("element length bounds given for %v", t)}
+		}
+		var buf bytes.Buffer
+		if t.Elem().Kind() == reflect.Uint8 {
+			buf.Write(v.Bytes())
+		} else {
+			for i := 0; i < v.Len(); i++ {
+				if err := marshalField(&buf, v.Index(i), p.elemParams()); err != nil {
+					return err
+				}
+			}
+		}
+		if buf.Len() < p.minLen || buf.Len() > p.maxLen {
+			return StructuralError{p.name, fmt.Sprintf("length %d outside range [%d, %d]", buf.Len(), p.minLen, p.maxLen)}
+		}
+		if err := writeUint(w, uint64(buf.Len()), lengthBytes(p.maxLen)); err != nil {
+			return err
+		}
+		_, err := w.Write(buf.Bytes())
+		return err
+	case reflect.Struct:
+		s, params, err := newSelectorCheck(t)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < t.NumField(); i++ {
+			fp := params[i]
+			if fp.skip || !s.selected(fp) {
+				continue
+			}
+			if err := marshalField(w, v.Field(i), fp); err != nil {
+				return err
+			}
+			if err := s.record(t.Field(i).Name, v.Field(i), false); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Ptr:
+		if v.IsNil() {
+			return StructuralError{p.name, "nil pointer"}
+		}
+		return marshalField(w, v.Elem(), p)
+	default:
+		return StructuralError{p.name, fmt.Sprintf("unsupported type %v", t)}
+	}
+}
+
+func unmarshalField(r io.Reader, v reflect.Value, p fieldParams) error {
+	t := v.Type()
+	if p.hasLen && t.Kind() != reflect.Slice {
+		return StructuralError{p.name, fmt.Sprintf("length bounds given for %v", t)}
+	}
+	switch t.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size, err := uintSize(t, p)
+		if err != nil {
+			return err
+		}
+		buf := make([]byte, size)
+		if err := readFull(r, buf, p); err != nil {
+			return err
+		}
+		var value uint64
+		for _, b := range buf {
+			value = value<<8 | uint64(b)
+		}
+		v.SetUint(value)
+		return nil
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			if err := readFull(r, b, p); err != nil {
+				return err
+			}
+			reflect.Copy(v, reflect.ValueOf(b))
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := unmarshalField(r, v.Index(i), fieldParams{name: p.name}); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if !p.hasLen {
+			return StructuralError{p.name, "slice has no maxlen"}
+		}
+		if p.hasElemLen && t.Elem().Kind() != reflect.Slice {
+			return StructuralError{p.name, fmt.Sprintf("element length bounds given for %v", t)}
+		}
+		lenBuf := make([]byte, lengthBytes(p.maxLen))
+		if err := readFull(r, lenBuf, p); err != nil {
+			return err
+		}
+		var n uint64
+		for _, b := range lenBuf {
+			n = n<<8 | uint64(b)
+		}
+		if n < uint64(p.minLen) || n > uint64(p.maxLen) {
+			return SyntaxError{p.name, fmt.Sprintf("length %d outside range [%d, %d]", n, p.minLen, p.maxLen)}
+		}
+		data := make([]byte, n)
+		if err := readFull(r, data, p); err != nil {
+			return err
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(data)
+			return nil
+		}
+		s := reflect.MakeSlice(t, 0, 0)
+		er := bytes.NewReader(data)
+		for er.Len() > 0 {
+			elem := reflect.New(t.Elem()).Elem()
+			if err := unmarshalField(er, elem, p.elemParams()); err != nil {
+				return err
+			}
+			s = reflect.Append(s, elem)
+		}
+		v.Set(s)
+		return nil
+	case reflect.Struct:
+		s, params, err := newSelectorCheck(t)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < t.NumField(); i++ {
+			fp := params[i]
+			if fp.skip || !s.selected(fp) {
+				continue
+			}
+			if err := unmarshalField(r, v.Field(i), fp); err != nil {
+				return err
+			}
+			if err := s.record(t.Field(i).Name, v.Field(i), true); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Ptr:
+		v.Set(reflect.New(t.Elem()))
+		return unmarshalField(r, v.Elem(), p)
+	default:
+		return StructuralError{p.name, fmt.Sprintf("unsupported type %v", t)}
+	}
+}
+
+// Write writes the TLS encoding of |val| to |w|.
+// In case of error, w may contain garbage.
+func Write(w io.Writer, val interface{}) error {
+	v := reflect.ValueOf(val)
+	if !v.IsValid() {
+		return StructuralError{"", "cannot marshal nil"}
+	}
+	return marshalField(w, v, fieldParams{})
+}
+
+// Marshal returns the TLS encoding of |val|.
+func Marshal(val interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Write(&buf, val); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Read parses a TLS encoded value from |r| into the value pointed to by |val|,
+// reading no more of |r| than the encoding occupies.
+func Read(r io.Reader, val interface{}) error {
+	v := reflect.ValueOf(val)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return StructuralError{"", fmt.Sprintf("Read needs a non-nil pointer, got %T", val)}
+	}
+	return unmarshalField(r, v.Elem(), fieldParams{})
+}
+
+// Unmarshal parses the TLS encoded data |b| into the value pointed to by
+// |val|, and returns any data remaining after it.
+func Unmarshal(b []byte, val interface{}) ([]byte, error) {
+	r := bytes.NewReader(b)
+	if err := Read(r, val); err != nil {
+		return nil, err
+	}
+	return b[len(b)-r.Len():], nil
+}
@@ -0,0 +1,220 @@
+package tls
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testEnum uint8
+
+type testInner struct {
+	Val  uint16
+	Data []byte `tls:"minlen:0,maxlen:255"`
+}
+
+type testStruct struct {
+	A       uint8
+	B       uint32 `tls:"size:3"`
+	Hash    [4]byte
+	Kind    testEnum
+	Small   *testInner  `tls:"selector:Kind,val:1,val:3"`
+	Big     uint64      `tls:"selector:Kind,val:2"`
+	List    []testInner `tls:"minlen:1,maxlen:65535"`
+	Ignored string      `tls:"-"`
+}
+
+func dh(t *testing.T, h string) []byte {
+	b, err := hex.DecodeString(strings.Replace(h, " ", "", -1))
+	if err != nil {
+		t.Fatalf("bad hex %q: %v", h, err)
+	}
+	return b
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		val  testStruct
+		data string
+	}{
+		{
+			testStruct{A: 1, B: 0x020304, Hash: [4]byte{5, 6, 7, 8}, Kind: 1,
+				Small: &testInner{Val: 0x0a0b, Data: []byte{0xcc}},
+				List:  []testInner{{Val: 1, Data: []byte{}}, {Val: 2, Data: []byte{0xdd, 0xee}}}},
+			"01 020304 05060708 01 0a0b01cc 0008 000100 000202ddee",
+		},
+		{
+			testStruct{A: 0xff, Hash: [4]byte{}, Kind: 2, Big: 0x0102030405060708,
+				List: []testInner{{Val: 0xffff, Data: []byte{}}}},
+			"ff 000000 00000000 02 0102030405060708 0003 ffff00",
+		},
+		{
+			testStruct{A: 2, Hash: [4]byte{}, Kind: 3,
+				Small: &testInner{Val: 1, Data: []byte{}},
+				List:  []testInner{{Val: 2, Data: []byte{}}}},
+			"02 000000 00000000 03 000100 0003 000200",
+		},
+	} {
+		want := dh(t, test.data)
+		got, err := Marshal(test.val)
+		if err != nil {
+			t.Errorf("Marshal(%+v)=%v", test.val, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("Marshal(%+v)=%x, want %x", test.val, got, want)
+		}
+
+		var val testStruct
+		rest, err := Unmarshal(append(want, 0x99), &val)
+		if err != nil {
+			t.Errorf("Unmarshal(%x)=%v", want, err)
+			continue
+		}
+		if !bytes.Equal(rest, []byte{0x99}) {
+			t.Errorf("Unmarshal(%x) left %x, want 99", want, rest)
+		}
+		if !reflect.DeepEqual(val, test.val) {
+			t.Errorf("Unmarshal(%x)=%+v, want %+v", want, val, test.val)
+		}
+	}
+}
+
+func TestReadDoesNotOverread(t *testing.T) {
+	r := bytes.NewReader(dh(t, "0102 00 0304 01ff"))
+	var a, b testInner
+	if err := Read(r, &a); err != nil {
+		t.Fatalf("Read()=%v", err)
+	}
+	if err := Read(r, &b); err != nil {
+		t.Fatalf("second Read()=%v", err)
+	}
+	if a.Val != 0x0102 || b.Val != 0x0304 || !bytes.Equal(b.Data, []byte{0xff}) || r.Len() != 0 {
+		t.Errorf("Read() gave %+v, %+v with %d bytes left", a, b, r.Len())
+	}
+}
+
+// testChain is a vector of vectors, like an ASN.1Cert list.
+type testChain struct {
+	Certs [][]byte `tls:"minlen:0,maxlen:65535,elemminlen:1,elemmaxlen:255"`
+}
+
+func TestVectorOfVectors(t *testing.T) {
+	for _, test := range []struct {
+		val  testChain
+		data string
+	}{
+		{testChain{Certs: [][]byte{}}, "0000"},
+		{testChain{Certs: [][]byte{{0xaa}, {0xbb, 0xcc}}}, "0005 01aa 02bbcc"},
+	} {
+		want := dh(t, test.data)
+		got, err := Marshal(test.val)
+		if err != nil {
+			t.Errorf("Marshal(%+v)=%v", test.val, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("Marshal(%+v)=%x, want %x", test.val, got, want)
+		}
+		var val testChain
+		if _, err := Unmarshal(want, &val); err != nil {
+			t.Errorf("Unmarshal(%x)=%v", want, err)
+			continue
+		}
+		if !reflect.DeepEqual(val, test.val) {
+			t.Errorf("Unmarshal(%x)=%+v, want %+v", want, val, test.val)
+		}
+	}
+
+	if _, err := Marshal(testChain{Certs: [][]byte{{}}}); err == nil {
+		t.Error("Marshal() with empty element succeeded, want error")
+	}
+	if _, err := Marshal(testChain{Certs: [][]byte{make([]byte, 256)}}); err == nil {
+		t.Error("Marshal() with oversized element succeeded, want error")
+	}
+	for _, data := range []string{"0001 00", "0003 03aabb"} {
+		var val testChain
+		if _, err := Unmarshal(dh(t, data), &val); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded, want error", data)
+		} else if _, ok := err.(SyntaxError); !ok {
+			t.Errorf("Unmarshal(%s)=%v, want SyntaxError", data, err)
+		}
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	for _, test := range []struct {
+		desc string
+		val  interface{}
+	}{
+		{"nil", nil},
+		{"signed integer", struct{ A int }{}},
+		{"value too large for size", testStruct{B: 0x01000000, Kind: 2, List: []testInner{{}}}},
+		{"no variant", testStruct{Kind: 3, List: []testInner{{}}}},
+		{"nil variant", testStruct{Kind: 1, List: []testInner{{}}}},
+		{"vector too short", testStruct{Kind: 2}},
+		{"vector too long", testInner{Data: make([]byte, 256)}},
+		{"slice without maxlen", struct{ A []byte }{}},
+		{"bounds on non-slice", struct {
+			A uint8 `tls:"maxlen:10"`
+		}{}},
+		{"size too large", struct {
+			A uint8 `tls:"size:2"`
+		}{}},
+		{"bad tag", struct {
+			A uint8 `tls:"wibble:1"`
+		}{}},
+		{"selector later field", struct {
+			A uint8 `tls:"selector:B,val:1"`
+			B uint8
+		}{}},
+		{"element bounds on byte slice", struct {
+			A []byte `tls:"maxlen:10,elemmaxlen:10"`
+		}{}},
+		{"element bounds without maxlen", struct {
+			A [][]byte `tls:"elemmaxlen:10"`
+		}{}},
+		{"invalid element bounds", struct {
+			A [][]byte `tls:"maxlen:10,elemminlen:5,elemmaxlen:4"`
+		}{}},
+		{"selector not integer", struct {
+			A []byte `tls:"maxlen:1"`
+			B uint8  `tls:"selector:A,val:1"`
+		}{}},
+	} {
+		if _, err := Marshal(test.val); err == nil {
+			t.Errorf("%s: Marshal() succeeded, want error", test.desc)
+		} else if _, ok := err.(StructuralError); !ok {
+			t.Errorf("%s: Marshal()=%v, want StructuralError", test.desc, err)
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	for _, test := range []struct {
+		desc string
+		data string
+	}{
+		{"empty", ""},
+		{"truncated integer", "01 0203"},
+		{"truncated array", "01 020304 0506"},
+		{"no variant", "01 020304 05060708 04 0003 ffff00"},
+		{"truncated variant", "01 020304 05060708 02 01020304"},
+		{"vector too short", "01 020304 05060708 02 0102030405060708 0000"},
+		{"truncated vector", "01 020304 05060708 02 0102030405060708 0004 ffff00"},
+		{"truncated vector element", "01 020304 05060708 02 0102030405060708 0004 ffff0201"},
+	} {
+		var val testStruct
+		if _, err := Unmarshal(dh(t, test.data), &val); err == nil {
+			t.Errorf("%s: Unmarshal() succeeded, want error", test.desc)
+		} else if _, ok := err.(SyntaxError); !ok {
+			t.Errorf("%s: Unmarshal()=%v, want SyntaxError", test.desc, err)
+		}
+	}
+	var val testStruct
+	if _, err := Unmarshal(nil, val); err == nil {
+		t.Error("Unmarshal() into non-pointer succeeded, want error")
+	}
+}
@@ -105,7 +105,21 @@ type ASN1Cert []byte
 // PreCert represents a Precertificate (section 3.2)
 type PreCert struct {
 	IssuerKeyHash  [issuerKeyHashLength]byte
-	TBSCertificate []byte
+	TBSCertificate []byte `tls:"minlen:1,maxlen:16777215"` // DER-encoded TBSCertificate
+}
+
+// CertificateChain holds the extra_data of an X509 log entry: the chain from
+// the certificate's issuer up to a root accepted by the log (section 4.6).
+type CertificateChain struct {
+	Entries []ASN1Cert `tls:"minlen:0,maxlen:16777215,elemminlen:1,elemmaxlen:16777215"`
+}
+
+// PrecertChainEntry holds the extra_data of a Precertificate log entry: the
+// submitted Precertificate and the chain from its issuer up to a root accepted
+// by the log (section 4.6).
+type PrecertChainEntry struct {
+	PreCertificate   ASN1Cert   `tls:"minlen:1,maxlen:16777215"`
+	CertificateChain []ASN1Cert `tls:"minlen:0,maxlen:16777215,elemminlen:1,elemmaxlen:16777215"`
 }
 
 // CTExtensions is a representation of the raw bytes of any CtExtension
@@ -137,6 +151,9 @@ const (
 	SHA256 HashAlgorithm = 4
 	SHA384 HashAlgorithm = 5
 	SHA512 HashAlgorithm = 6
+	// Intrinsic is used with signature algorithms which specify their own
+	// hashing, see RFC8422 section 5.1.3.
+	Intrinsic HashAlgorithm = 8
 )
 
 func (h HashAlgorithm) String() string {
@@ -155,6 +172,8 @@ func (h HashAlgorithm) String() string {
 		return "SHA384"
 	case SHA512:
 		return "SHA512"
+	case Intrinsic:
+		return "Intrinsic"
 	default:
 		return fmt.Sprintf("UNKNOWN(%d)", h)
 	}
@@ -169,6 +188,12 @@ const (
 	RSA       SignatureAlgorithm = 1
 	DSA       SignatureAlgorithm = 2
 	ECDSA     SignatureAlgorithm = 3
+	// RSA-PSS and Ed25519 are used with the Intrinsic HashAlgorithm; see
+	// RFC8446 section 4.2.3 and RFC8422 section 5.1.3.
+	RSAPSSWithSHA256 SignatureAlgorithm = 4
+	RSAPSSWithSHA384 SignatureAlgorithm = 5
+	RSAPSSWithSHA512 SignatureAlgorithm = 6
+	Ed25519          SignatureAlgorithm = 7
 )
 
 func (s SignatureAlgorithm) String() string {
@@ -181,6 +206,14 @@ func (s SignatureAlgorithm) String() string {
 		return "DSA"
 	case ECDSA:
 		return "ECDSA"
+	case RSAPSSWithSHA256:
+		return "RSAPSSWithSHA256"
+	case RSAPSSWithSHA384:
+		return "RSAPSSWithSHA384"
+	case RSAPSSWithSHA512:
+		return "RSAPSSWithSHA512"
+	case Ed25519:
+		return "Ed25519"
 	default:
 		return fmt.Sprintf("UNKNOWN(%d)", s)
 	}
@@ -190,7 +223,7 @@ func (s SignatureAlgorithm) String() string {
 type DigitallySigned struct {
 	HashAlgorithm      HashAlgorithm
 	SignatureAlgorithm SignatureAlgorithm
-	Signature          []byte
+	Signature          []byte `tls:"minlen:0,maxlen:65535"`
 }
 
 // FromBase64String populates the DigitallySigned structure from the base64 data passed in.
@@ -296,11 +329,11 @@ type SignedTreeHead struct {
 // 3.2 ,4.1 and 4.2)
 type SignedCertificateTimestamp struct {
 	SCTVersion Version    // The version of the protocol to which the SCT conforms
-	LogID      SHA256Hash // the SHA-256 hash of the log's public key, calculated over
+	LogID      SHA256Hash `tls:"selector:SCTVersion,val:0"` // the SHA-256 hash of the log's public key, calculated over
 	// the DER encoding of the key represented as SubjectPublicKeyInfo.
-	Timestamp  uint64          // Timestamp (in ms since unix epoc) at which the SCT was issued
-	Extensions CTExtensions    // For future extensions to the protocol
-	Signature  DigitallySigned // The Log's signature for this SCT
+	Timestamp  uint64          `tls:"selector:SCTVersion,val:0"`                       // Timestamp (in ms since unix epoc) at which the SCT was issued
+	Extensions CTExtensions    `tls:"selector:SCTVersion,val:0,minlen:0,maxlen:65535"` // For future extensions to the protocol
+	Signature  DigitallySigned `tls:"selector:SCTVersion,val:0"`                       // The Log's signature for this SCT
 }
 
 func (s SignedCertificateTimestamp) String() string {
@@ -316,18 +349,41 @@ func (s SignedCertificateTimestamp) String() string {
 type TimestampedEntry struct {
 	Timestamp    uint64
 	EntryType    LogEntryType
-	X509Entry    ASN1Cert
-	JSONData     []byte
-	PrecertEntry PreCert
-	Extensions   CTExtensions
+	X509Entry    ASN1Cert     `tls:"selector:EntryType,val:0,minlen:1,maxlen:16777215"`
+	JSONData     []byte       `tls:"selector:EntryType,val:32768,minlen:0,maxlen:16777215"`
+	PrecertEntry PreCert      `tls:"selector:EntryType,val:1"`
+	Extensions   CTExtensions `tls:"minlen:0,maxlen:65535"`
 }
 
 // MerkleTreeLeaf represents the deserialized sructure of the hash input for the
 // leaves of a log's Merkle tree. See RFC section 3.4
 type MerkleTreeLeaf struct {
 	Version          Version          // the version of the protocol to which the MerkleTreeLeaf corresponds
-	LeafType         MerkleLeafType   // The type of the leaf input, currently only TimestampedEntry can exist
-	TimestampedEntry TimestampedEntry // The entry data itself
+	LeafType         MerkleLeafType   `tls:"selector:Version,val:0"`  // The type of the leaf input, currently only TimestampedEntry can exist
+	TimestampedEntry TimestampedEntry `tls:"selector:LeafType,val:0"` // The entry data itself
+}
+
+// CertificateTimestamp is the data over which a log signs to produce an SCT.
+// See RFC section 3.2
+type CertificateTimestamp struct {
+	SCTVersion    Version
+	SignatureType SignatureType
+	Timestamp     uint64
+	EntryType     LogEntryType
+	X509Entry     ASN1Cert     `tls:"selector:EntryType,val:0,minlen:1,maxlen:16777215"`
+	JSONEntry     []byte       `tls:"selector:EntryType,val:32768,minlen:0,maxlen:16777215"`
+	PrecertEntry  PreCert      `tls:"selector:EntryType,val:1"`
+	Extensions    CTExtensions `tls:"minlen:0,maxlen:65535"`
+}
+
+// TreeHeadSignature is the data over which a log signs to produce an STH.
+// See RFC section 3.5
+type TreeHeadSignature struct {
+	Version        Version
+	SignatureType  SignatureType
+	Timestamp      uint64
+	TreeSize       uint64
+	SHA256RootHash SHA256Hash
 }
 
 // Precertificate represents the parsed CT Precertificate structure.
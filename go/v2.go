@@ -0,0 +1,281 @@
+package ct
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/certificate-transparency/go/tls"
+)
+
+// This file holds the data structures defined by RFC6962-bis (CT v2), in
+// which every structure exchanged with a log is wrapped in a TransItem, log
+// IDs are OIDs, and SCTs and STHs carry typed extensions.
+
+// VersionedTransType represents the VersionedTransType enum from section 4.4
+// of RFC6962-bis, which identifies the contents of a TransItem.
+type VersionedTransType uint16
+
+func (t VersionedTransType) String() string {
+	switch t {
+	case X509EntryV2TransType:
+		return "X509EntryV2"
+	case PrecertEntryV2TransType:
+		return "PrecertEntryV2"
+	case X509SCTV2TransType:
+		return "X509SCTV2"
+	case PrecertSCTV2TransType:
+		return "PrecertSCTV2"
+	case SignedTreeHeadV2TransType:
+		return "SignedTreeHeadV2"
+	case ConsistencyProofV2TransType:
+		return "ConsistencyProofV2"
+	case InclusionProofV2TransType:
+		return "InclusionProofV2"
+	default:
+		return fmt.Sprintf("UnknownVersionedTransType(%d)", t)
+	}
+}
+
+// VersionedTransType constants, see section 4.4 of RFC6962-bis.
+const (
+	X509EntryV2TransType        VersionedTransType = 1
+	PrecertEntryV2TransType     VersionedTransType = 2
+	X509SCTV2TransType          VersionedTransType = 3
+	PrecertSCTV2TransType       VersionedTransType = 4
+	SignedTreeHeadV2TransType   VersionedTransType = 5
+	ConsistencyProofV2TransType VersionedTransType = 6
+	InclusionProofV2TransType   VersionedTransType = 7
+)
+
+// Length limits on log IDs, which are encoded as opaque<2..127>.
+const (
+	minLogIDV2Length = 2
+	maxLogIDV2Length = 127
+)
+
+// LogIDV2 identifies a log in RFC6962-bis: it holds the contents octets (i.e.
+// the DER encoding minus the tag and length) of the log's OID.
+type LogIDV2 []byte
+
+// LogIDV2FromOID returns the LogIDV2 for the log with the given |oid|.
+func LogIDV2FromOID(oid asn1.ObjectIdentifier) (LogIDV2, error) {
+	der, err := asn1.Marshal(oid)
+	if err != nil {
+		return nil, err
+	}
+	var raw asn1.RawValue
+	if _, err := asn1.Unmarshal(der, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw.Bytes) < minLogIDV2Length || len(raw.Bytes) > maxLogIDV2Length {
+		return nil, fmt.Errorf("OID %v encodes to %d bytes, log IDs must be %d to %d bytes", oid, len(raw.Bytes), minLogIDV2Length, maxLogIDV2Length)
+	}
+	return LogIDV2(raw.Bytes), nil
+}
+
+// OID returns the log's OID.
+func (id LogIDV2) OID() (asn1.ObjectIdentifier, error) {
+	if len(id) < minLogIDV2Length || len(id) > maxLogIDV2Length {
+		return nil, fmt.Errorf("invalid log ID length %d", len(id))
+	}
+	var oid asn1.ObjectIdentifier
+	if rest, err := asn1.Unmarshal(append([]byte{asn1.TagOID, byte(len(id))}, id...), &oid); err != nil {
+		return nil, fmt.Errorf("invalid log ID: %v", err)
+	} else if len(rest) > 0 {
+		return nil, errors.New("trailing data after log ID")
+	}
+	return oid, nil
+}
+
+// SCTExtensionType represents the SctExtensionType enum from section 4.5 of
+// RFC6962-bis. No extension types have been defined yet.
+type SCTExtensionType uint16
+
+// SCTExtension is a single extension carried in an SCT or certificate entry.
+type SCTExtension struct {
+	Type SCTExtensionType
+	Data []byte `tls:"minlen:0,maxlen:65535"`
+}
+
+// STHExtensionType represents the SthExtensionType enum from section 4.8 of
+// RFC6962-bis. No extension types have been defined yet.
+type STHExtensionType uint16
+
+// STHExtension is a single extension carried in an STH.
+type STHExtension struct {
+	Type STHExtensionType
+	Data []byte `tls:"minlen:0,maxlen:65535"`
+}
+
+// TimestampedCertificateEntryDataV2 is the content of x509_entry_v2 and
+// precert_entry_v2 TransItems, see section 4.6 of RFC6962-bis.
+type TimestampedCertificateEntryDataV2 struct {
+	Timestamp      uint64
+	IssuerKeyHash  []byte         `tls:"minlen:32,maxlen:255"`
+	TBSCertificate []byte         `tls:"minlen:1,maxlen:16777215"`
+	SCTExtensions  []SCTExtension `tls:"minlen:0,maxlen:65535"`
+}
+
+// SignedCertificateTimestampDataV2 is the content of x509_sct_v2 and
+// precert_sct_v2 TransItems, see section 4.8 of RFC6962-bis.
+type SignedCertificateTimestampDataV2 struct {
+	LogID         LogIDV2 `tls:"minlen:2,maxlen:127"`
+	Timestamp     uint64
+	SCTExtensions []SCTExtension `tls:"minlen:0,maxlen:65535"`
+	Signature     []byte         `tls:"minlen:0,maxlen:65535"`
+}
+
+// TreeHeadDataV2 is the data over which a log signs to produce an STH, see
+// section 4.9 of RFC6962-bis.
+type TreeHeadDataV2 struct {
+	Timestamp     uint64
+	TreeSize      uint64
+	RootHash      []byte         `tls:"minlen:32,maxlen:255"`
+	STHExtensions []STHExtension `tls:"minlen:0,maxlen:65535"`
+}
+
+// SignedTreeHeadDataV2 is the content of signed_tree_head_v2 TransItems, see
+// section 4.10 of RFC6962-bis.
+type SignedTreeHeadDataV2 struct {
+	LogID     LogIDV2 `tls:"minlen:2,maxlen:127"`
+	TreeHead  TreeHeadDataV2
+	Signature []byte `tls:"minlen:0,maxlen:65535"`
+}
+
+// ConsistencyProofDataV2 is the content of consistency_proof_v2 TransItems,
+// see section 4.11 of RFC6962-bis.
+type ConsistencyProofDataV2 struct {
+	LogID           LogIDV2 `tls:"minlen:2,maxlen:127"`
+	TreeSize1       uint64
+	TreeSize2       uint64
+	ConsistencyPath [][]byte `tls:"minlen:0,maxlen:65535,elemminlen:32,elemmaxlen:255"`
+}
+
+// InclusionProofDataV2 is the content of inclusion_proof_v2 TransItems, see
+// section 4.12 of RFC6962-bis.
+type InclusionProofDataV2 struct {
+	LogID         LogIDV2 `tls:"minlen:2,maxlen:127"`
+	TreeSize      uint64
+	LeafIndex     uint64
+	InclusionPath [][]byte `tls:"minlen:0,maxlen:65535,elemminlen:32,elemmaxlen:255"`
+}
+
+// TransItem represents the TransItem structure from section 4.4 of
+// RFC6962-bis. Exactly one of the data fields is set, according to Type; the
+// val:s in their tls tags are the VersionedTransType constants.
+type TransItem struct {
+	Type VersionedTransType
+	// CertificateEntry is set for X509EntryV2TransType and
+	// PrecertEntryV2TransType.
+	CertificateEntry *TimestampedCertificateEntryDataV2 `tls:"selector:Type,val:1,val:2"`
+	// SCT is set for X509SCTV2TransType and PrecertSCTV2TransType.
+	SCT *SignedCertificateTimestampDataV2 `tls:"selector:Type,val:3,val:4"`
+	// STH is set for SignedTreeHeadV2TransType.
+	STH *SignedTreeHeadDataV2 `tls:"selector:Type,val:5"`
+	// ConsistencyProof is set for ConsistencyProofV2TransType.
+	ConsistencyProof *ConsistencyProofDataV2 `tls:"selector:Type,val:6"`
+	// InclusionProof is set for InclusionProofV2TransType.
+	InclusionProof *InclusionProofDataV2 `tls:"selector:Type,val:7"`
+}
+
+// checkExtensionOrder checks that the types of the |n| extensions returned by
+// |extType| are in strictly ascending order, as RFC6962-bis requires.
+func checkExtensionOrder(n int, extType func(i int) uint16) error {
+	for i := 1; i < n; i++ {
+		if t, prev := extType(i), extType(i-1); t <= prev {
+			return fmt.Errorf("extension type %d follows type %d, extensions must be in ascending order of type", t, prev)
+		}
+	}
+	return nil
+}
+
+func checkSCTExtensions(exts []SCTExtension) error {
+	return checkExtensionOrder(len(exts), func(i int) uint16 { return uint16(exts[i].Type) })
+}
+
+func checkSTHExtensions(exts []STHExtension) error {
+	return checkExtensionOrder(len(exts), func(i int) uint16 { return uint16(exts[i].Type) })
+}
+
+// checkExtensions checks the order of the extensions in |item|, which the TLS
+// encoding can't express.
+func (item *TransItem) checkExtensions() error {
+	switch {
+	case item.CertificateEntry != nil:
+		return checkSCTExtensions(item.CertificateEntry.SCTExtensions)
+	case item.SCT != nil:
+		return checkSCTExtensions(item.SCT.SCTExtensions)
+	case item.STH != nil:
+		return checkSTHExtensions(item.STH.TreeHead.STHExtensions)
+	}
+	return nil
+}
+
+// SerializeTreeHeadDataV2 writes |th| to |w|. This is the input to the log's
+// signature on an STH.
+// In case of error, w may contain garbage.
+func SerializeTreeHeadDataV2(w io.Writer, th *TreeHeadDataV2) error {
+	if err := checkSTHExtensions(th.STHExtensions); err != nil {
+		return err
+	}
+	return tls.Write(w, th)
+}
+
+// ReadTreeHeadDataV2 parses a TreeHeadDataV2 from |r|.
+func ReadTreeHeadDataV2(r io.Reader) (*TreeHeadDataV2, error) {
+	var th TreeHeadDataV2
+	if err := tls.Read(r, &th); err != nil {
+		return nil, err
+	}
+	if err := checkSTHExtensions(th.STHExtensions); err != nil {
+		return nil, err
+	}
+	return &th, nil
+}
+
+// WriteTransItem writes |item| to |w|.
+// In case of error, w may contain garbage.
+func WriteTransItem(w io.Writer, item *TransItem) error {
+	if err := item.checkExtensions(); err != nil {
+		return err
+	}
+	return tls.Write(w, item)
+}
+
+// ReadTransItem parses a TransItem from |r|.
+func ReadTransItem(r io.Reader) (*TransItem, error) {
+	var item TransItem
+	if err := tls.Read(r, &item); err != nil {
+		return nil, err
+	}
+	if err := item.checkExtensions(); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// SerializeTransItem returns the TLS encoding of |item|.
+func SerializeTransItem(item *TransItem) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteTransItem(&buf, item); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DeserializeTransItem parses the TLS encoded TransItem |b|, which must not
+// be followed by any other data.
+func DeserializeTransItem(b []byte) (*TransItem, error) {
+	r := bytes.NewReader(b)
+	item, err := ReadTransItem(r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() > 0 {
+		return nil, fmt.Errorf("trailing data after %v TransItem", item.Type)
+	}
+	return item, nil
+}
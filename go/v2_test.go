@@ -0,0 +1,184 @@
+package ct
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLogIDV2(t *testing.T) {
+	oid := asn1.ObjectIdentifier{1, 3, 101, 8192}
+	id, err := LogIDV2FromOID(oid)
+	if err != nil {
+		t.Fatalf("LogIDV2FromOID(%v)=%v", oid, err)
+	}
+	if want := []byte{0x2b, 0x65, 0xc0, 0x00}; !bytes.Equal(id, want) {
+		t.Errorf("LogIDV2FromOID(%v)=%x, want %x", oid, []byte(id), want)
+	}
+	got, err := id.OID()
+	if err != nil {
+		t.Fatalf("OID()=%v", err)
+	}
+	if !got.Equal(oid) {
+		t.Errorf("OID()=%v, want %v", got, oid)
+	}
+
+	if _, err := LogIDV2FromOID(asn1.ObjectIdentifier{1, 3}); err == nil {
+		t.Error("LogIDV2FromOID() of single byte OID succeeded, want error")
+	}
+	if _, err := (LogIDV2{0x2b, 0x80}).OID(); err == nil {
+		t.Error("OID() of truncated log ID succeeded, want error")
+	}
+}
+
+func testLogIDV2() LogIDV2 {
+	return LogIDV2{0x2b, 0x65, 0xc0, 0x00}
+}
+
+func testHash(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestTransItemSCTEncoding(t *testing.T) {
+	item := &TransItem{
+		Type: X509SCTV2TransType,
+		SCT: &SignedCertificateTimestampDataV2{
+			LogID:         testLogIDV2(),
+			Timestamp:     0x0102030405060708,
+			SCTExtensions: []SCTExtension{{Type: 1, Data: []byte{0xaa}}, {Type: 7, Data: []byte{}}},
+			Signature:     []byte("sig"),
+		},
+	}
+	want := mustDehex(t, strings.Join([]string{
+		"0003",             // x509_sct_v2
+		"04" + "2b65c000",  // log_id
+		"0102030405060708", // timestamp
+		"0009",             // sct_extensions length
+		"0001" + "0001aa",  // sct_extensions[0]
+		"0007" + "0000",    // sct_extensions[1]
+		"0003" + "736967",  // signature
+	}, ""))
+	b, err := SerializeTransItem(item)
+	if err != nil {
+		t.Fatalf("SerializeTransItem()=%v", err)
+	}
+	if !bytes.Equal(b, want) {
+		t.Fatalf("SerializeTransItem()=%x, want %x", b, want)
+	}
+	got, err := DeserializeTransItem(b)
+	if err != nil {
+		t.Fatalf("DeserializeTransItem()=%v", err)
+	}
+	if !reflect.DeepEqual(got, item) {
+		t.Errorf("DeserializeTransItem()=%+v, want %+v", got.SCT, item.SCT)
+	}
+}
+
+func TestTransItemRoundTrip(t *testing.T) {
+	for _, item := range []*TransItem{
+		{Type: X509EntryV2TransType, CertificateEntry: &TimestampedCertificateEntryDataV2{
+			Timestamp:      1234,
+			IssuerKeyHash:  testHash(1),
+			TBSCertificate: []byte("tbs"),
+			SCTExtensions:  []SCTExtension{},
+		}},
+		{Type: PrecertEntryV2TransType, CertificateEntry: &TimestampedCertificateEntryDataV2{
+			Timestamp:      1234,
+			IssuerKeyHash:  testHash(2),
+			TBSCertificate: []byte("precert tbs"),
+			SCTExtensions:  []SCTExtension{{Type: 3, Data: []byte("ext")}},
+		}},
+		{Type: PrecertSCTV2TransType, SCT: &SignedCertificateTimestampDataV2{
+			LogID:         testLogIDV2(),
+			Timestamp:     5678,
+			SCTExtensions: []SCTExtension{},
+			Signature:     []byte{},
+		}},
+		{Type: SignedTreeHeadV2TransType, STH: &SignedTreeHeadDataV2{
+			LogID: testLogIDV2(),
+			TreeHead: TreeHeadDataV2{
+				Timestamp:     91011,
+				TreeSize:      42,
+				RootHash:      testHash(3),
+				STHExtensions: []STHExtension{{Type: 1, Data: []byte("a")}, {Type: 2, Data: []byte("b")}},
+			},
+			Signature: []byte("sth sig"),
+		}},
+		{Type: ConsistencyProofV2TransType, ConsistencyProof: &ConsistencyProofDataV2{
+			LogID:           testLogIDV2(),
+			TreeSize1:       3,
+			TreeSize2:       7,
+			ConsistencyPath: [][]byte{testHash(4), testHash(5), testHash(6)},
+		}},
+		{Type: InclusionProofV2TransType, InclusionProof: &InclusionProofDataV2{
+			LogID:         testLogIDV2(),
+			TreeSize:      1,
+			LeafIndex:     0,
+			InclusionPath: [][]byte{},
+		}},
+	} {
+		b, err := SerializeTransItem(item)
+		if err != nil {
+			t.Errorf("%v: SerializeTransItem()=%v", item.Type, err)
+			continue
+		}
+		got, err := DeserializeTransItem(b)
+		if err != nil {
+			t.Errorf("%v: DeserializeTransItem()=%v", item.Type, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, item) {
+			t.Errorf("%v: DeserializeTransItem(SerializeTransItem(%+v))=%+v", item.Type, item, got)
+		}
+		if _, err := DeserializeTransItem(b[:len(b)-1]); err == nil {
+			t.Errorf("%v: DeserializeTransItem() of truncated item succeeded, want error", item.Type)
+		}
+		if _, err := DeserializeTransItem(append(b, 0)); err == nil {
+			t.Errorf("%v: DeserializeTransItem() with trailing data succeeded, want error", item.Type)
+		}
+	}
+}
+
+func TestSerializeTransItemErrors(t *testing.T) {
+	for _, test := range []struct {
+		desc string
+		item *TransItem
+	}{
+		{"unknown type", &TransItem{Type: 99}},
+		{"missing data", &TransItem{Type: X509SCTV2TransType}},
+		{"short log ID", &TransItem{Type: X509SCTV2TransType, SCT: &SignedCertificateTimestampDataV2{LogID: LogIDV2{1}}}},
+		{"short issuer key hash", &TransItem{Type: X509EntryV2TransType, CertificateEntry: &TimestampedCertificateEntryDataV2{
+			IssuerKeyHash: []byte{1}, TBSCertificate: []byte("tbs")}}},
+		{"empty TBSCertificate", &TransItem{Type: X509EntryV2TransType, CertificateEntry: &TimestampedCertificateEntryDataV2{
+			IssuerKeyHash: testHash(1)}}},
+		{"extensions out of order", &TransItem{Type: X509SCTV2TransType, SCT: &SignedCertificateTimestampDataV2{
+			LogID: testLogIDV2(), SCTExtensions: []SCTExtension{{Type: 2}, {Type: 1}}}}},
+		{"duplicate extensions", &TransItem{Type: SignedTreeHeadV2TransType, STH: &SignedTreeHeadDataV2{
+			LogID: testLogIDV2(), TreeHead: TreeHeadDataV2{RootHash: testHash(1), STHExtensions: []STHExtension{{Type: 1}, {Type: 1}}}}}},
+		{"short path hash", &TransItem{Type: InclusionProofV2TransType, InclusionProof: &InclusionProofDataV2{
+			LogID: testLogIDV2(), InclusionPath: [][]byte{{1, 2, 3}}}}},
+	} {
+		if _, err := SerializeTransItem(test.item); err == nil {
+			t.Errorf("%s: SerializeTransItem() succeeded, want error", test.desc)
+		}
+	}
+}
+
+func TestDeserializeTransItemErrors(t *testing.T) {
+	for _, test := range []struct {
+		desc string
+		data string
+	}{
+		{"empty", ""},
+		{"unknown type", "0063"},
+		{"short log ID", "0003" + "012b" + "0000000000000000" + "0000" + "0000"},
+		{"extensions out of order", "0003" + "042b65c000" + "0000000000000000" + "0008" + "00020000" + "00010000" + "0000"},
+		{"short root hash", "0005" + "042b65c000" + "0000000000000000" + "0000000000000000" + "0101" + "0000" + "0000"},
+	} {
+		if _, err := DeserializeTransItem(mustDehex(t, test.data)); err == nil {
+			t.Errorf("%s: DeserializeTransItem() succeeded, want error", test.desc)
+		}
+	}
+}
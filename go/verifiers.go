@@ -0,0 +1,89 @@
+package ct
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// UnknownLogError is returned when an SCT or STH was issued by a log for
+// which no SignatureVerifier is known.
+type UnknownLogError struct {
+	LogID SHA256Hash
+}
+
+func (e UnknownLogError) Error() string {
+	return fmt.Sprintf("unknown log %s", e.LogID.Base64String())
+}
+
+// LogVerifiers holds a SignatureVerifier for each of a set of known logs,
+// keyed by log ID.
+type LogVerifiers map[SHA256Hash]SignatureVerifier
+
+// AddPEM adds a verifier for each of the PEM encoded public keys in |pemData|,
+// created with |opts|.
+func (v LogVerifiers) AddPEM(pemData []byte, opts ...VerifierOption) error {
+	for len(pemData) > 0 {
+		key, id, rest, err := PublicKeyFromPEM(pemData)
+		if err != nil {
+			return fmt.Errorf("failed to read public key from PEM: %v", err)
+		}
+		sv, err := NewSignatureVerifier(key, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to create SignatureVerifier for log %s: %v", id.Base64String(), err)
+		}
+		v[id] = *sv
+		pemData = rest
+	}
+	return nil
+}
+
+// AddPEMFile adds a verifier for each of the PEM encoded public keys in the
+// file |filename|, created with |opts|.
+func (v LogVerifiers) AddPEMFile(filename string, opts ...VerifierOption) error {
+	pemData, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read PEM file: %v", err)
+	}
+	if err := v.AddPEM(pemData, opts...); err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	return nil
+}
+
+// AddPEMDir adds a verifier for each of the PEM encoded public keys in the
+// files with a .pem extension in the directory |dir|, created with |opts|.
+func (v LogVerifiers) AddPEMDir(dir string, opts ...VerifierOption) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := v.AddPEMFile(f, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifySCT verifies the signature on |sct| over |entry| using the verifier
+// for the log which issued it. Returns an UnknownLogError if there is no
+// verifier for that log.
+func (v LogVerifiers) VerifySCT(sct SignedCertificateTimestamp, entry LogEntry) error {
+	sv, ok := v[sct.LogID]
+	if !ok {
+		return UnknownLogError{LogID: sct.LogID}
+	}
+	return sv.VerifySCTSignature(sct, entry)
+}
+
+// VerifySTH verifies the signature on |sth| using the verifier for the log
+// which issued it. Returns an UnknownLogError if there is no verifier for that
+// log.
+func (v LogVerifiers) VerifySTH(sth SignedTreeHead) error {
+	sv, ok := v[sth.LogID]
+	if !ok {
+		return UnknownLogError{LogID: sth.LogID}
+	}
+	return sv.VerifySTHSignature(sth)
+}
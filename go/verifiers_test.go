@@ -0,0 +1,103 @@
+package ct
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/certificate-transparency/go/testonly"
+)
+
+func TestLogVerifiersAddPEM(t *testing.T) {
+	v := make(LogVerifiers)
+	if err := v.AddPEM([]byte(sigTestEC256PublicKeyPEM + sigTestRSAPublicKeyPEM)); err != nil {
+		t.Fatalf("AddPEM()=%v", err)
+	}
+	if len(v) != 2 {
+		t.Errorf("AddPEM() of two keys added %d verifiers", len(v))
+	}
+	if err := v.AddPEM([]byte("not PEM")); err == nil {
+		t.Error("AddPEM() of garbage succeeded, want error")
+	}
+	if err := v.AddPEMFile(testonly.Path("no-such-file.pem")); err == nil {
+		t.Error("AddPEMFile() of missing file succeeded, want error")
+	}
+}
+
+func TestLogVerifiersAddPEMDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logkeys")
+	if err != nil {
+		t.Fatalf("TempDir()=%v", err)
+	}
+	defer os.RemoveAll(dir)
+	for name, data := range map[string]string{
+		"ec.pem":     sigTestEC256PublicKeyPEM,
+		"more.pem":   sigTestEC256PublicKey2PEM + sigTestRSAPublicKeyPEM,
+		"README.txt": "not a key",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatalf("WriteFile()=%v", err)
+		}
+	}
+	v := make(LogVerifiers)
+	if err := v.AddPEMDir(dir); err != nil {
+		t.Fatalf("AddPEMDir()=%v", err)
+	}
+	if len(v) != 3 {
+		t.Errorf("AddPEMDir() added %d verifiers, want 3", len(v))
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "bad.pem"), []byte("not a key"), 0644); err != nil {
+		t.Fatalf("WriteFile()=%v", err)
+	}
+	if err := v.AddPEMDir(dir); err == nil {
+		t.Error("AddPEMDir() with bad PEM file succeeded, want error")
+	}
+}
+
+func testVerifiers(t *testing.T) (LogVerifiers, SHA256Hash) {
+	v := make(LogVerifiers)
+	if err := v.AddPEM([]byte(sigTestEC256PublicKeyPEM)); err != nil {
+		t.Fatalf("AddPEM()=%v", err)
+	}
+	_, id, _, err := PublicKeyFromPEM([]byte(sigTestEC256PublicKeyPEM))
+	if err != nil {
+		t.Fatalf("PublicKeyFromPEM()=%v", err)
+	}
+	return v, id
+}
+
+func TestLogVerifiersVerifySCT(t *testing.T) {
+	v, _ := testVerifiers(t)
+	sct := sigTestSCTEC(t)
+	entry := sigTestCertLogEntry(t)
+	if err := v.VerifySCT(sct, entry); err != nil {
+		t.Errorf("VerifySCT()=%v", err)
+	}
+	sct.Timestamp++
+	if err := v.VerifySCT(sct, entry); err == nil {
+		t.Error("VerifySCT() with wrong timestamp succeeded, want error")
+	}
+	sct.LogID[0] ^= 1
+	if err, ok := v.VerifySCT(sct, entry).(UnknownLogError); !ok || err.LogID != sct.LogID {
+		t.Errorf("VerifySCT() from unknown log=%v, want UnknownLogError", err)
+	}
+}
+
+func TestLogVerifiersVerifySTH(t *testing.T) {
+	v, id := testVerifiers(t)
+	sth := sigTestDefaultSTH(t)
+	sth.LogID = id
+	if err := v.VerifySTH(sth); err != nil {
+		t.Errorf("VerifySTH()=%v", err)
+	}
+	sth.TreeSize++
+	if err := v.VerifySTH(sth); err == nil {
+		t.Error("VerifySTH() with wrong tree size succeeded, want error")
+	}
+	sth.LogID[0] ^= 1
+	if err, ok := v.VerifySTH(sth).(UnknownLogError); !ok || err.LogID != sth.LogID {
+		t.Errorf("VerifySTH() from unknown log=%v, want UnknownLogError", err)
+	}
+}
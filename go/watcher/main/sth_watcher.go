@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/certificate-transparency/go/client"
+	"github.com/google/certificate-transparency/go/watcher"
+	"golang.org/x/net/context"
+)
+
+var logURI = flag.String("log_uri", "http://ct.googleapis.com/aviator", "CT log base URI")
+var logPubKey = flag.String("log_public_key", "", "File containing the log's public key in PEM format")
+var sthFile = flag.String("sth_file", "", "File in which to keep the last trusted STH")
+var pollInterval = flag.Duration("poll_interval", time.Minute, "Interval at which to fetch the log's STH")
+
+func main() {
+	flag.Parse()
+	if len(*logPubKey) == 0 || len(*sthFile) == 0 {
+		log.Fatal("--log_public_key and --sth_file are required")
+	}
+	pem, err := ioutil.ReadFile(*logPubKey)
+	if err != nil {
+		log.Fatalf("Failed to read log public key: %v", err)
+	}
+	c, err := client.NewWithPubKey(*logURI, &http.Client{}, string(pem))
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+	w := watcher.New(*logURI, c, watcher.NewFileStore(*sthFile), func(a watcher.Alert) {
+		log.Printf("ALERT: %s", a)
+	})
+	log.Printf("Watching STHs of %s", *logURI)
+	w.Run(context.Background(), *pollInterval)
+}
@@ -0,0 +1,255 @@
+// Package watcher monitors a CT log's signed tree heads. A Watcher polls the
+// log's get-sth endpoint, checks that each new STH is consistent with the last
+// one it trusted, and raises an Alert if the log presents a view of its tree
+// which can't be reconciled with the STHs it has issued before.
+package watcher
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+	"github.com/google/certificate-transparency/go/merkletree"
+	"golang.org/x/net/context"
+)
+
+// AlertType describes the way in which a log misbehaved.
+type AlertType int
+
+// AlertType constants
+const (
+	// InvalidSignature means the STH's signature didn't verify.
+	InvalidSignature AlertType = iota
+	// ConsistencyProofFailed means the log's consistency proof didn't show
+	// the new STH's tree to be an extension of the trusted one.
+	ConsistencyProofFailed
+	// TreeSizeShrank means the new STH is for a smaller tree than the
+	// trusted one.
+	TreeSizeShrank
+	// TimestampWentBackwards means the new STH is older than the trusted one.
+	TimestampWentBackwards
+	// ConflictingRoots means the new STH has the same tree size as the
+	// trusted one, but a different root hash.
+	ConflictingRoots
+)
+
+func (a AlertType) String() string {
+	switch a {
+	case InvalidSignature:
+		return "InvalidSignature"
+	case ConsistencyProofFailed:
+		return "ConsistencyProofFailed"
+	case TreeSizeShrank:
+		return "TreeSizeShrank"
+	case TimestampWentBackwards:
+		return "TimestampWentBackwards"
+	case ConflictingRoots:
+		return "ConflictingRoots"
+	default:
+		return fmt.Sprintf("UnknownAlertType(%d)", a)
+	}
+}
+
+// Alert describes an STH which the log should not have issued.
+type Alert struct {
+	Type AlertType
+	// LogURI is the base URI of the log.
+	LogURI string
+	// Trusted is the last STH accepted from the log, if any.
+	Trusted *ct.SignedTreeHead
+	// Received is the offending STH. It is nil for InvalidSignature alerts.
+	Received *ct.SignedTreeHead
+	// Proof is the consistency proof between Trusted and Received, for
+	// ConsistencyProofFailed alerts.
+	Proof [][]byte
+	// Err gives details of the failure.
+	Err error
+}
+
+func (a Alert) String() string {
+	desc := func(sth *ct.SignedTreeHead) string {
+		if sth == nil {
+			return "none"
+		}
+		return fmt.Sprintf("{TreeSize:%d Timestamp:%d Root:%s}", sth.TreeSize, sth.Timestamp, sth.SHA256RootHash.Base64String())
+	}
+	return fmt.Sprintf("%s from %s: trusted STH %s, received STH %s: %v", a.Type, a.LogURI, desc(a.Trusted), desc(a.Received), a.Err)
+}
+
+// STHStore persists the STHs which a Watcher has accepted.
+// server.Storage implementations satisfy this interface.
+type STHStore interface {
+	// AddSTH records |sth| as the latest trusted STH.
+	AddSTH(sth *ct.SignedTreeHead) error
+	// LatestSTH returns the most recently added STH, or nil if there isn't
+	// one.
+	LatestSTH() (*ct.SignedTreeHead, error)
+}
+
+// Watcher checks the STHs issued by a single log.
+type Watcher struct {
+	uri      string
+	client   *client.LogClient
+	store    STHStore
+	alert    func(Alert)
+	verifier merkletree.MerkleVerifier
+}
+
+func sha256Hash(b []byte) []byte {
+	h := sha256.Sum256(b)
+	return h[:]
+}
+
+// New returns a Watcher which fetches STHs for the log at |uri| using |c|,
+// stores those it accepts in |store|, and passes any Alerts to |alert|.
+// |c| must have been created with the log's public key, so that it verifies
+// the signatures on the STHs. If |store| is empty the first STH fetched is
+// trusted.
+func New(uri string, c *client.LogClient, store STHStore, alert func(Alert)) *Watcher {
+	return &Watcher{
+		uri:      uri,
+		client:   c,
+		store:    store,
+		alert:    alert,
+		verifier: merkletree.NewMerkleVerifier(sha256Hash),
+	}
+}
+
+// Check fetches the log's latest STH and checks it against the last trusted
+// STH. If it is consistent with, and newer than, the trusted STH it is stored
+// as the new trusted STH; if it shows the log to have misbehaved an Alert is
+// raised, and the trusted STH is left unchanged.
+// Returns an error if the check couldn't be completed, e.g. because the log
+// couldn't be reached.
+func (w *Watcher) Check(ctx context.Context) error {
+	trusted, err := w.store.LatestSTH()
+	if err != nil {
+		return fmt.Errorf("failed to get trusted STH: %v", err)
+	}
+	sth, err := w.client.GetSTHWithContext(ctx)
+	if err != nil {
+		if _, ok := err.(client.SignatureVerificationError); ok {
+			w.raise(Alert{Type: InvalidSignature, Trusted: trusted, Err: err})
+			return nil
+		}
+		return fmt.Errorf("failed to get STH: %v", err)
+	}
+	if trusted == nil {
+		return w.accept(sth)
+	}
+
+	switch {
+	case sth.TreeSize < trusted.TreeSize:
+		w.raise(Alert{Type: TreeSizeShrank, Trusted: trusted, Received: sth,
+			Err: fmt.Errorf("tree size went from %d to %d", trusted.TreeSize, sth.TreeSize)})
+		return nil
+	case sth.TreeSize == trusted.TreeSize && sth.SHA256RootHash != trusted.SHA256RootHash:
+		w.raise(Alert{Type: ConflictingRoots, Trusted: trusted, Received: sth,
+			Err: fmt.Errorf("tree of size %d has two roots", sth.TreeSize)})
+		return nil
+	case sth.Timestamp < trusted.Timestamp:
+		w.raise(Alert{Type: TimestampWentBackwards, Trusted: trusted, Received: sth,
+			Err: fmt.Errorf("timestamp went from %d to %d", trusted.Timestamp, sth.Timestamp)})
+		return nil
+	case sth.TreeSize == trusted.TreeSize:
+		// Nothing new, though the log may have re-signed the same tree.
+		if sth.Timestamp > trusted.Timestamp {
+			return w.accept(sth)
+		}
+		return nil
+	}
+
+	var proof [][]byte
+	if trusted.TreeSize > 0 {
+		if proof, err = w.client.GetSTHConsistency(ctx, trusted.TreeSize, sth.TreeSize); err != nil {
+			return fmt.Errorf("failed to get consistency proof from %d to %d: %v", trusted.TreeSize, sth.TreeSize, err)
+		}
+	}
+	if err := w.verifier.VerifyConsistencyProof(int64(trusted.TreeSize), int64(sth.TreeSize), trusted.SHA256RootHash[:], sth.SHA256RootHash[:], proof); err != nil {
+		w.raise(Alert{Type: ConsistencyProofFailed, Trusted: trusted, Received: sth, Proof: proof, Err: err})
+		return nil
+	}
+	return w.accept(sth)
+}
+
+func (w *Watcher) accept(sth *ct.SignedTreeHead) error {
+	if err := w.store.AddSTH(sth); err != nil {
+		return fmt.Errorf("failed to store STH: %v", err)
+	}
+	return nil
+}
+
+func (w *Watcher) raise(a Alert) {
+	a.LogURI = w.uri
+	w.alert(a)
+}
+
+// Run calls Check every |interval| until |ctx| is done. Errors from Check are
+// logged, and the check is retried at the next interval.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := w.Check(ctx); err != nil {
+			log.Printf("Checking STH of %s failed: %v", w.uri, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// FileStore is an STHStore which keeps the latest STH as JSON in a file.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore returns a FileStore which keeps the STH in the file at |path|.
+// The file needn't exist until the first STH is added.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// AddSTH implements STHStore. The file is replaced atomically, so a crash
+// can't leave it holding a partial STH.
+func (f *FileStore) AddSTH(sth *ct.SignedTreeHead) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, err := json.Marshal(sth)
+	if err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// LatestSTH implements STHStore.
+func (f *FileStore) LatestSTH() (*ct.SignedTreeHead, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var sth ct.SignedTreeHead
+	if err := json.Unmarshal(data, &sth); err != nil {
+		return nil, fmt.Errorf("failed to parse STH in %s: %v", f.path, err)
+	}
+	return &sth, nil
+}
@@ -0,0 +1,187 @@
+package watcher
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency/go"
+	"github.com/google/certificate-transparency/go/client"
+	"github.com/google/certificate-transparency/go/ctfake"
+	"github.com/google/certificate-transparency/go/testonly"
+	"golang.org/x/net/context"
+)
+
+// memoryStore is an STHStore which remembers every STH added to it.
+type memoryStore struct {
+	sths []*ct.SignedTreeHead
+}
+
+func (m *memoryStore) AddSTH(sth *ct.SignedTreeHead) error {
+	m.sths = append(m.sths, sth)
+	return nil
+}
+
+func (m *memoryStore) LatestSTH() (*ct.SignedTreeHead, error) {
+	if len(m.sths) == 0 {
+		return nil, nil
+	}
+	return m.sths[len(m.sths)-1], nil
+}
+
+type testEnv struct {
+	log     *ctfake.Log
+	now     time.Time
+	store   *memoryStore
+	alerts  []Alert
+	watcher *Watcher
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	e := &testEnv{now: time.Unix(1500000000, 0), store: &memoryStore{}}
+	l, err := ctfake.NewLog(ctfake.Config{
+		Roots: []ct.ASN1Cert{testonly.ReadPEM(t, "ca-cert.pem")},
+		Now:   func() time.Time { return e.now },
+	})
+	if err != nil {
+		t.Fatalf("NewLog()=%v", err)
+	}
+	e.log = l
+	c, err := client.NewWithPubKey(l.URL(), &http.Client{}, l.PublicKeyPEM())
+	if err != nil {
+		l.Close()
+		t.Fatalf("NewWithPubKey()=%v", err)
+	}
+	e.watcher = New(l.URL(), c, e.store, func(a Alert) { e.alerts = append(e.alerts, a) })
+	return e
+}
+
+func (e *testEnv) add(t *testing.T, name string) {
+	if _, err := e.log.AddChain([]ct.ASN1Cert{testonly.ReadPEM(t, name)}); err != nil {
+		t.Fatalf("AddChain(%s)=%v", name, err)
+	}
+	e.now = e.now.Add(time.Minute)
+}
+
+// check runs the watcher once, and returns the types of any alerts raised.
+func (e *testEnv) check(t *testing.T) []AlertType {
+	e.alerts = nil
+	if err := e.watcher.Check(context.Background()); err != nil {
+		t.Fatalf("Check()=%v", err)
+	}
+	var types []AlertType
+	for _, a := range e.alerts {
+		if a.LogURI != e.log.URL() {
+			t.Errorf("alert has LogURI %q, want %q", a.LogURI, e.log.URL())
+		}
+		types = append(types, a.Type)
+	}
+	return types
+}
+
+func TestGrowingLog(t *testing.T) {
+	e := newTestEnv(t)
+	defer e.log.Close()
+	for _, name := range []string{"", "test-cert.pem", "test-embedded-cert.pem", "", "intermediate-cert.pem"} {
+		if name != "" {
+			e.add(t, name)
+		}
+		if alerts := e.check(t); len(alerts) != 0 {
+			t.Fatalf("Check() raised %v", alerts)
+		}
+		sth, _ := e.store.LatestSTH()
+		if sth == nil || sth.TreeSize != e.log.TreeSize() {
+			t.Fatalf("trusted STH is %v, want one of size %d", sth, e.log.TreeSize())
+		}
+	}
+	// The same tree re-signed at the same time isn't stored again.
+	n := len(e.store.sths)
+	if alerts := e.check(t); len(alerts) != 0 {
+		t.Fatalf("Check() raised %v", alerts)
+	}
+	if len(e.store.sths) != n {
+		t.Errorf("STH for unchanged tree was stored")
+	}
+}
+
+func TestMisbehavingLog(t *testing.T) {
+	tests := []struct {
+		desc   string
+		tamper func(t *testing.T, e *testEnv)
+		want   AlertType
+	}{
+		{"bad signature", func(t *testing.T, e *testEnv) {
+			e.log.SetMisbehaviour(ctfake.Misbehaviour{BadSignatures: true})
+		}, InvalidSignature},
+		{"fork at same size", func(t *testing.T, e *testEnv) {
+			e.log.SetMisbehaviour(ctfake.Misbehaviour{ForkSTH: true})
+		}, ConflictingRoots},
+		{"fork after growth", func(t *testing.T, e *testEnv) {
+			e.add(t, "intermediate-cert.pem")
+			e.log.SetMisbehaviour(ctfake.Misbehaviour{ForkSTH: true})
+		}, ConsistencyProofFailed},
+		{"timestamp went backwards", func(t *testing.T, e *testEnv) {
+			e.add(t, "intermediate-cert.pem")
+			e.now = e.now.Add(-time.Hour)
+		}, TimestampWentBackwards},
+		{"tree shrank", func(t *testing.T, e *testEnv) {
+			trusted := *e.store.sths[0]
+			trusted.TreeSize = 100
+			e.store.AddSTH(&trusted)
+		}, TreeSizeShrank},
+	}
+	for _, test := range tests {
+		e := newTestEnv(t)
+		e.add(t, "test-cert.pem")
+		e.add(t, "test-embedded-cert.pem")
+		if alerts := e.check(t); len(alerts) != 0 {
+			t.Fatalf("%s: Check() raised %v before tampering", test.desc, alerts)
+		}
+		test.tamper(t, e)
+		trusted, _ := e.store.LatestSTH()
+
+		if got, want := e.check(t), []AlertType{test.want}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Check() raised %v, want %v", test.desc, got, want)
+		} else if e.alerts[0].Trusted != trusted {
+			t.Errorf("%s: alert has trusted STH %v, want %v", test.desc, e.alerts[0].Trusted, trusted)
+		}
+		if latest, _ := e.store.LatestSTH(); latest != trusted {
+			t.Errorf("%s: bad STH was stored", test.desc)
+		}
+		e.log.Close()
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watcher_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := NewFileStore(filepath.Join(dir, "sth.json"))
+	if sth, err := f.LatestSTH(); sth != nil || err != nil {
+		t.Errorf("LatestSTH()=%v, %v; want nil", sth, err)
+	}
+	sth := &ct.SignedTreeHead{
+		Version:           ct.V1,
+		TreeSize:          42,
+		Timestamp:         1234,
+		TreeHeadSignature: ct.DigitallySigned{HashAlgorithm: ct.SHA256, SignatureAlgorithm: ct.ECDSA, Signature: []byte("sig")},
+	}
+	sth.SHA256RootHash[0] = 1
+	sth.LogID[0] = 2
+	if err := f.AddSTH(sth); err != nil {
+		t.Fatalf("AddSTH()=%v", err)
+	}
+	got, err := f.LatestSTH()
+	if err != nil {
+		t.Fatalf("LatestSTH()=%v", err)
+	}
+	if !reflect.DeepEqual(got, sth) {
+		t.Errorf("LatestSTH()=%+v, want %+v", got, sth)
+	}
+}
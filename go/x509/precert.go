@@ -0,0 +1,268 @@
+package x509
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/google/certificate-transparency/go/asn1"
+	"github.com/google/certificate-transparency/go/x509/pkix"
+)
+
+// OIDExtensionCTPoison is the OID of the critical "poison" extension which
+// marks a certificate as a CT Precertificate (RFC6962 section 3.1).
+var OIDExtensionCTPoison = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 3}
+
+// OIDExtensionCTSCTList is the OID of the extension which carries the SCTs
+// embedded in a final certificate (RFC6962 section 3.3).
+var OIDExtensionCTSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+
+// OIDExtKeyUsageCertificateTransparency is the extended key usage which marks
+// a certificate as a Precertificate Signing Certificate (RFC6962 section 3.1).
+var OIDExtKeyUsageCertificateTransparency = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 4}
+
+// ASN.1 class and tag values used when picking apart a TBSCertificate.
+const (
+	classUniversal       = 0
+	classContextSpecific = 2
+	tagSequence          = 16
+	tbsVersionTag        = 0
+	tbsExtensionsTag     = 3
+)
+
+// IsPrecertificate returns true if the certificate contains the CT poison
+// extension.
+func (c *Certificate) IsPrecertificate() bool {
+	return oidInExtensions(OIDExtensionCTPoison, c.Extensions)
+}
+
+// IsPrecertificateSigningCert returns true if the certificate carries the
+// Certificate Transparency extended key usage, i.e. it is a special purpose
+// certificate used only to sign Precertificates on behalf of its issuer.
+func (c *Certificate) IsPrecertificateSigningCert() bool {
+	for _, oid := range c.UnknownExtKeyUsage {
+		if oid.Equal(OIDExtKeyUsageCertificateTransparency) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildPrecertTBS returns the DER encoded TBSCertificate which a CT log signs
+// over when it issues an SCT for a Precertificate, given the DER encoded
+// TBSCertificate |tbsData| of the Precertificate itself.
+// The CT poison extension is removed and, if the Precertificate was issued by
+// the Precertificate Signing Certificate |preIssuer| (which may be nil), the
+// issuer and authority key identifier are replaced with those of |preIssuer|,
+// so that they match the final certificate. All other fields are left byte
+// for byte as they were.
+func BuildPrecertTBS(tbsData []byte, preIssuer *Certificate) ([]byte, error) {
+	fields, issuerIndex, err := splitTBS(tbsData)
+	if err != nil {
+		return nil, err
+	}
+	if preIssuer != nil {
+		fields[issuerIndex].FullBytes = preIssuer.RawIssuer
+	}
+	return joinTBS(fields, func(data []byte) ([]byte, error) {
+		return buildPrecertExtensions(data, preIssuer)
+	})
+}
+
+// RemoveSCTList returns the DER encoded TBSCertificate |tbsData| of a final
+// certificate with its embedded SCT list extension removed. This is the
+// TBSCertificate which the logs signed over when they issued the embedded
+// SCTs for the corresponding Precertificate. All other fields are left byte
+// for byte as they were.
+func RemoveSCTList(tbsData []byte) ([]byte, error) {
+	fields, _, err := splitTBS(tbsData)
+	if err != nil {
+		return nil, err
+	}
+	return joinTBS(fields, removeSCTListExtension)
+}
+
+// splitTBS splits the DER encoded TBSCertificate |tbsData| into its fields,
+// and returns them along with the index of the issuer field.
+func splitTBS(tbsData []byte) ([]asn1.RawValue, int, error) {
+	var tbs asn1.RawValue
+	if rest, err := asn1.Unmarshal(tbsData, &tbs); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse TBSCertificate: %v", err)
+	} else if len(rest) > 0 {
+		return nil, 0, errors.New("trailing data after TBSCertificate")
+	}
+	if tbs.Class != classUniversal || tbs.Tag != tagSequence {
+		return nil, 0, fmt.Errorf("TBSCertificate is not a SEQUENCE (class %d, tag %d)", tbs.Class, tbs.Tag)
+	}
+	fields, err := splitRawValues(tbs.Bytes)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to parse TBSCertificate: %v", err)
+	}
+
+	issuerIndex := 2
+	if len(fields) > 0 && fields[0].Class == classContextSpecific && fields[0].Tag == tbsVersionTag {
+		issuerIndex++
+	}
+	if len(fields) <= issuerIndex {
+		return nil, 0, errors.New("TBSCertificate is truncated")
+	}
+	return fields, issuerIndex, nil
+}
+
+// joinTBS reassembles the TBSCertificate |fields| into a DER encoded
+// TBSCertificate, passing the contents of the extensions field through
+// |rewriteExtensions|, which returns the complete re-tagged field (or nil to
+// drop it).
+func joinTBS(fields []asn1.RawValue, rewriteExtensions func([]byte) ([]byte, error)) ([]byte, error) {
+	var out bytes.Buffer
+	for _, f := range fields {
+		if f.Class == classContextSpecific && f.Tag == tbsExtensionsTag {
+			exts, err := rewriteExtensions(f.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			if exts != nil {
+				out.Write(exts)
+			}
+			continue
+		}
+		out.Write(f.FullBytes)
+	}
+	return asn1.Marshal(asn1.RawValue{Class: classUniversal, Tag: tagSequence, IsCompound: true, Bytes: out.Bytes()})
+}
+
+// splitExtensions splits the contents |data| of the explicitly tagged
+// extensions field of a TBSCertificate into the individual extensions.
+func splitExtensions(data []byte) ([]asn1.RawValue, error) {
+	var seq asn1.RawValue
+	if rest, err := asn1.Unmarshal(data, &seq); err != nil {
+		return nil, fmt.Errorf("failed to parse extensions: %v", err)
+	} else if len(rest) > 0 {
+		return nil, errors.New("trailing data after extensions")
+	}
+	exts, err := splitRawValues(seq.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse extensions: %v", err)
+	}
+	return exts, nil
+}
+
+// joinExtensions wraps the concatenated DER encoded extensions |exts| back up
+// into an explicitly tagged extensions field.
+// Returns nil if |exts| is empty.
+func joinExtensions(exts []byte) ([]byte, error) {
+	if len(exts) == 0 {
+		return nil, nil
+	}
+	seqBytes, err := asn1.Marshal(asn1.RawValue{Class: classUniversal, Tag: tagSequence, IsCompound: true, Bytes: exts})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(asn1.RawValue{Class: classContextSpecific, Tag: tbsExtensionsTag, IsCompound: true, Bytes: seqBytes})
+}
+
+// buildPrecertExtensions rewrites the contents |data| of the explicitly tagged
+// extensions field of a Precertificate's TBSCertificate as described in
+// BuildPrecertTBS, and returns the complete re-tagged field.
+// Returns nil if no extensions remain.
+func buildPrecertExtensions(data []byte, preIssuer *Certificate) ([]byte, error) {
+	exts, err := splitExtensions(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	foundPoison := false
+	for _, e := range exts {
+		var ext pkix.Extension
+		if _, err := asn1.Unmarshal(e.FullBytes, &ext); err != nil {
+			return nil, fmt.Errorf("failed to parse extension: %v", err)
+		}
+		switch {
+		case ext.Id.Equal(OIDExtensionCTPoison):
+			if foundPoison {
+				return nil, errors.New("multiple CT poison extensions present")
+			}
+			foundPoison = true
+			continue
+		case preIssuer != nil && ext.Id.Equal(oidExtensionAuthorityKeyId):
+			// The final certificate will carry the key identifier of the real
+			// issuer, which is what the Precertificate Signing Certificate's
+			// own AKI refers to.
+			aki := findExtension(oidExtensionAuthorityKeyId, preIssuer.Extensions)
+			if aki == nil {
+				continue
+			}
+			b, err := asn1.Marshal(*aki)
+			if err != nil {
+				return nil, err
+			}
+			out.Write(b)
+			continue
+		}
+		out.Write(e.FullBytes)
+	}
+	if !foundPoison {
+		return nil, errors.New("no CT poison extension present")
+	}
+	return joinExtensions(out.Bytes())
+}
+
+// removeSCTListExtension drops the embedded SCT list from the contents |data|
+// of the explicitly tagged extensions field of a final certificate's
+// TBSCertificate, and returns the complete re-tagged field.
+// Returns nil if no extensions remain.
+func removeSCTListExtension(data []byte) ([]byte, error) {
+	exts, err := splitExtensions(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	foundSCTList := false
+	for _, e := range exts {
+		var ext pkix.Extension
+		if _, err := asn1.Unmarshal(e.FullBytes, &ext); err != nil {
+			return nil, fmt.Errorf("failed to parse extension: %v", err)
+		}
+		if ext.Id.Equal(OIDExtensionCTSCTList) {
+			if foundSCTList {
+				return nil, errors.New("multiple SCT list extensions present")
+			}
+			foundSCTList = true
+			continue
+		}
+		out.Write(e.FullBytes)
+	}
+	if !foundSCTList {
+		return nil, errors.New("no SCT list extension present")
+	}
+	return joinExtensions(out.Bytes())
+}
+
+// splitRawValues splits the concatenated DER elements in |data| into their
+// individual RawValues.
+func splitRawValues(data []byte) ([]asn1.RawValue, error) {
+	var ret []asn1.RawValue
+	for len(data) > 0 {
+		var v asn1.RawValue
+		rest, err := asn1.Unmarshal(data, &v)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, v)
+		data = rest
+	}
+	return ret, nil
+}
+
+// findExtension returns the extension with the given |oid| from |extensions|,
+// or nil if there isn't one.
+func findExtension(oid asn1.ObjectIdentifier, extensions []pkix.Extension) *pkix.Extension {
+	for i := range extensions {
+		if extensions[i].Id.Equal(oid) {
+			return &extensions[i]
+		}
+	}
+	return nil
+}
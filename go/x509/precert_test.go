@@ -0,0 +1,141 @@
+package x509
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/google/certificate-transparency/go/asn1"
+	"github.com/google/certificate-transparency/go/testonly"
+	"github.com/google/certificate-transparency/go/x509/pkix"
+)
+
+func readTestCert(t *testing.T, name string) *Certificate {
+	cert, err := ParseCertificate(testonly.ReadPEM(t, name))
+	if IsFatal(err) {
+		t.Fatalf("Failed to parse %s: %v", name, err)
+	}
+	return cert
+}
+
+func withoutExtension(oid asn1.ObjectIdentifier, exts []pkix.Extension) []pkix.Extension {
+	var ret []pkix.Extension
+	for _, e := range exts {
+		if !e.Id.Equal(oid) {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+func TestPrecertificateDetection(t *testing.T) {
+	for _, test := range []struct {
+		name           string
+		precert, preCA bool
+	}{
+		{"test-cert.pem", false, false},
+		{"test-embedded-pre-cert.pem", true, false},
+		{"ca-cert.pem", false, false},
+		{"ca-pre-cert.pem", false, true},
+		{"intermediate-pre-cert.pem", false, true},
+	} {
+		cert := readTestCert(t, test.name)
+		if got := cert.IsPrecertificate(); got != test.precert {
+			t.Errorf("%s: IsPrecertificate()=%v, want %v", test.name, got, test.precert)
+		}
+		if got := cert.IsPrecertificateSigningCert(); got != test.preCA {
+			t.Errorf("%s: IsPrecertificateSigningCert()=%v, want %v", test.name, got, test.preCA)
+		}
+	}
+}
+
+// Checks that the TBSCertificate built from each precertificate matches the
+// corresponding final certificate, bar the embedded SCT list.
+func TestBuildPrecertTBS(t *testing.T) {
+	for _, test := range []struct {
+		precert, preIssuer, final string
+	}{
+		{"test-embedded-pre-cert.pem", "", "test-embedded-cert.pem"},
+		{"test-embedded-with-preca-pre-cert.pem", "ca-pre-cert.pem", "test-embedded-with-preca-cert.pem"},
+		{"test-embedded-with-intermediate-pre-cert.pem", "", "test-embedded-with-intermediate-cert.pem"},
+		{"test-embedded-with-intermediate-preca-pre-cert.pem", "intermediate-pre-cert.pem", "test-embedded-with-intermediate-preca-cert.pem"},
+	} {
+		precert := readTestCert(t, test.precert)
+		var preIssuer *Certificate
+		if test.preIssuer != "" {
+			preIssuer = readTestCert(t, test.preIssuer)
+		}
+		final := readTestCert(t, test.final)
+
+		tbs, err := BuildPrecertTBS(precert.RawTBSCertificate, preIssuer)
+		if err != nil {
+			t.Errorf("%s: BuildPrecertTBS()=%v", test.precert, err)
+			continue
+		}
+		got, err := ParseTBSCertificate(tbs)
+		if err != nil {
+			t.Errorf("%s: failed to parse built TBSCertificate: %v", test.precert, err)
+			continue
+		}
+		if got.IsPrecertificate() {
+			t.Errorf("%s: built TBSCertificate still contains the poison extension", test.precert)
+		}
+		if !bytes.Equal(got.RawIssuer, final.RawIssuer) {
+			t.Errorf("%s: built TBSCertificate has issuer %v, want %v", test.precert, got.Issuer, final.Issuer)
+		}
+		if !bytes.Equal(got.RawSubjectPublicKeyInfo, final.RawSubjectPublicKeyInfo) {
+			t.Errorf("%s: built TBSCertificate has a different public key to the final certificate", test.precert)
+		}
+		if want := withoutExtension(OIDExtensionCTSCTList, final.Extensions); !reflect.DeepEqual(got.Extensions, want) {
+			t.Errorf("%s: built TBSCertificate has extensions %v, want %v", test.precert, got.Extensions, want)
+		}
+	}
+}
+
+func TestBuildPrecertTBSWithoutPoison(t *testing.T) {
+	cert := readTestCert(t, "test-cert.pem")
+	if _, err := BuildPrecertTBS(cert.RawTBSCertificate, nil); err == nil {
+		t.Fatal("BuildPrecertTBS() of certificate without poison succeeded, want error")
+	}
+	if _, err := BuildPrecertTBS([]byte{0x30, 0x03, 0x02, 0x01}, nil); err == nil {
+		t.Fatal("BuildPrecertTBS() of truncated data succeeded, want error")
+	}
+}
+
+// Checks that removing the SCT list from each final certificate gives the
+// TBSCertificate built from the corresponding precertificate.
+func TestRemoveSCTList(t *testing.T) {
+	for _, test := range []struct {
+		final, precert, preIssuer string
+	}{
+		{"test-embedded-cert.pem", "test-embedded-pre-cert.pem", ""},
+		{"test-embedded-with-preca-cert.pem", "test-embedded-with-preca-pre-cert.pem", "ca-pre-cert.pem"},
+		{"test-embedded-with-intermediate-cert.pem", "test-embedded-with-intermediate-pre-cert.pem", ""},
+	} {
+		final := readTestCert(t, test.final)
+		precert := readTestCert(t, test.precert)
+		var preIssuer *Certificate
+		if test.preIssuer != "" {
+			preIssuer = readTestCert(t, test.preIssuer)
+		}
+		want, err := BuildPrecertTBS(precert.RawTBSCertificate, preIssuer)
+		if err != nil {
+			t.Fatalf("%s: BuildPrecertTBS()=%v", test.precert, err)
+		}
+		got, err := RemoveSCTList(final.RawTBSCertificate)
+		if err != nil {
+			t.Errorf("%s: RemoveSCTList()=%v", test.final, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: RemoveSCTList() doesn't match the TBSCertificate built from %s", test.final, test.precert)
+		}
+	}
+}
+
+func TestRemoveSCTListWithoutSCTs(t *testing.T) {
+	cert := readTestCert(t, "test-cert.pem")
+	if _, err := RemoveSCTList(cert.RawTBSCertificate); err == nil {
+		t.Fatal("RemoveSCTList() of certificate without SCT list succeeded, want error")
+	}
+}
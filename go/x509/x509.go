@@ -835,6 +835,17 @@ func (e *NonFatalErrors) HasError() bool {
 	return len(e.Errors) > 0
 }
 
+// IsFatal returns true if |err|, returned when parsing a certificate, means
+// that the certificate could not be parsed; that is, if it is neither nil nor
+// a NonFatalErrors.
+func IsFatal(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.(NonFatalErrors)
+	return !ok
+}
+
 // END CT CHANGES
 
 func parseCertificate(in *certificate) (*Certificate, error) {